@@ -1,30 +1,231 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"go-offline-test/internal/audit"
+	"go-offline-test/internal/auth"
+	"go-offline-test/internal/filter"
+	"go-offline-test/internal/logging"
 	"go-offline-test/internal/repository"
 	"go-offline-test/internal/services"
+	"go-offline-test/internal/shared"
+	"go-offline-test/internal/shared/dto/config"
+	"go-offline-test/internal/tracing"
 	"go-offline-test/internal/transport"
-	"log"
+	"go-offline-test/internal/validation"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 )
 
 func main() {
-	// Для запуска без докер
-	// projectRoot, err := filepath.Abs("../")
-	// if err != nil {
-	// 	log.Fatal("Не удалось получить projectRoot:", err)
-	// }
-	//
-	// envPath := filepath.Join(projectRoot, ".env")
-	// if err := godotenv.Load(envPath); err != nil {
-	//	log.Fatal("Не удалось загрузить .env файл")
-	// }
-	// log.Printf("INFO: .env файл успешно загружен")
-
-	repo := repository.NewQuoteRepository()
-	log.Printf("INFO: слой репозитория успешно создан")
-	service := services.NewQuoteService(repo)
-	log.Printf("INFO: сервисный слой успешно создан")
-	controller := transport.NewController(service)
-	log.Printf("INFO: транспортный слой успешно создан")
-	transport.RunRouter(controller)
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "keys":
+			os.Exit(runKeys(os.Args[2:], os.Stdout, os.Stderr))
+		case "audit":
+			os.Exit(runAudit(os.Args[2:], os.Stdout, os.Stderr))
+		}
+	}
+
+	os.Exit(runServer(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// runServer - Собирает слои и обслуживает запросы до SIGTERM/SIGINT. Отложенные вызовы закрывают
+// журнал аудита и отправляют оставшиеся трассы уже после того, как завершились все запросы.
+func runServer(args []string, stdout, stderr io.Writer) int {
+	conf, opts, err := shared.LoadConfig("quotes", args, stderr)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		if shared.IsConfigError(err) {
+			fmt.Fprintln(stderr, err)
+		}
+		return 2
+	}
+	if opts.Print {
+		if err := shared.PrintConfig(stdout, conf); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		return 0
+	}
+
+	logger, err := logging.New(stderr, conf.Log.Format, conf.Log.Level, conf.Log.RedactQuotes)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	slog.SetDefault(logger)
+	if opts.File != "" {
+		slog.Info("настройки загружены из файла", "file", opts.File)
+	}
+
+	tracer, err := newTracer(&conf.Trace)
+	if err != nil {
+		return startupFailed(err)
+	}
+	tracing.SetDefault(tracer)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := tracer.Shutdown(ctx); err != nil {
+			slog.Warn("не удалось отправить оставшиеся трассы", "error", err)
+		}
+	}()
+
+	healthState := newHealth(&conf.Health, tracer)
+
+	tenants := repository.NewTenantRegistry(repository.Quota{
+		MaxQuotes:  conf.Tenants.DefaultMaxQuotes,
+		MaxAuthors: conf.Tenants.DefaultMaxAuthors,
+	})
+	slog.Info("слой репозитория успешно создан")
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Слушатели открываются до восстановления журнала: пока оно идёт, /readyz отвечает replaying.
+	replayDone := healthState.BeginReplay("audit")
+	tlsConfig, certReloader, err := newTLS(&conf.TLS)
+	if err != nil {
+		return startupFailed(err)
+	}
+	if certReloader != nil {
+		go certReloader.Watch(ctx, conf.TLS.ReloadInterval)
+	}
+	server, err := transport.Listen(conf, tlsConfig, healthState)
+	if err != nil {
+		return startupFailed(err)
+	}
+	defer server.Close()
+
+	auditLog, err := audit.NewLog(conf.Audit.File, conf.Audit.MaxEntries)
+	replayDone()
+	if err != nil {
+		return startupFailed(fmt.Errorf("не удалось открыть журнал аудита: %w", err))
+	}
+	defer func() {
+		if err := auditLog.Close(); err != nil {
+			slog.Error("не удалось закрыть журнал аудита", "error", err)
+		}
+	}()
+	if conf.Audit.File != "" {
+		slog.Info("журнал аудита успешно открыт", "file", conf.Audit.File)
+	}
+
+	rules, ruleErrs := validation.Load(conf.Validation)
+	if len(ruleErrs) > 0 {
+		return startupFailed(fmt.Errorf("не удалось загрузить правила проверки: %w", errors.Join(ruleErrs...)))
+	}
+	slog.Info("правила проверки загружены", "sets", rules.Sets())
+
+	filters, filterErrs := filter.Load(conf.Filter)
+	if len(filterErrs) > 0 {
+		return startupFailed(fmt.Errorf("не удалось собрать фильтры содержимого: %w", errors.Join(filterErrs...)))
+	}
+	slog.Info("фильтры содержимого собраны", "chain", filters.Filters())
+
+	service := services.NewQuoteService(tenants, auditLog, rules, filters, conf.Moderation)
+	tenantService := services.NewTenantService(tenants)
+	auditService := services.NewAuditService(auditLog)
+	configService := newConfigService(conf, args, service, tenants, tracer)
+	slog.Info("сервисный слой успешно создан")
+
+	certAuth, err := newCertAuth(&conf.TLS, &conf.Auth)
+	if err != nil {
+		return startupFailed(err)
+	}
+	authenticator, keys, err := newAuth(&conf.Auth, &conf.JWT, certAuth != nil)
+	if err != nil {
+		return startupFailed(err)
+	}
+
+	controller := transport.NewController(service, service, service, service, tenantService, auditService, configService, authenticator, certAuth, keys, newMetrics(tenants), healthState)
+	slog.Info("транспортный слой успешно создан")
+
+	watchReload(ctx, configService)
+
+	if err := server.Run(ctx, controller); err != nil {
+		slog.Error("сервер завершился с ошибкой", "error", err)
+		return 1
+	}
+	return 0
+}
+
+// newAuth - Собирает цепочку аутентификации по bearer-токену из включённых способов: API-ключи и JWT.
+// При mTLS цепочка может быть пустой - тогда клиенты проходят только по сертификату.
+func newAuth(conf *config.AuthConfig, jwtConf *config.JWTConfig, mtls bool) (auth.IAuthenticator, *auth.KeyStore, error) {
+	if !conf.Enabled {
+		return nil, nil, nil
+	}
+
+	var chain auth.Chain
+	var keys *auth.KeyStore
+	if conf.APIKeys {
+		var err error
+		keys, err = auth.NewKeyStore(conf.KeysFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("не удалось загрузить API-ключи: %w", err)
+		}
+		chain = append(chain, keys)
+		slog.Info("хранилище API-ключей успешно загружено", "file", conf.KeysFile)
+	}
+
+	if jwtConf.JWKS != "" {
+		jwks, err := auth.NewJWKS(context.Background(), jwtConf.JWKS, jwtConf.JWKSRefresh)
+		if err != nil {
+			return nil, nil, fmt.Errorf("не удалось загрузить JWKS: %w", err)
+		}
+
+		scopeMap := make(map[string][]auth.Scope, len(jwtConf.ScopeMap))
+		for value, raw := range jwtConf.ScopeMap {
+			scopes, err := auth.ParseScopes(raw)
+			if err != nil {
+				return nil, nil, fmt.Errorf("некорректное значение в jwt.scope_map: %s: %w", value, err)
+			}
+			scopeMap[value] = scopes
+		}
+
+		chain = append(chain, auth.NewJWTVerifier(jwks, auth.JWTOptions{
+			Issuer:     jwtConf.Issuer,
+			Audience:   jwtConf.Audience,
+			Leeway:     jwtConf.Leeway,
+			ScopeClaim: jwtConf.ScopeClaim,
+			ScopeMap:   scopeMap,
+		}))
+		slog.Info("проверка JWT включена", "jwks", jwtConf.JWKS)
+	}
+
+	if len(chain) == 0 {
+		if mtls {
+			return nil, nil, nil
+		}
+		return nil, nil, errors.New("авторизация включена, но не настроен ни один способ: включите API_KEYS_ENABLED, задайте JWT_JWKS или TLS_CLIENT_CA_FILE")
+	}
+	return chain, keys, nil
+}
+
+// startupFailed - Пишет ошибку запуска в лог и возвращает код выхода. Процесс завершается в main уже после
+// отложенных вызовов runServer, поэтому журнал аудита закрывается, а собранные трассы отправляются.
+func startupFailed(err error) int {
+	slog.Error("сервер не запущен", "error", err)
+	return 1
+}
+
+// subcommandConfig - Настройки для подкоманд: файл из CONFIG_FILE и переменные окружения.
+func subcommandConfig(stderr io.Writer) (*config.Config, bool) {
+	conf, _, err := shared.LoadConfig("", nil, stderr)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return nil, false
+	}
+	return conf, true
 }
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go-offline-test/internal/audit"
+	"io"
+)
+
+const auditUsage = `Журнал аудита:
+  audit verify [-file путь]   проверить цепочку хешей (по умолчанию audit.file из настроек)`
+
+// runAudit - CLI для проверки целостности файла аудита.
+func runAudit(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] != "verify" {
+		fmt.Fprintln(stderr, auditUsage)
+		return 2
+	}
+
+	conf, ok := subcommandConfig(stderr)
+	if !ok {
+		return 1
+	}
+
+	fs := flag.NewFlagSet("audit verify", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	file := fs.String("file", conf.Audit.File, "файл журнала аудита")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if *file == "" {
+		fmt.Fprintln(stderr, "не указан файл аудита: задайте -file или audit.file в настройках")
+		return 2
+	}
+
+	n, err := audit.VerifyFile(*file)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "OK: %d записей, цепочка хешей не нарушена\n", n)
+
+	return 0
+}
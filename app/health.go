@@ -0,0 +1,23 @@
+package main
+
+import (
+	"go-offline-test/internal/health"
+	"go-offline-test/internal/shared/dto/config"
+	"go-offline-test/internal/tracing"
+	"log/slog"
+)
+
+// newHealth - Регистрирует проверки готовности из настроек.
+func newHealth(conf *config.HealthConfig, tracer *tracing.Tracer) *health.Health {
+	h := health.New()
+
+	if conf.DiskPath != "" {
+		h.Register("disk", health.DiskCheck(conf.DiskPath, uint64(conf.DiskMinFreeMB)<<20))
+		slog.Info("проверка свободного места включена", "path", conf.DiskPath, "min_free_mb", conf.DiskMinFreeMB)
+	}
+	if tracer.Exporting() && conf.TraceQueueMax > 0 {
+		h.Register("trace_queue", health.QueueCheck(tracer.QueueLen, conf.TraceQueueMax))
+	}
+
+	return h
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"go-offline-test/internal/auth"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const keysUsage = `Управление API-ключами:
+  keys create -name NAME -scopes quotes:read,quotes:write [-expires 2026-12-31 | -ttl 720h]
+  keys list
+  keys revoke ID
+  keys rotate [-expires 2026-12-31 | -ttl 720h] ID
+
+Общий флаг: -file путь к файлу ключей (по умолчанию auth.keys_file из настроек)`
+
+// runKeys - CLI для управления ключами. Пишет напрямую в файл, который читает запущенный сервер.
+func runKeys(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, keysUsage)
+		return 2
+	}
+
+	conf, ok := subcommandConfig(stderr)
+	if !ok {
+		return 1
+	}
+
+	cmd, args := args[0], args[1:]
+	fs := flag.NewFlagSet("keys "+cmd, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	file := fs.String("file", conf.Auth.KeysFile, "файл с ключами")
+
+	var name, scopes, expires string
+	var ttl time.Duration
+	switch cmd {
+	case "create":
+		fs.StringVar(&name, "name", "", "имя ключа")
+		fs.StringVar(&scopes, "scopes", "", "права через запятую: quotes:read, quotes:write, admin")
+		fallthrough
+	case "rotate":
+		fs.StringVar(&expires, "expires", "", "дата истечения (RFC3339 или YYYY-MM-DD)")
+		fs.DurationVar(&ttl, "ttl", 0, "срок действия ключа, например 720h")
+	case "list", "revoke":
+	default:
+		fmt.Fprintln(stderr, keysUsage)
+		return 2
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	store, err := auth.NewKeyStore(*file)
+	if err != nil {
+		fmt.Fprintf(stderr, "не удалось открыть файл ключей: %v\n", err)
+		return 1
+	}
+
+	ctx := context.Background()
+	switch cmd {
+	case "create":
+		expiresAt, err := parseExpiry(expires, ttl)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 2
+		}
+		parsed, err := auth.ParseScopes(strings.Split(scopes, ","))
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 2
+		}
+		token, key, err := store.Create(ctx, name, parsed, expiresAt)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		fmt.Fprintf(stdout, "id:    %s\ntoken: %s\n", key.ID, token)
+		fmt.Fprintln(stderr, "Сохраните токен: повторно его получить нельзя.")
+
+	case "list":
+		keys, err := store.List(ctx)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		printKeys(stdout, keys)
+
+	case "revoke":
+		if fs.NArg() != 1 {
+			fmt.Fprintln(stderr, keysUsage)
+			return 2
+		}
+		if err := store.Revoke(ctx, fs.Arg(0)); err != nil {
+			fmt.Fprintln(stderr, err)
+			return keyExitCode(err)
+		}
+		fmt.Fprintf(stdout, "ключ %s отозван\n", fs.Arg(0))
+
+	case "rotate":
+		if fs.NArg() != 1 {
+			fmt.Fprintln(stderr, keysUsage)
+			return 2
+		}
+		expiresAt, err := parseExpiry(expires, ttl)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 2
+		}
+		token, key, err := store.Rotate(ctx, fs.Arg(0), expiresAt)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return keyExitCode(err)
+		}
+		fmt.Fprintf(stdout, "id:    %s\ntoken: %s\n", key.ID, token)
+		fmt.Fprintln(stderr, "Сохраните токен: повторно его получить нельзя.")
+	}
+
+	return 0
+}
+
+func parseExpiry(expires string, ttl time.Duration) (*time.Time, error) {
+	switch {
+	case expires != "" && ttl != 0:
+		return nil, fmt.Errorf("нельзя указывать -expires и -ttl одновременно")
+	case ttl != 0:
+		t := time.Now().UTC().Add(ttl)
+		return &t, nil
+	case expires == "":
+		return nil, nil
+	}
+
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, expires); err == nil {
+			t = t.UTC()
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("не удалось разобрать дату истечения %q", expires)
+}
+
+func printKeys(w io.Writer, keys []*auth.APIKey) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNAME\tSCOPES\tCREATED\tEXPIRES\tSTATUS")
+	now := time.Now()
+	for _, key := range keys {
+		scopes := make([]string, 0, len(key.Scopes))
+		for _, s := range key.Scopes {
+			scopes = append(scopes, string(s))
+		}
+		expires := "-"
+		if key.ExpiresAt != nil {
+			expires = key.ExpiresAt.Format(time.DateTime)
+		}
+		status := "active"
+		switch {
+		case errors.Is(key.Status(now), auth.ErrKeyRevoked):
+			status = "revoked"
+		case errors.Is(key.Status(now), auth.ErrKeyExpired):
+			status = "expired"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			key.ID, key.Name, strings.Join(scopes, ","), key.CreatedAt.Format(time.DateTime), expires, status)
+	}
+	tw.Flush()
+}
+
+func keyExitCode(err error) int {
+	if errors.Is(err, auth.ErrKeyNotFound) || errors.Is(err, auth.ErrKeyRevoked) {
+		return 3
+	}
+	return 1
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"go-offline-test/internal/metrics"
+	"go-offline-test/internal/repository"
+	"time"
+)
+
+// newMetrics - Собирает реестр метрик: рантайм Go, размеры коллекций и очереди модерации по тенантам.
+// Метрики HTTP-слоя регистрирует контроллер.
+func newMetrics(tenants *repository.TenantRegistry) *metrics.Registry {
+	registry := metrics.NewRegistry()
+	registry.Register(metrics.NewRuntimeCollector())
+
+	stats := func(value func(repository.Stats) int) func() []metrics.Sample {
+		return func() []metrics.Sample {
+			list, err := tenants.List(context.Background())
+			if err != nil {
+				return nil
+			}
+			samples := make([]metrics.Sample, 0, len(list))
+			for _, tenant := range list {
+				samples = append(samples, metrics.Sample{
+					Values: []string{tenant.Name},
+					Value:  float64(value(tenant.Repo.Stats())),
+				})
+			}
+			return samples
+		}
+	}
+
+	registry.Register(metrics.NewGaugeFunc("quotes_stored", "Число цитат в коллекции тенанта.",
+		stats(func(s repository.Stats) int { return s.Quotes }), "tenant"))
+	registry.Register(metrics.NewGaugeFunc("authors_stored", "Число авторов в коллекции тенанта.",
+		stats(func(s repository.Stats) int { return s.Authors }), "tenant"))
+	registry.Register(metrics.NewGaugeFunc("quote_free_ids", "Число освободившихся id цитат, ожидающих повторного использования.",
+		stats(func(s repository.Stats) int { return s.FreeIDs }), "tenant"))
+	registry.Register(metrics.NewGaugeFunc("quotes_pending", "Число цитат тенанта, ожидающих модерации.",
+		stats(func(s repository.Stats) int { return s.Pending }), "tenant"))
+	registry.Register(metrics.NewGaugeFunc("quotes_pending_oldest_seconds", "Сколько ждёт модерации самая старая цитата тенанта.",
+		stats(func(s repository.Stats) int {
+			if s.OldestPending.IsZero() {
+				return 0
+			}
+			return int(time.Since(s.OldestPending).Seconds())
+		}), "tenant"))
+
+	return registry
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"go-offline-test/internal/filter"
+	"go-offline-test/internal/logging"
+	"go-offline-test/internal/repository"
+	"go-offline-test/internal/services"
+	"go-offline-test/internal/shared"
+	"go-offline-test/internal/shared/dto/config"
+	"go-offline-test/internal/tracing"
+	"go-offline-test/internal/validation"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// newConfigService - Перезагрузка настроек с теми же аргументами командной строки, что и при запуске.
+// Применяются только настройки с тегом reload, остальные ждут перезапуска.
+func newConfigService(conf *config.Config, args []string, quotes *services.QuoteService, tenants *repository.TenantRegistry, tracer *tracing.Tracer) *services.ConfigService {
+	cs := services.NewConfigService(conf, func() (*config.Config, error) {
+		next, _, err := shared.LoadConfig("quotes", args, io.Discard)
+		return next, err
+	})
+
+	cs.OnReload(func(conf *config.Config) (func(), error) {
+		return func() {
+			logging.SetLevel(conf.Log.Level)
+			logging.SetRedactQuotes(conf.Log.RedactQuotes)
+		}, nil
+	})
+	cs.OnReload(func(conf *config.Config) (func(), error) {
+		// Файл правил читается один раз: применяются ровно те правила, что здесь собраны и проверены.
+		rules, errs := validation.Load(conf.Validation)
+		if len(errs) > 0 {
+			return nil, errors.Join(errs...)
+		}
+		return func() { quotes.SetRules(rules) }, nil
+	})
+	cs.OnReload(func(conf *config.Config) (func(), error) {
+		filters, errs := filter.Load(conf.Filter)
+		if len(errs) > 0 {
+			return nil, errors.Join(errs...)
+		}
+		return func() { quotes.SetFilters(filters) }, nil
+	})
+	cs.OnReload(func(conf *config.Config) (func(), error) {
+		return func() { quotes.SetModeration(conf.Moderation) }, nil
+	})
+	cs.OnReload(func(conf *config.Config) (func(), error) {
+		quota := repository.Quota{
+			MaxQuotes:  conf.Tenants.DefaultMaxQuotes,
+			MaxAuthors: conf.Tenants.DefaultMaxAuthors,
+		}
+		return func() { tenants.SetDefaultQuota(quota) }, nil
+	})
+	cs.OnReload(func(conf *config.Config) (func(), error) {
+		return func() { tracer.SetSampleRatio(conf.Trace.SampleRatio) }, nil
+	})
+
+	return cs
+}
+
+// watchReload - Перезагружает настройки по SIGHUP до отмены ctx.
+func watchReload(ctx context.Context, cs *services.ConfigService) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(hup)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hup:
+				slog.Info("получен SIGHUP, настройки перечитываются")
+				// Ошибку уже записал сервис, прежние настройки остаются в силе.
+				_, _ = cs.Reload(ctx)
+			}
+		}
+	}()
+}
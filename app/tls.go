@@ -0,0 +1,104 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"go-offline-test/internal/auth"
+	"go-offline-test/internal/certs"
+	"go-offline-test/internal/shared/dto/config"
+	"log/slog"
+	"os"
+	"time"
+)
+
+// modernCipherSuites - Для TLS 1.2 только ECDHE с AEAD. Наборы TLS 1.3 Go не даёт настраивать, они все современные.
+var modernCipherSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+}
+
+// newTLS - Собирает tls.Config из настроек. Возвращает nil, если TLS выключен.
+// Reloader нужно запустить отдельно, чтобы он следил за файлами сертификата.
+func newTLS(conf *config.TLSConfig) (*tls.Config, *certs.Reloader, error) {
+	if !conf.Enabled {
+		return nil, nil, nil
+	}
+
+	var reloader *certs.Reloader
+	if conf.SelfSigned {
+		cert, err := certs.SelfSigned(30 * 24 * time.Hour)
+		if err != nil {
+			return nil, nil, fmt.Errorf("не удалось выпустить самоподписанный сертификат: %w", err)
+		}
+		reloader = certs.Static(cert)
+		slog.Warn("используется самоподписанный сертификат, только для разработки", "not_after", cert.Leaf.NotAfter)
+	} else {
+		var err error
+		reloader, err = certs.NewReloader(conf.CertFile, conf.KeyFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("не удалось загрузить сертификат: %w", err)
+		}
+		slog.Info("сертификат загружен", "file", conf.CertFile, "not_after", reloader.NotAfter())
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: reloader.GetCertificate,
+	}
+	if conf.MinVersion == "1.3" {
+		tlsConfig.MinVersion = tls.VersionTLS13
+	}
+	if conf.CipherPolicy == "modern" {
+		tlsConfig.CipherSuites = modernCipherSuites
+	}
+
+	if conf.ClientCAFile != "" {
+		pool, err := loadCertPool(conf.ClientCAFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("не удалось загрузить CA клиентских сертификатов: %w", err)
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		if conf.ClientAuth == "verify_if_given" {
+			tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+		}
+		slog.Info("mTLS включён", "client_ca", conf.ClientCAFile, "client_auth", conf.ClientAuth)
+	}
+
+	return tlsConfig, reloader, nil
+}
+
+// newCertAuth - Аутентификация по клиентским сертификатам. nil, если mTLS или авторизация выключены.
+func newCertAuth(conf *config.TLSConfig, authConf *config.AuthConfig) (*auth.ClientCertAuthenticator, error) {
+	if !conf.Enabled || conf.ClientCAFile == "" || !authConf.Enabled {
+		return nil, nil
+	}
+
+	scopes := make(map[string][]auth.Scope, len(conf.ClientScopes))
+	for subject, raw := range conf.ClientScopes {
+		parsed, err := auth.ParseScopes(raw)
+		if err != nil {
+			return nil, fmt.Errorf("некорректное значение в tls.client_scopes: %s: %w", subject, err)
+		}
+		scopes[subject] = parsed
+	}
+	return auth.NewClientCertAuthenticator(scopes), nil
+}
+
+func loadCertPool(file string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, errors.New("в файле нет сертификатов в формате PEM")
+	}
+	return pool, nil
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"fmt"
+	"go-offline-test/internal/shared/dto/config"
+	"go-offline-test/internal/tracing"
+	"log/slog"
+)
+
+// newTracer - Создаёт трассировщик с экспортёром из настроек. Без экспортёра
+// идентификаторы трасс всё равно передаются дальше и пишутся в логи.
+func newTracer(conf *config.TraceConfig) (*tracing.Tracer, error) {
+	var exporter tracing.Exporter
+	switch conf.Exporter {
+	case "file":
+		fileExporter, err := tracing.NewFileExporter(conf.File, conf.ServiceName)
+		if err != nil {
+			return nil, fmt.Errorf("не удалось создать экспортёр трасс: %w", err)
+		}
+		exporter = fileExporter
+		slog.Info("трассы пишутся в файл", "file", conf.File)
+	case "otlp":
+		exporter = tracing.NewOTLPExporter(conf.Endpoint, conf.ServiceName, conf.Headers)
+		slog.Info("трассы отправляются по OTLP", "endpoint", conf.Endpoint)
+	}
+
+	return tracing.NewTracer(exporter, conf.SampleRatio), nil
+}
@@ -0,0 +1,122 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// QueryAudit - GET /audit. Пустые поля фильтра не передаются.
+func (c *Client) QueryAudit(ctx context.Context, filter AuditFilter) ([]*AuditEntry, error) {
+	q := url.Values{}
+	set := func(key, value string) {
+		if value != "" {
+			q.Set(key, value)
+		}
+	}
+	set("actor", filter.Actor)
+	set("operation", filter.Operation)
+	set("tenant", filter.Tenant)
+	set("request_id", filter.RequestID)
+	if filter.QuoteID != 0 {
+		q.Set("quote_id", strconv.Itoa(filter.QuoteID))
+	}
+	if filter.AuthorID != 0 {
+		q.Set("author_id", strconv.Itoa(filter.AuthorID))
+	}
+	if !filter.Since.IsZero() {
+		q.Set("since", filter.Since.Format(time.RFC3339))
+	}
+	if !filter.Until.IsZero() {
+		q.Set("until", filter.Until.Format(time.RFC3339))
+	}
+	if filter.Limit != 0 {
+		q.Set("limit", strconv.Itoa(filter.Limit))
+	}
+
+	var entries []*AuditEntry
+	if err := c.do(ctx, &request{method: http.MethodGet, path: "/audit", query: q}, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// ListTenants - GET /admin/tenants.
+func (c *Client) ListTenants(ctx context.Context) ([]*Tenant, error) {
+	var tenants []*Tenant
+	if err := c.do(ctx, &request{method: http.MethodGet, path: "/admin/tenants"}, &tenants); err != nil {
+		return nil, err
+	}
+	return tenants, nil
+}
+
+// CreateTenant - POST /admin/tenants. Без квоты применяется квота по умолчанию.
+func (c *Client) CreateTenant(ctx context.Context, tenant *CreateTenant) (*Tenant, error) {
+	var created Tenant
+	if err := c.do(ctx, &request{method: http.MethodPost, path: "/admin/tenants", body: tenant}, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// UpdateTenantQuota - PUT /admin/tenants/{name}/quota.
+func (c *Client) UpdateTenantQuota(ctx context.Context, name string, quota *Quota) (*Tenant, error) {
+	var tenant Tenant
+	req := &request{method: http.MethodPut, path: "/admin/tenants/" + url.PathEscape(name) + "/quota", body: quota}
+	if err := c.do(ctx, req, &tenant); err != nil {
+		return nil, err
+	}
+	return &tenant, nil
+}
+
+// DeleteTenant - DELETE /admin/tenants/{name}.
+func (c *Client) DeleteTenant(ctx context.Context, name string) error {
+	return c.do(ctx, &request{method: http.MethodDelete, path: "/admin/tenants/" + url.PathEscape(name)}, nil)
+}
+
+// ListKeys - GET /admin/keys. Токены в ответе не возвращаются.
+func (c *Client) ListKeys(ctx context.Context) ([]*APIKey, error) {
+	var keys []*APIKey
+	if err := c.do(ctx, &request{method: http.MethodGet, path: "/admin/keys"}, &keys); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// CreateKey - POST /admin/keys. Токен есть только в этом ответе.
+func (c *Client) CreateKey(ctx context.Context, key *CreateAPIKey) (*APIKey, error) {
+	var created APIKey
+	if err := c.do(ctx, &request{method: http.MethodPost, path: "/admin/keys", body: key}, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// RotateKey - POST /admin/keys/{id}/rotate. opts может быть nil - срок действия не меняется.
+func (c *Client) RotateKey(ctx context.Context, id string, opts *RotateAPIKey) (*APIKey, error) {
+	var rotated APIKey
+	req := &request{method: http.MethodPost, path: "/admin/keys/" + url.PathEscape(id) + "/rotate"}
+	if opts != nil {
+		req.body = opts
+	}
+	if err := c.do(ctx, req, &rotated); err != nil {
+		return nil, err
+	}
+	return &rotated, nil
+}
+
+// RevokeKey - DELETE /admin/keys/{id}.
+func (c *Client) RevokeKey(ctx context.Context, id string) error {
+	return c.do(ctx, &request{method: http.MethodDelete, path: "/admin/keys/" + url.PathEscape(id)}, nil)
+}
+
+// ReloadConfig - POST /admin/reload. Отклонённые настройки - ErrValidation (422).
+func (c *Client) ReloadConfig(ctx context.Context) (*ConfigReload, error) {
+	var report ConfigReload
+	if err := c.do(ctx, &request{method: http.MethodPost, path: "/admin/reload"}, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
@@ -0,0 +1,57 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// Author - GET /authors/{author}. ref - id автора числом, иначе имя или псевдоним.
+// Автор без опубликованных цитат - ErrNotFound.
+func (c *Client) Author(ctx context.Context, ref string) (*Author, error) {
+	var author Author
+	if err := c.do(ctx, &request{method: http.MethodGet, path: "/authors/" + url.PathEscape(ref)}, &author); err != nil {
+		return nil, err
+	}
+	return &author, nil
+}
+
+// CreateAuthor - POST /authors. Имя может совпадать с именем другого автора.
+func (c *Client) CreateAuthor(ctx context.Context, create *CreateAuthor) (*Author, error) {
+	var author Author
+	if err := c.do(ctx, &request{method: http.MethodPost, path: "/authors", body: create}, &author); err != nil {
+		return nil, err
+	}
+	return &author, nil
+}
+
+// UpdateAuthor - PUT /authors/{author}. Сведения заменяются целиком: пустые поля update очищаются.
+func (c *Client) UpdateAuthor(ctx context.Context, ref string, update *UpdateAuthor) (*Author, error) {
+	var author Author
+	req := &request{method: http.MethodPut, path: "/authors/" + url.PathEscape(ref), body: update}
+	if err := c.do(ctx, req, &author); err != nil {
+		return nil, err
+	}
+	return &author, nil
+}
+
+// MergeAuthors - POST /authors/{author}/merge. Цитаты автора ref переносятся к автору с id into.
+func (c *Client) MergeAuthors(ctx context.Context, ref string, into int) (*AuthorMerge, error) {
+	var merge AuthorMerge
+	req := &request{method: http.MethodPost, path: "/authors/" + url.PathEscape(ref) + "/merge", body: &MergeAuthors{Into: into}}
+	if err := c.do(ctx, req, &merge); err != nil {
+		return nil, err
+	}
+	return &merge, nil
+}
+
+// AuthorCitations - GET /authors/{author}/citations. Ссылки на все опубликованные цитаты автора
+// по возрастанию id; со StyleBibTeX тексты ссылок вместе - готовый файл .bib.
+func (c *Client) AuthorCitations(ctx context.Context, ref, style string) ([]*Citation, error) {
+	var citations []*Citation
+	req := &request{method: http.MethodGet, path: "/authors/" + url.PathEscape(ref) + "/citations", query: citeStyle(style)}
+	if err := c.do(ctx, req, &citations); err != nil {
+		return nil, err
+	}
+	return citations, nil
+}
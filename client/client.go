@@ -0,0 +1,217 @@
+// Package client - Типизированный Go-клиент сервиса цитат.
+//
+// Ошибки сервиса возвращаются как *Error и сравниваются через errors.Is с ErrNotFound,
+// ErrAlreadyExists, ErrValidation и другими. GET и HEAD повторяются при ответах 429/502/503/504
+// и сетевых ошибках, PUT и DELETE - только по ответу 429/503 от самого сервиса, см. RetryPolicy.
+package client
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Middleware - Обёртка над транспортом: подпись запросов, логирование, метрики.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// Options - Настройки клиента. Нулевое значение - запросы без авторизации с повторами по умолчанию.
+type Options struct {
+	// Token - Bearer-токен: API-ключ или JWT.
+	Token string
+	// Tenant - Значение заголовка X-Tenant. Пусто - тенант по умолчанию.
+	Tenant string
+	// UserAgent - Заголовок User-Agent. По умолчанию quotes-go-client.
+	UserAgent string
+	// Language - Значение Accept-Language, язык сообщений об ошибках (ru, en). Пусто - язык сервиса по умолчанию.
+	Language string
+	// Timeout - Предельное время одной попытки. 0 - без ограничения, действует только контекст.
+	Timeout time.Duration
+	// TLSConfig - Корневые сертификаты и клиентский сертификат для HTTPS и mTLS.
+	TLSConfig *tls.Config
+	// Transport - Базовый транспорт. По умолчанию копия http.DefaultTransport.
+	// Для адреса unix:// должен быть *http.Transport или nil.
+	Transport http.RoundTripper
+	// Middleware - Обёртки над транспортом. Первая в списке видит запрос первой.
+	Middleware []Middleware
+	// Retry - Повторы запросов при сбоях, см. RetryPolicy.
+	Retry RetryPolicy
+}
+
+// Client - Клиент сервиса цитат. Безопасен для одновременного использования из нескольких горутин.
+type Client struct {
+	base      *url.URL
+	http      *http.Client
+	token     string
+	tenant    string
+	userAgent string
+	language  string
+	retry     RetryPolicy
+}
+
+// New - Клиент для адреса http://host:port, https://host:port или unix:///путь.sock.
+func New(baseURL string, opts Options) (*Client, error) {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("некорректный адрес сервиса %q: %w", baseURL, err)
+	}
+
+	transport := opts.Transport
+	switch base.Scheme {
+	case "http", "https":
+		if base.Host == "" {
+			return nil, fmt.Errorf("в адресе сервиса %q нет хоста", baseURL)
+		}
+	case "unix":
+		socket := base.Host + base.Path
+		if socket == "" {
+			return nil, fmt.Errorf("в адресе сервиса %q нет пути к сокету", baseURL)
+		}
+		if transport == nil {
+			transport = http.DefaultTransport.(*http.Transport).Clone()
+		}
+		t, ok := transport.(*http.Transport)
+		if !ok {
+			return nil, errors.New("для unix-сокета транспорт должен быть *http.Transport")
+		}
+		t.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", socket)
+		}
+		base = &url.URL{Scheme: "http", Host: "unix"}
+	default:
+		return nil, fmt.Errorf("адрес сервиса должен начинаться с http://, https:// или unix://, получено %q", baseURL)
+	}
+
+	if transport == nil {
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	}
+	if opts.TLSConfig != nil {
+		t, ok := transport.(*http.Transport)
+		if !ok {
+			return nil, errors.New("TLSConfig задаётся только вместе с транспортом *http.Transport")
+		}
+		t.TLSClientConfig = opts.TLSConfig
+	}
+	for i := len(opts.Middleware) - 1; i >= 0; i-- {
+		transport = opts.Middleware[i](transport)
+	}
+
+	return &Client{
+		base:      base,
+		http:      &http.Client{Transport: transport, Timeout: opts.Timeout},
+		token:     opts.Token,
+		tenant:    opts.Tenant,
+		userAgent: cmp.Or(opts.UserAgent, "quotes-go-client"),
+		language:  opts.Language,
+		retry:     opts.Retry.withDefaults(),
+	}, nil
+}
+
+// request - Один вызов API.
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	header http.Header
+	body   any
+	// noRetryStatus - Не повторять по статусу ответа: для /readyz 503 - это ответ, а не сбой.
+	noRetryStatus bool
+}
+
+// send - Выполняет запрос с повторами и возвращает последний ответ независимо от статуса.
+func (c *Client) send(ctx context.Context, req *request) (*http.Response, error) {
+	var payload []byte
+	if req.body != nil {
+		var err error
+		if payload, err = json.Marshal(req.body); err != nil {
+			return nil, err
+		}
+	}
+
+	u := c.base.JoinPath(req.path)
+	u.RawQuery = req.query.Encode()
+
+	retry := c.retry.kind(req.method)
+	for attempt := 1; ; attempt++ {
+		var body io.Reader
+		if payload != nil {
+			body = bytes.NewReader(payload)
+		}
+		httpReq, err := http.NewRequestWithContext(ctx, req.method, u.String(), body)
+		if err != nil {
+			return nil, err
+		}
+		for key, values := range req.header {
+			httpReq.Header[key] = values
+		}
+		httpReq.Header.Set("Accept", "application/json, application/problem+json")
+		httpReq.Header.Set("User-Agent", c.userAgent)
+		if payload != nil {
+			httpReq.Header.Set("Content-Type", "application/json")
+		}
+		if c.token != "" {
+			httpReq.Header.Set("Authorization", "Bearer "+c.token)
+		}
+		if c.language != "" {
+			httpReq.Header.Set("Accept-Language", c.language)
+		}
+		if c.tenant != "" {
+			httpReq.Header.Set("X-Tenant", c.tenant)
+		}
+
+		resp, err := c.http.Do(httpReq)
+		last := retry == retryNever || attempt >= c.retry.MaxAttempts || ctx.Err() != nil
+		switch {
+		case err != nil:
+			if last || retry == retryOnRefusal {
+				return nil, err
+			}
+		case req.noRetryStatus || last || !retry.retryable(resp):
+			return resp, nil
+		}
+
+		wait := c.retry.backoff(attempt)
+		if resp != nil {
+			wait = c.retry.retryAfter(resp.Header.Get("Retry-After"), wait)
+			// Тело дочитываем, чтобы соединение вернулось в пул.
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// do - Выполняет запрос и декодирует JSON-ответ в out. Статус 400 и выше превращается в *Error.
+func (c *Client) do(ctx context.Context, req *request, out any) error {
+	resp, err := c.send(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return decodeError(resp)
+	}
+	return decodeBody(resp, out)
+}
+
+func decodeBody(resp *http.Response, out any) error {
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("некорректный ответ сервиса: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Ошибки для сравнения через errors.Is. Сопоставляются по коду ошибки сервиса, а если кода нет
+// (ответ прокси или шлюза) - по статусу. Конкретный ответ сервиса - в *Error.
+var (
+	// ErrNotFound - Нет цитаты, автора, тенанта или ключа. Без кода - 404.
+	ErrNotFound = errors.New("не найдено")
+	// ErrAlreadyExists - Цитата или тенант уже есть. Без кода - 409.
+	ErrAlreadyExists = errors.New("уже существует")
+	// ErrConflict - Операция конфликтует с текущим состоянием: ключ отозван, цитата не на модерации,
+	// имя автора неоднозначно или псевдоним занят, основной тенант нельзя удалить.
+	ErrConflict = errors.New("конфликт с текущим состоянием")
+	// ErrValidation - Данные запроса или новые настройки не прошли проверку. Поле - в Error.Field.
+	// Без кода - 400 и 422.
+	ErrValidation = errors.New("некорректные данные")
+	// ErrTooLarge - Тело запроса больше лимита сервиса. Без кода - 413.
+	ErrTooLarge = errors.New("слишком большой запрос")
+	// ErrUnauthorized - Нет токена или он недействителен. Без кода - 401.
+	ErrUnauthorized = errors.New("требуется авторизация")
+	// ErrForbidden - Не хватает прав. Без кода - 403.
+	ErrForbidden = errors.New("доступ запрещён")
+	// ErrQuotaExceeded - Превышена квота тенанта.
+	ErrQuotaExceeded = errors.New("превышена квота")
+	// ErrUnavailable - 429, 502, 503, 504: сервис перегружен, выключается или не готов.
+	// Сопоставляется по статусу всегда: такие ответы часто отдаёт шлюз, а не сервис.
+	ErrUnavailable = errors.New("сервис недоступен")
+)
+
+// codeErrors - Ошибка пакета для каждого кода сервиса. Коды без ошибки пакета сравниваются только с Error.Code.
+var codeErrors = map[ErrorCode]error{
+	CodeQuoteNotFound:       ErrNotFound,
+	CodeNoQuotes:            ErrNotFound,
+	CodeAuthorNotFound:      ErrNotFound,
+	CodeAuthorHasNoQuotes:   ErrNotFound,
+	CodeTenantNotFound:      ErrNotFound,
+	CodeKeyNotFound:         ErrNotFound,
+	CodeQuoteAlreadyExists:  ErrAlreadyExists,
+	CodeTenantAlreadyExists: ErrAlreadyExists,
+	CodeDefaultTenant:       ErrConflict,
+	CodeKeyRevoked:          ErrConflict,
+	CodeQuoteNotPending:     ErrConflict,
+	CodeAuthorAmbiguous:     ErrConflict,
+	CodeAliasTaken:          ErrConflict,
+	CodeValidationFailed:    ErrValidation,
+	CodeMalformedBody:       ErrValidation,
+	CodeContentRejected:     ErrValidation,
+	CodeConfigRejected:      ErrValidation,
+	CodePayloadTooLarge:     ErrTooLarge,
+	CodeUnauthorized:        ErrUnauthorized,
+	CodeForbidden:           ErrForbidden,
+	CodeQuotaExceeded:       ErrQuotaExceeded,
+}
+
+// statusErrors - Ошибка пакета по статусу, если в ответе нет кода сервиса.
+var statusErrors = map[int]error{
+	http.StatusNotFound:              ErrNotFound,
+	http.StatusConflict:              ErrAlreadyExists,
+	http.StatusBadRequest:            ErrValidation,
+	http.StatusUnprocessableEntity:   ErrValidation,
+	http.StatusRequestEntityTooLarge: ErrTooLarge,
+	http.StatusUnauthorized:          ErrUnauthorized,
+	http.StatusForbidden:             ErrForbidden,
+}
+
+// Error - Ответ сервиса с ошибкой в формате RFC 7807 (application/problem+json).
+type Error struct {
+	StatusCode int
+	// Code - Стабильный код ошибки (QUOTE_NOT_FOUND, VALIDATION_FAILED, ...). Пусто, если ответ не от сервиса.
+	Code    ErrorCode
+	Message string
+	// Field - Поле запроса, которое не прошло проверку. Пусто, если сервис его не указал.
+	Field string
+	// Fields - Все поля с ошибками проверки.
+	Fields []FieldError
+}
+
+func (e *Error) Error() string {
+	if e.Field != "" {
+		return fmt.Sprintf("%s: %s (HTTP %d)", e.Field, e.Message, e.StatusCode)
+	}
+	return fmt.Sprintf("%s (HTTP %d)", e.Message, e.StatusCode)
+}
+
+// Is - Сопоставляет код ответа, а без кода - статус, с ошибками пакета.
+func (e *Error) Is(target error) bool {
+	if target == ErrUnavailable {
+		return retryableStatus(e.StatusCode)
+	}
+	if e.Code != "" {
+		return codeErrors[e.Code] == target
+	}
+	return statusErrors[e.StatusCode] == target
+}
+
+// decodeError - *Error из тела ответа. Если тело не problem+json, сообщением становится текст ответа.
+func decodeError(resp *http.Response) error {
+	var problem Problem
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if json.Unmarshal(data, &problem) != nil || problem.Detail == "" {
+		problem.Detail = strings.TrimSpace(string(data))
+	}
+	if problem.Detail == "" {
+		problem.Detail = http.StatusText(resp.StatusCode)
+	}
+	apiErr := &Error{StatusCode: resp.StatusCode, Code: problem.Code, Message: problem.Detail, Fields: problem.Errors}
+	if len(problem.Errors) > 0 {
+		apiErr.Field = problem.Errors[0].Field
+	}
+	return apiErr
+}
@@ -0,0 +1,48 @@
+package client_test
+
+import (
+	"errors"
+	"go-offline-test/client"
+	"net/http"
+	"testing"
+)
+
+func TestErrorIs(t *testing.T) {
+	sentinels := []error{
+		client.ErrNotFound, client.ErrAlreadyExists, client.ErrConflict, client.ErrValidation, client.ErrTooLarge,
+		client.ErrUnauthorized, client.ErrForbidden, client.ErrQuotaExceeded, client.ErrUnavailable,
+	}
+	tests := []struct {
+		name   string
+		status int
+		code   client.ErrorCode
+		want   error
+	}{
+		{"цитата уже есть", http.StatusConflict, client.CodeQuoteAlreadyExists, client.ErrAlreadyExists},
+		{"неоднозначный автор - конфликт, а не дубликат", http.StatusConflict, client.CodeAuthorAmbiguous, client.ErrConflict},
+		{"отозванный ключ", http.StatusConflict, client.CodeKeyRevoked, client.ErrConflict},
+		{"цитата не на модерации", http.StatusConflict, client.CodeQuoteNotPending, client.ErrConflict},
+		{"занятый псевдоним", http.StatusConflict, client.CodeAliasTaken, client.ErrConflict},
+		{"квота - не нехватка прав", http.StatusForbidden, client.CodeQuotaExceeded, client.ErrQuotaExceeded},
+		{"нет прав", http.StatusForbidden, client.CodeForbidden, client.ErrForbidden},
+		{"отфильтрованный текст", http.StatusUnprocessableEntity, client.CodeContentRejected, client.ErrValidation},
+		{"отклонённые настройки", http.StatusUnprocessableEntity, client.CodeConfigRejected, client.ErrValidation},
+		{"нет тенанта", http.StatusNotFound, client.CodeTenantNotFound, client.ErrNotFound},
+		{"внутренняя ошибка", http.StatusInternalServerError, client.CodeInternal, nil},
+		{"409 без кода", http.StatusConflict, "", client.ErrAlreadyExists},
+		{"403 без кода", http.StatusForbidden, "", client.ErrForbidden},
+		{"413 без кода", http.StatusRequestEntityTooLarge, "", client.ErrTooLarge},
+		{"шлюз 502", http.StatusBadGateway, "", client.ErrUnavailable},
+		{"503 от сервиса", http.StatusServiceUnavailable, client.CodeInternal, client.ErrUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := error(&client.Error{StatusCode: tt.status, Code: tt.code})
+			for _, sentinel := range sentinels {
+				if got := errors.Is(err, sentinel); got != (sentinel == tt.want) {
+					t.Errorf("errors.Is(%d %s, %v) = %v", tt.status, tt.code, sentinel, got)
+				}
+			}
+		})
+	}
+}
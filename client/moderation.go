@@ -0,0 +1,41 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+)
+
+// ModerationQueue - GET /moderation/queue. Нужно право quotes:moderate.
+func (c *Client) ModerationQueue(ctx context.Context) (*ModerationQueue, error) {
+	var queue ModerationQueue
+	if err := c.do(ctx, &request{method: http.MethodGet, path: "/moderation/queue"}, &queue); err != nil {
+		return nil, err
+	}
+	return &queue, nil
+}
+
+// ApproveQuote - POST /moderation/quotes/{id}/approve. Цитата не на модерации - ErrConflict
+// с кодом QUOTE_NOT_PENDING.
+func (c *Client) ApproveQuote(ctx context.Context, id int) (*Quote, error) {
+	var quote Quote
+	req := &request{method: http.MethodPost, path: "/moderation/quotes/" + strconv.Itoa(id) + "/approve"}
+	if err := c.do(ctx, req, &quote); err != nil {
+		return nil, err
+	}
+	return &quote, nil
+}
+
+// RejectQuote - POST /moderation/quotes/{id}/reject. Отклонённая цитата удаляется из коллекции.
+func (c *Client) RejectQuote(ctx context.Context, id int, reason string) (*Quote, error) {
+	var quote Quote
+	req := &request{
+		method: http.MethodPost,
+		path:   "/moderation/quotes/" + strconv.Itoa(id) + "/reject",
+		body:   &RejectQuote{Reason: reason},
+	}
+	if err := c.do(ctx, req, &quote); err != nil {
+		return nil, err
+	}
+	return &quote, nil
+}
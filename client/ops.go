@@ -0,0 +1,54 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+// Healthz - GET /healthz: процесс жив.
+func (c *Client) Healthz(ctx context.Context) (*Health, error) {
+	var health Health
+	if err := c.do(ctx, &request{method: http.MethodGet, path: "/healthz"}, &health); err != nil {
+		return nil, err
+	}
+	return &health, nil
+}
+
+// Readyz - GET /readyz. Если сервис не готов, возвращается и отчёт с причиной, и ошибка ErrUnavailable.
+func (c *Client) Readyz(ctx context.Context) (*Health, error) {
+	resp, err := c.send(ctx, &request{method: http.MethodGet, path: "/readyz", noRetryStatus: true})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusServiceUnavailable:
+		var health Health
+		if err := decodeBody(resp, &health); err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return &health, &Error{StatusCode: resp.StatusCode, Message: "сервис не готов: " + health.Reason}
+		}
+		return &health, nil
+	default:
+		return nil, decodeError(resp)
+	}
+}
+
+// Metrics - GET /metrics в текстовом формате Prometheus.
+func (c *Client) Metrics(ctx context.Context) (string, error) {
+	resp, err := c.send(ctx, &request{method: http.MethodGet, path: "/metrics"})
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return "", decodeError(resp)
+	}
+	data, err := io.ReadAll(resp.Body)
+	return string(data), err
+}
@@ -0,0 +1,107 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Стили ссылок для Cite и AuthorCitations.
+const (
+	StyleAPA     = "apa"
+	StyleMLA     = "mla"
+	StyleChicago = "chicago"
+	StyleBibTeX  = "bibtex"
+)
+
+// expandAuthor - Параметр запроса, с которым цитаты приходят со сведениями об авторе в AuthorDetails.
+var expandAuthor = url.Values{"expand": {"author"}}
+
+// AddQuote - POST /quotes. Возвращает цитату с присвоенным id.
+func (c *Client) AddQuote(ctx context.Context, text, author string) (*Quote, error) {
+	return c.SubmitQuote(ctx, &Quote{Text: text, AuthorName: author})
+}
+
+// AddQuoteByAuthorID - POST /quotes с author_id: так цитату добавляют одному из авторов-тёзок.
+func (c *Client) AddQuoteByAuthorID(ctx context.Context, text string, authorID int) (*Quote, error) {
+	return c.SubmitQuote(ctx, &Quote{Text: text, AuthorID: authorID})
+}
+
+// SubmitQuote - POST /quotes с полями, которые задаёт клиент: текст, автор или author_id и источник.
+// Остальные поля quote сервис проставляет сам.
+func (c *Client) SubmitQuote(ctx context.Context, quote *Quote) (*Quote, error) {
+	body := &Quote{Text: quote.Text, AuthorName: quote.AuthorName, AuthorID: quote.AuthorID, Source: quote.Source}
+	var created Quote
+	if err := c.do(ctx, &request{method: http.MethodPost, path: "/quotes", body: body}, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// Cite - GET /quotes/{id}/cite. Пустой style - APA.
+func (c *Client) Cite(ctx context.Context, id int, style string) (*Citation, error) {
+	var citation Citation
+	req := &request{method: http.MethodGet, path: "/quotes/" + strconv.Itoa(id) + "/cite", query: citeStyle(style)}
+	if err := c.do(ctx, req, &citation); err != nil {
+		return nil, err
+	}
+	return &citation, nil
+}
+
+func citeStyle(style string) url.Values {
+	if style == "" {
+		return nil
+	}
+	return url.Values{"style": {style}}
+}
+
+// ListQuotes - GET /quotes. Пустое хранилище - ErrNotFound.
+func (c *Client) ListQuotes(ctx context.Context) ([]*Quote, error) {
+	return c.listQuotes(ctx, "", nil)
+}
+
+// QuotesByAuthor - GET /quotes с заголовком author.
+func (c *Client) QuotesByAuthor(ctx context.Context, author string) ([]*Quote, error) {
+	return c.listQuotes(ctx, author, nil)
+}
+
+// ListQuotesWithAuthors - GET /quotes?expand=author. Пустой author - все цитаты, иначе цитаты автора.
+func (c *Client) ListQuotesWithAuthors(ctx context.Context, author string) ([]*Quote, error) {
+	return c.listQuotes(ctx, author, expandAuthor)
+}
+
+func (c *Client) listQuotes(ctx context.Context, author string, query url.Values) ([]*Quote, error) {
+	req := &request{method: http.MethodGet, path: "/quotes", query: query, header: http.Header{}}
+	if author != "" {
+		req.header.Set("author", author)
+	}
+	var quotes []*Quote
+	if err := c.do(ctx, req, &quotes); err != nil {
+		return nil, err
+	}
+	return quotes, nil
+}
+
+// RandomQuote - GET /quotes/random.
+func (c *Client) RandomQuote(ctx context.Context) (*Quote, error) {
+	return c.randomQuote(ctx, nil)
+}
+
+// RandomQuoteWithAuthor - GET /quotes/random?expand=author.
+func (c *Client) RandomQuoteWithAuthor(ctx context.Context) (*Quote, error) {
+	return c.randomQuote(ctx, expandAuthor)
+}
+
+func (c *Client) randomQuote(ctx context.Context, query url.Values) (*Quote, error) {
+	var quote Quote
+	if err := c.do(ctx, &request{method: http.MethodGet, path: "/quotes/random", query: query}, &quote); err != nil {
+		return nil, err
+	}
+	return &quote, nil
+}
+
+// DeleteQuote - DELETE /quotes/{id}.
+func (c *Client) DeleteQuote(ctx context.Context, id int) error {
+	return c.do(ctx, &request{method: http.MethodDelete, path: "/quotes/" + strconv.Itoa(id)}, nil)
+}
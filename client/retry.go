@@ -0,0 +1,138 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy - GET и HEAD повторяются при ответах 429/502/503/504 и при сетевых ошибках.
+// PUT и DELETE повторяются только по ответу 429 или 503 с кодом ошибки сервиса: шлюз, вернувший 502
+// или 504, не знает, выполнил ли сервис запрос. Если запрос выполнен, а ответ потерялся, повтор
+// DELETE /quotes/{id} удалил бы другую цитату, получившую освободившийся id.
+// POST не повторяется: повтор мог бы добавить цитату или выпустить ключ дважды.
+type RetryPolicy struct {
+	// MaxAttempts - Всего попыток, включая первую. 0 - по умолчанию 3, 1 - без повторов.
+	MaxAttempts int
+	// InitialBackoff - Пауза перед первым повтором, дальше удваивается. По умолчанию 100ms.
+	InitialBackoff time.Duration
+	// MaxBackoff - Предельная пауза, в том числе из Retry-After. По умолчанию 2s.
+	MaxBackoff time.Duration
+	// RetryWritesOnNetworkError - Повторять PUT и DELETE, как GET: при сетевых ошибках и ответах шлюза.
+	// Включать, только если повтор уже выполненного запроса безопасен для вызывающего кода.
+	RetryWritesOnNetworkError bool
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = 3
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = 100 * time.Millisecond
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = 2 * time.Second
+	}
+	if p.MaxBackoff < p.InitialBackoff {
+		p.MaxBackoff = p.InitialBackoff
+	}
+	return p
+}
+
+// backoff - Экспоненциальная пауза со случайной добавкой, чтобы клиенты не повторяли запросы одновременно.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.InitialBackoff
+	for i := 1; i < attempt && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+	d = min(d, p.MaxBackoff)
+	return d/2 + rand.N(d/2+1)
+}
+
+// retryAfter - Пауза из заголовка Retry-After в секундах или как дата, не больше MaxBackoff.
+func (p RetryPolicy) retryAfter(header string, fallback time.Duration) time.Duration {
+	if header == "" {
+		return fallback
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return min(time.Duration(seconds)*time.Second, p.MaxBackoff)
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		return min(max(time.Until(at), 0), p.MaxBackoff)
+	}
+	return fallback
+}
+
+// retryKind - Когда запрос можно повторить.
+type retryKind int
+
+const (
+	retryNever retryKind = iota
+	// retryOnRefusal - Только по ответу 429/503 с кодом ошибки: сервис сам сообщил, что запрос не выполнен.
+	retryOnRefusal
+	// retryAlways - И при сетевой ошибке или ответе шлюза, когда неизвестно, выполнен ли запрос.
+	retryAlways
+)
+
+func (p RetryPolicy) kind(method string) retryKind {
+	switch method {
+	case http.MethodGet, http.MethodHead:
+		return retryAlways
+	case http.MethodPut, http.MethodDelete:
+		if p.RetryWritesOnNetworkError {
+			return retryAlways
+		}
+		return retryOnRefusal
+	}
+	return retryNever
+}
+
+// retryable - Можно ли повторить запрос после ответа resp.
+func (k retryKind) retryable(resp *http.Response) bool {
+	switch k {
+	case retryAlways:
+		return retryableStatus(resp.StatusCode)
+	case retryOnRefusal:
+		return refused(resp)
+	}
+	return false
+}
+
+func retryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// refused - Ответ 429 или 503 в формате problem+json с кодом ошибки пришёл от самого сервиса,
+// а не от шлюза. Тело прочитанного ответа подменяется копией, чтобы его можно было декодировать ещё раз.
+func refused(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return false
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{bytes.NewReader(data), resp.Body}
+	var problem Problem
+	return json.Unmarshal(data, &problem) == nil && problem.Code != ""
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,176 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"go-offline-test/client"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// roundTripFunc - Транспорт из функции: отвечает без сервера.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+// errConnReset - Соединение оборвалось после отправки запроса: выполнен ли он, неизвестно.
+var errConnReset = errors.New("connection reset by peer")
+
+func TestRetry(t *testing.T) {
+	respond := func(status int, body string) func(*http.Request) (*http.Response, error) {
+		return func(r *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: status, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(body)), Request: r}, nil
+		}
+	}
+	// refusal - Сервис сам отказал в запросе: в ответе есть код ошибки.
+	refusal := `{"status":503,"detail":"сервис выключается","code":"INTERNAL_ERROR"}`
+	lost := func(*http.Request) (*http.Response, error) { return nil, errConnReset }
+
+	deleteQuote := func(ctx context.Context, c *client.Client) error { return c.DeleteQuote(ctx, 7) }
+	updateAuthor := func(ctx context.Context, c *client.Client) error {
+		_, err := c.UpdateAuthor(ctx, "Steve Jobs", &client.UpdateAuthor{})
+		return err
+	}
+	randomQuote := func(ctx context.Context, c *client.Client) error {
+		_, err := c.RandomQuote(ctx)
+		return err
+	}
+	addQuote := func(ctx context.Context, c *client.Client) error {
+		_, err := c.AddQuote(ctx, "Текст", "Автор")
+		return err
+	}
+
+	tests := []struct {
+		name string
+		call func(context.Context, *client.Client) error
+		// optIn - RetryWritesOnNetworkError.
+		optIn bool
+		// attempts - Ответы транспорта по порядку, последний повторяется.
+		attempts []func(*http.Request) (*http.Response, error)
+		calls    int
+		err      error
+	}{
+		{
+			name:     "ответ на выполненный DELETE потерян - без повтора",
+			call:     deleteQuote,
+			attempts: []func(*http.Request) (*http.Response, error){lost, respond(http.StatusNoContent, "")},
+			calls:    1,
+			err:      errConnReset,
+		},
+		{
+			name:     "PUT после сетевой ошибки - без повтора",
+			call:     updateAuthor,
+			attempts: []func(*http.Request) (*http.Response, error){lost, respond(http.StatusOK, "{}")},
+			calls:    1,
+			err:      errConnReset,
+		},
+		{
+			name:     "DELETE после сетевой ошибки с RetryWritesOnNetworkError",
+			call:     deleteQuote,
+			optIn:    true,
+			attempts: []func(*http.Request) (*http.Response, error){lost, lost, respond(http.StatusNoContent, "")},
+			calls:    3,
+		},
+		{
+			name:     "DELETE по ответу 503 от сервиса",
+			call:     deleteQuote,
+			attempts: []func(*http.Request) (*http.Response, error){respond(http.StatusServiceUnavailable, refusal), respond(http.StatusNoContent, "")},
+			calls:    2,
+		},
+		{
+			name:     "PUT по ответу 429 от сервиса",
+			call:     updateAuthor,
+			attempts: []func(*http.Request) (*http.Response, error){respond(http.StatusTooManyRequests, refusal), respond(http.StatusOK, "{}")},
+			calls:    2,
+		},
+		{
+			name:     "DELETE по ответу 503 без кода - без повтора",
+			call:     deleteQuote,
+			attempts: []func(*http.Request) (*http.Response, error){respond(http.StatusServiceUnavailable, "Service Unavailable"), respond(http.StatusNoContent, "")},
+			calls:    1,
+			err:      client.ErrUnavailable,
+		},
+		{
+			name:     "PUT по ответу шлюза 502 - без повтора",
+			call:     updateAuthor,
+			attempts: []func(*http.Request) (*http.Response, error){respond(http.StatusBadGateway, refusal), respond(http.StatusOK, "{}")},
+			calls:    1,
+			err:      client.ErrUnavailable,
+		},
+		{
+			name:     "DELETE по ответу шлюза 504 - без повтора",
+			call:     deleteQuote,
+			attempts: []func(*http.Request) (*http.Response, error){respond(http.StatusGatewayTimeout, ""), respond(http.StatusNoContent, "")},
+			calls:    1,
+			err:      client.ErrUnavailable,
+		},
+		{
+			name:     "DELETE по ответу 504 с RetryWritesOnNetworkError",
+			call:     deleteQuote,
+			optIn:    true,
+			attempts: []func(*http.Request) (*http.Response, error){respond(http.StatusGatewayTimeout, ""), respond(http.StatusNoContent, "")},
+			calls:    2,
+		},
+		{
+			name:     "GET по ответу шлюза 502",
+			call:     randomQuote,
+			attempts: []func(*http.Request) (*http.Response, error){respond(http.StatusBadGateway, ""), respond(http.StatusOK, "{}")},
+			calls:    2,
+		},
+		{
+			name:     "GET после сетевой ошибки",
+			call:     randomQuote,
+			attempts: []func(*http.Request) (*http.Response, error){lost, respond(http.StatusOK, "{}")},
+			calls:    2,
+		},
+		{
+			name:     "GET исчерпал попытки",
+			call:     randomQuote,
+			attempts: []func(*http.Request) (*http.Response, error){lost},
+			calls:    3,
+			err:      errConnReset,
+		},
+		{
+			name:     "POST по ответу 503 - без повтора",
+			call:     addQuote,
+			attempts: []func(*http.Request) (*http.Response, error){respond(http.StatusServiceUnavailable, ""), respond(http.StatusCreated, "{}")},
+			calls:    1,
+			err:      client.ErrUnavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			transport := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+				attempt := tt.attempts[min(calls, len(tt.attempts)-1)]
+				calls++
+				return attempt(r)
+			})
+			c, err := client.New("http://quotes.test", client.Options{
+				Transport: transport,
+				Retry: client.RetryPolicy{
+					InitialBackoff:            time.Millisecond,
+					MaxBackoff:                time.Millisecond,
+					RetryWritesOnNetworkError: tt.optIn,
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = tt.call(context.Background(), c)
+			if calls != tt.calls {
+				t.Errorf("попыток %d, ожидалось %d", calls, tt.calls)
+			}
+			switch {
+			case tt.err == nil && err != nil:
+				t.Errorf("ошибка %v, ожидался успех", err)
+			case tt.err != nil && !errors.Is(err, tt.err):
+				t.Errorf("ошибка %v, ожидалась %v", err, tt.err)
+			}
+		})
+	}
+}
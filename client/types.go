@@ -0,0 +1,66 @@
+package client
+
+import "go-offline-test/internal/shared/dto"
+
+// Типы запросов и ответов совпадают с теми, что сервис кодирует в JSON.
+type (
+	Quote = dto.Quote
+	// ModerationQueue, PendingQuote - Очередь модерации тенанта.
+	ModerationQueue = dto.ModerationQueue
+	PendingQuote    = dto.PendingQuote
+	RejectQuote     = dto.RejectQuote
+	// Author, AuthorLink, UpdateAuthor, CreateAuthor - Автор и сведения о нём.
+	Author       = dto.Author
+	AuthorLink   = dto.AuthorLink
+	UpdateAuthor = dto.UpdateAuthor
+	CreateAuthor = dto.CreateAuthor
+	// Source, Citation - Источник цитаты и ссылка на неё.
+	Source   = dto.Source
+	Citation = dto.Citation
+	// MergeAuthors, AuthorMerge - Запрос на объединение авторов и его итог.
+	MergeAuthors = dto.MergeAuthors
+	AuthorMerge  = dto.AuthorMerge
+	Tenant       = dto.Tenant
+	Quota        = dto.Quota
+	CreateTenant = dto.CreateTenant
+	APIKey       = dto.APIKey
+	CreateAPIKey = dto.CreateAPIKey
+	RotateAPIKey = dto.RotateAPIKey
+	AuditEntry   = dto.AuditEntry
+	AuditFilter  = dto.AuditFilter
+	Health       = dto.Health
+	HealthCheck  = dto.HealthCheck
+	ConfigReload = dto.ConfigReload
+	ConfigChange = dto.ConfigChange
+	Problem      = dto.Problem
+	FieldError   = dto.FieldError
+	ErrorCode    = dto.ErrorCode
+)
+
+// Коды ошибок сервиса для сравнения с Error.Code.
+const (
+	CodeValidationFailed     = dto.CodeValidationFailed
+	CodeMalformedBody        = dto.CodeMalformedBody
+	CodeUnauthorized         = dto.CodeUnauthorized
+	CodeForbidden            = dto.CodeForbidden
+	CodeQuotaExceeded        = dto.CodeQuotaExceeded
+	CodeQuoteNotFound        = dto.CodeQuoteNotFound
+	CodeNoQuotes             = dto.CodeNoQuotes
+	CodeAuthorNotFound       = dto.CodeAuthorNotFound
+	CodeAuthorHasNoQuotes    = dto.CodeAuthorHasNoQuotes
+	CodeTenantNotFound       = dto.CodeTenantNotFound
+	CodeKeyNotFound          = dto.CodeKeyNotFound
+	CodeNotAcceptable        = dto.CodeNotAcceptable
+	CodeQuoteAlreadyExists   = dto.CodeQuoteAlreadyExists
+	CodeTenantAlreadyExists  = dto.CodeTenantAlreadyExists
+	CodeDefaultTenant        = dto.CodeDefaultTenant
+	CodeKeyRevoked           = dto.CodeKeyRevoked
+	CodeQuoteNotPending      = dto.CodeQuoteNotPending
+	CodeAuthorAmbiguous      = dto.CodeAuthorAmbiguous
+	CodeAliasTaken           = dto.CodeAliasTaken
+	CodePayloadTooLarge      = dto.CodePayloadTooLarge
+	CodeUnsupportedMediaType = dto.CodeUnsupportedMediaType
+	CodeContentRejected      = dto.CodeContentRejected
+	CodeConfigRejected       = dto.CodeConfigRejected
+	CodeInternal             = dto.CodeInternal
+)
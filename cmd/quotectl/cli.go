@@ -0,0 +1,163 @@
+package main
+
+import (
+	"cmp"
+	"crypto/tls"
+	"crypto/x509"
+	"flag"
+	"fmt"
+	"go-offline-test/client"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputPlain = "plain"
+)
+
+// cli - Общие флаги и подключение, которое получают все команды.
+type cli struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+
+	profile string
+	server  string
+	token   string
+	tenant  string
+	output  string
+	timeout time.Duration
+
+	client *client.Client
+}
+
+// flagSet - Флаги команды вместе с общими. Значения по умолчанию берутся из уже разобранных
+// общих флагов перед командой, затем из переменных окружения.
+func (c *cli) flagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	fs.StringVar(&c.profile, "profile", orEnv(c.profile, "QUOTECTL_PROFILE"), "профиль из файла настроек")
+	fs.StringVar(&c.server, "server", orEnv(c.server, "QUOTECTL_SERVER"), "адрес сервиса")
+	fs.StringVar(&c.token, "token", orEnv(c.token, "QUOTECTL_TOKEN"), "bearer-токен")
+	fs.StringVar(&c.tenant, "tenant", orEnv(c.tenant, "QUOTECTL_TENANT"), "тенант")
+	fs.StringVar(&c.output, "o", cmp.Or(c.output, outputTable), "формат вывода: table, json или plain")
+	fs.DurationVar(&c.timeout, "timeout", cmp.Or(c.timeout, 10*time.Second), "предельное время одного запроса")
+	return fs
+}
+
+func orEnv(value, env string) string {
+	return cmp.Or(value, os.Getenv(env))
+}
+
+// parseArgs - Разбирает флаги вперемешку с аргументами: quotectl by-author Steve Jobs -o json.
+// Всё после -- считается аргументами, даже если начинается с дефиса.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		consumed := len(args) - fs.NArg()
+		if consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, fs.Args()...), nil
+		}
+
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// connect - Собирает подключение: флаги и переменные окружения перекрывают профиль.
+func (c *cli) connect() error {
+	switch c.output {
+	case outputTable, outputJSON, outputPlain:
+	default:
+		return usagef("неизвестный формат вывода %q, ожидается table, json или plain", c.output)
+	}
+
+	p, _, err := loadProfiles()
+	if err != nil {
+		return err
+	}
+	name := c.profile
+	if name == "" {
+		name = p.Current
+	}
+
+	conn := &profile{}
+	if name != "" {
+		found, ok := p.Profiles[name]
+		if !ok {
+			return usagef("профиль %q не найден", name)
+		}
+		*conn = *found
+	}
+	if c.server != "" {
+		conn.Server = c.server
+	}
+	if c.token != "" {
+		conn.Token = c.token
+	}
+	if c.tenant != "" {
+		conn.Tenant = c.tenant
+	}
+	if conn.Server == "" {
+		conn.Server = "http://localhost:8080"
+	}
+
+	return c.dial(conn)
+}
+
+// dial - Клиент SDK для профиля. Адрес unix:///путь.sock означает unix-сокет.
+func (c *cli) dial(p *profile) error {
+	if !strings.HasPrefix(p.Server, "http://") && !strings.HasPrefix(p.Server, "https://") && !strings.HasPrefix(p.Server, "unix://") {
+		return usagef("адрес сервиса должен начинаться с http://, https:// или unix://")
+	}
+	tlsConfig, err := clientTLS(p)
+	if err != nil {
+		return err
+	}
+
+	c.client, err = client.New(p.Server, client.Options{
+		Token:     p.Token,
+		Tenant:    p.Tenant,
+		UserAgent: "quotectl",
+		Timeout:   c.timeout,
+		TLSConfig: tlsConfig,
+	})
+	if err != nil {
+		return usagef("%v", err)
+	}
+	return nil
+}
+
+func clientTLS(p *profile) (*tls.Config, error) {
+	conf := &tls.Config{InsecureSkipVerify: p.Insecure}
+	if p.CAFile != "" {
+		data, err := os.ReadFile(p.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("в %s нет сертификатов в формате PEM", p.CAFile)
+		}
+		conf.RootCAs = pool
+	}
+	if p.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(p.CertFile, p.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		conf.Certificates = []tls.Certificate{cert}
+	}
+	return conf, nil
+}
@@ -0,0 +1,454 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"go-offline-test/client"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// runFunc - Выполняет команду после разбора флагов.
+type runFunc func(ctx context.Context, c *cli, args []string) error
+
+// commands - Команды регистрируют свои флаги и возвращают функцию, которая их использует.
+var commands = map[string]func(fs *flag.FlagSet) runFunc{
+	"add":          addCommand,
+	"list":         listCommand,
+	"random":       randomCommand,
+	"by-author":    byAuthorCommand,
+	"delete":       deleteCommand,
+	"import":       importCommand,
+	"export":       exportCommand,
+	"pending":      pendingCommand,
+	"approve":      approveCommand,
+	"reject":       rejectCommand,
+	"author":       authorCommand,
+	"author-set":   authorSetCommand,
+	"author-new":   authorNewCommand,
+	"author-merge": authorMergeCommand,
+	"cite":         citeCommand,
+}
+
+func addCommand(fs *flag.FlagSet) runFunc {
+	author := fs.String("author", "", "автор цитаты")
+	authorID := fs.Int("author-id", 0, "id автора, если имя носят несколько авторов")
+	var source client.Source
+	fs.StringVar(&source.Type, "source-type", "", "вид источника: book, article, speech, interview, letter, web, other")
+	fs.StringVar(&source.Title, "source", "", "название источника")
+	fs.StringVar(&source.Publisher, "publisher", "", "издательство, журнал или событие")
+	fs.Func("year", "год издания или выступления", yearFlag(&source.Year))
+	fs.StringVar(&source.Pages, "pages", "", "страница или диапазон, например 42-45")
+	fs.StringVar(&source.URL, "url", "", "адрес источника")
+	return func(ctx context.Context, c *cli, args []string) error {
+		if (*author == "") == (*authorID == 0) || len(args) == 0 {
+			return usagef("использование: quotectl add -author ИМЯ | -author-id ID [-source НАЗВАНИЕ ...] ТЕКСТ")
+		}
+		req := &client.Quote{Text: strings.Join(args, " "), AuthorName: *author, AuthorID: *authorID}
+		// Источник отправляется, если задан хоть один его флаг: пустое название сервис отклонит.
+		if source != (client.Source{}) {
+			req.Source = &source
+		}
+		quote, err := c.client.SubmitQuote(ctx, req)
+		if err != nil {
+			return err
+		}
+		return c.printQuote(quote)
+	}
+}
+
+func listCommand(fs *flag.FlagSet) runFunc {
+	author := fs.String("author", "", "только цитаты автора")
+	return func(ctx context.Context, c *cli, args []string) error {
+		if len(args) > 0 {
+			return usagef("list не принимает аргументов, автор задаётся флагом -author")
+		}
+		quotes, err := c.client.QuotesByAuthor(ctx, *author)
+		if err != nil {
+			return err
+		}
+		return c.printQuotes(quotes)
+	}
+}
+
+func randomCommand(*flag.FlagSet) runFunc {
+	return func(ctx context.Context, c *cli, args []string) error {
+		quote, err := c.client.RandomQuote(ctx)
+		if err != nil {
+			return err
+		}
+		return c.printQuote(quote)
+	}
+}
+
+func byAuthorCommand(*flag.FlagSet) runFunc {
+	return func(ctx context.Context, c *cli, args []string) error {
+		if len(args) == 0 {
+			return usagef("использование: quotectl by-author ИМЯ")
+		}
+		quotes, err := c.client.QuotesByAuthor(ctx, strings.Join(args, " "))
+		if err != nil {
+			return err
+		}
+		return c.printQuotes(quotes)
+	}
+}
+
+func deleteCommand(*flag.FlagSet) runFunc {
+	return func(ctx context.Context, c *cli, args []string) error {
+		if len(args) == 0 {
+			return usagef("использование: quotectl delete ID...")
+		}
+		ids, err := parseIDs(args)
+		if err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			if err := c.client.DeleteQuote(ctx, id); err != nil {
+				return fmt.Errorf("цитата %d: %w", id, err)
+			}
+			if c.output != outputJSON {
+				fmt.Fprintf(c.stdout, "цитата %d удалена\n", id)
+			}
+		}
+		return nil
+	}
+}
+
+// parseIDs - id цитат из аргументов. Все id проверяются до первого запроса.
+func parseIDs(args []string) ([]int, error) {
+	ids := make([]int, 0, len(args))
+	for _, arg := range args {
+		id, err := strconv.Atoi(arg)
+		if err != nil || id <= 0 {
+			return nil, usagef("некорректный id %q", arg)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func importCommand(fs *flag.FlagSet) runFunc {
+	file := fs.String("file", "-", "файл с цитатами, - для stdin")
+	keepGoing := fs.Bool("keep-going", false, "не останавливаться на ошибках")
+	return func(ctx context.Context, c *cli, args []string) error {
+		quotes, err := readQuotes(c.stdin, *file)
+		if err != nil {
+			return err
+		}
+
+		var added, skipped, failed int
+		var lastErr error
+		for i, quote := range quotes {
+			// id авторов в другом тенанте свои, поэтому автор передаётся только именем.
+			_, err := c.client.SubmitQuote(ctx, &client.Quote{Text: quote.Text, AuthorName: quote.AuthorName, Source: quote.Source})
+			switch {
+			case err == nil:
+				added++
+			case errors.Is(err, client.ErrAlreadyExists):
+				skipped++
+			case *keepGoing && ctx.Err() == nil:
+				failed++
+				lastErr = err
+				fmt.Fprintf(c.stderr, "цитата %d (%s): %v\n", i+1, quote.AuthorName, err)
+			default:
+				return fmt.Errorf("цитата %d (%s): %w; добавлено до ошибки: %d", i+1, quote.AuthorName, err, added)
+			}
+		}
+
+		fmt.Fprintf(c.stderr, "добавлено: %d, уже были: %d, с ошибкой: %d\n", added, skipped, failed)
+		if failed > 0 {
+			return fmt.Errorf("%d цитат не загружено, последняя ошибка: %w", failed, lastErr)
+		}
+		return nil
+	}
+}
+
+// readQuotes - JSON-массив цитат (как в export) или по одному объекту в строке.
+func readQuotes(stdin io.Reader, file string) ([]*client.Quote, error) {
+	var data []byte
+	var err error
+	if file == "-" {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(file)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		var quotes []*client.Quote
+		if err := json.Unmarshal(data, &quotes); err != nil {
+			return nil, usagef("некорректный JSON: %v", err)
+		}
+		return quotes, nil
+	}
+
+	var quotes []*client.Quote
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for dec.More() {
+		var quote client.Quote
+		if err := dec.Decode(&quote); err != nil {
+			return nil, usagef("некорректный JSON в цитате %d: %v", len(quotes)+1, err)
+		}
+		quotes = append(quotes, &quote)
+	}
+	return quotes, nil
+}
+
+func exportCommand(fs *flag.FlagSet) runFunc {
+	file := fs.String("file", "-", "куда выгрузить, - для stdout")
+	author := fs.String("author", "", "только цитаты автора")
+	return func(ctx context.Context, c *cli, args []string) error {
+		quotes, err := c.client.QuotesByAuthor(ctx, *author)
+		if errors.Is(err, client.ErrNotFound) && *author == "" {
+			// Пустое хранилище - не ошибка для выгрузки.
+			quotes, err = []*client.Quote{}, nil
+		}
+		if err != nil {
+			return err
+		}
+
+		data, err := json.MarshalIndent(quotes, "", "  ")
+		if err != nil {
+			return err
+		}
+		data = append(data, '\n')
+		if *file == "-" {
+			_, err = c.stdout.Write(data)
+			return err
+		}
+		if err := os.WriteFile(*file, data, 0o644); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.stderr, "выгружено цитат: %d\n", len(quotes))
+		return nil
+	}
+}
+
+func pendingCommand(*flag.FlagSet) runFunc {
+	return func(ctx context.Context, c *cli, args []string) error {
+		if len(args) > 0 {
+			return usagef("pending не принимает аргументов")
+		}
+		queue, err := c.client.ModerationQueue(ctx)
+		if err != nil {
+			return err
+		}
+		return c.printQueue(queue)
+	}
+}
+
+func approveCommand(*flag.FlagSet) runFunc {
+	return func(ctx context.Context, c *cli, args []string) error {
+		if len(args) == 0 {
+			return usagef("использование: quotectl approve ID...")
+		}
+		ids, err := parseIDs(args)
+		if err != nil {
+			return err
+		}
+
+		approved := make([]*client.Quote, 0, len(ids))
+		for _, id := range ids {
+			quote, err := c.client.ApproveQuote(ctx, id)
+			if err != nil {
+				return fmt.Errorf("цитата %d: %w", id, err)
+			}
+			approved = append(approved, quote)
+		}
+		return c.printQuotes(approved)
+	}
+}
+
+func rejectCommand(fs *flag.FlagSet) runFunc {
+	reason := fs.String("reason", "", "причина отклонения")
+	return func(ctx context.Context, c *cli, args []string) error {
+		if strings.TrimSpace(*reason) == "" || len(args) == 0 {
+			return usagef("использование: quotectl reject -reason ПРИЧИНА ID...")
+		}
+		ids, err := parseIDs(args)
+		if err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			if _, err := c.client.RejectQuote(ctx, id, *reason); err != nil {
+				return fmt.Errorf("цитата %d: %w", id, err)
+			}
+			if c.output != outputJSON {
+				fmt.Fprintf(c.stdout, "цитата %d отклонена\n", id)
+			}
+		}
+		return nil
+	}
+}
+
+func authorCommand(*flag.FlagSet) runFunc {
+	return func(ctx context.Context, c *cli, args []string) error {
+		if len(args) == 0 {
+			return usagef("использование: quotectl author ИМЯ|ID")
+		}
+		author, err := c.client.Author(ctx, strings.Join(args, " "))
+		if err != nil {
+			return err
+		}
+		return c.printAuthor(author)
+	}
+}
+
+// authorSetCommand - Сведения заменяются целиком, как в PUT /authors/{author}: что не указано, очищается.
+func authorSetCommand(fs *flag.FlagSet) runFunc {
+	var update client.UpdateAuthor
+	authorFlags(fs, &update)
+	return func(ctx context.Context, c *cli, args []string) error {
+		if len(args) == 0 {
+			return usagef("использование: quotectl author-set [-full-name ...] [-born ГОД] [-died ГОД] [-alias ИМЯ]... [-link URL]... ИМЯ|ID")
+		}
+		author, err := c.client.UpdateAuthor(ctx, strings.Join(args, " "), &update)
+		if err != nil {
+			return err
+		}
+		return c.printAuthor(author)
+	}
+}
+
+// authorNewCommand - Заводит автора, в том числе тёзку уже существующего. Цитаты к нему добавляются
+// командой add -author-id.
+func authorNewCommand(fs *flag.FlagSet) runFunc {
+	var update client.UpdateAuthor
+	authorFlags(fs, &update)
+	return func(ctx context.Context, c *cli, args []string) error {
+		if len(args) == 0 {
+			return usagef("использование: quotectl author-new [-full-name ...] [-born ГОД] [-died ГОД] [-alias ИМЯ]... [-link URL]... ИМЯ")
+		}
+		author, err := c.client.CreateAuthor(ctx, &client.CreateAuthor{
+			Name:        strings.Join(args, " "),
+			FullName:    update.FullName,
+			BirthYear:   update.BirthYear,
+			DeathYear:   update.DeathYear,
+			Aliases:     update.Aliases,
+			Nationality: update.Nationality,
+			Bio:         update.Bio,
+			Links:       update.Links,
+		})
+		if err != nil {
+			return err
+		}
+		return c.printAuthor(author)
+	}
+}
+
+func authorMergeCommand(fs *flag.FlagSet) runFunc {
+	into := fs.Int("into", 0, "id автора, к которому переносятся цитаты")
+	return func(ctx context.Context, c *cli, args []string) error {
+		if *into <= 0 || len(args) == 0 {
+			return usagef("использование: quotectl author-merge -into ID ИМЯ|ID")
+		}
+		merge, err := c.client.MergeAuthors(ctx, strings.Join(args, " "), *into)
+		if err != nil {
+			return err
+		}
+		if c.output == outputJSON {
+			return c.printJSON(merge)
+		}
+		fmt.Fprintf(c.stdout, "перенесено цитат: %d, удалено дубликатов: %d\n", len(merge.Moved), len(merge.Dropped))
+		return c.printAuthor(merge.Author)
+	}
+}
+
+// authorFlags - Флаги сведений об авторе, общие для author-set и author-new.
+func authorFlags(fs *flag.FlagSet, update *client.UpdateAuthor) {
+	fs.StringVar(&update.FullName, "full-name", "", "полное имя")
+	fs.StringVar(&update.Nationality, "nationality", "", "национальность")
+	fs.StringVar(&update.Bio, "bio", "", "краткая биография")
+	fs.Func("born", "год рождения", yearFlag(&update.BirthYear))
+	fs.Func("died", "год смерти", yearFlag(&update.DeathYear))
+	fs.Func("link", "ссылка URL или ПОДПИСЬ=URL, можно несколько", func(value string) error {
+		link := client.AuthorLink{URL: value}
+		if title, u, ok := strings.Cut(value, "="); ok && !strings.Contains(title, "://") {
+			link = client.AuthorLink{Title: title, URL: u}
+		}
+		update.Links = append(update.Links, link)
+		return nil
+	})
+	fs.Func("alias", "другое имя автора, можно несколько", func(value string) error {
+		update.Aliases = append(update.Aliases, value)
+		return nil
+	})
+}
+
+func yearFlag(dst **int) func(string) error {
+	return func(value string) error {
+		year, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("некорректный год %q", value)
+		}
+		*dst = &year
+		return nil
+	}
+}
+
+// citeCommand - Ссылка на цитату или, с -author, на все цитаты автора. Ссылки BibTeX по автору
+// вместе составляют файл .bib, его удобно сразу записать через -file.
+func citeCommand(fs *flag.FlagSet) runFunc {
+	style := fs.String("style", "", "стиль: apa (по умолчанию), mla, chicago, bibtex")
+	author := fs.String("author", "", "все цитаты автора: имя или id")
+	file := fs.String("file", "-", "куда записать, - для stdout")
+	return func(ctx context.Context, c *cli, args []string) error {
+		var citations []*client.Citation
+		switch {
+		case *author != "" && len(args) == 0:
+			var err error
+			if citations, err = c.client.AuthorCitations(ctx, *author, *style); err != nil {
+				return err
+			}
+		case *author == "" && len(args) == 1:
+			ids, err := parseIDs(args)
+			if err != nil {
+				return err
+			}
+			citation, err := c.client.Cite(ctx, ids[0], *style)
+			if err != nil {
+				return err
+			}
+			citations = append(citations, citation)
+		default:
+			return usagef("использование: quotectl cite [-style СТИЛЬ] ID | quotectl cite -author ИМЯ|ID [-style bibtex] [-file путь]")
+		}
+
+		var out bytes.Buffer
+		if c.output == outputJSON {
+			data, err := json.MarshalIndent(citations, "", "  ")
+			if err != nil {
+				return err
+			}
+			out.Write(append(data, '\n'))
+		} else {
+			for i, citation := range citations {
+				if i > 0 {
+					out.WriteByte('\n')
+				}
+				out.WriteString(citation.Text + "\n")
+			}
+		}
+		if *file == "-" {
+			_, err := c.stdout.Write(out.Bytes())
+			return err
+		}
+		if err := os.WriteFile(*file, out.Bytes(), 0o644); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.stderr, "записано ссылок: %d\n", len(citations))
+		return nil
+	}
+}
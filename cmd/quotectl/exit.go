@@ -0,0 +1,65 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"go-offline-test/client"
+	"io"
+	"net"
+)
+
+const (
+	exitOK           = 0
+	exitError        = 1
+	exitUsage        = 2
+	exitNotFound     = 3
+	exitInvalid      = 4
+	exitUnauthorized = 5
+	exitConflict     = 6
+	exitUnavailable  = 7
+)
+
+// usageError - Команда вызвана неправильно.
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+func usagef(format string, args ...any) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+// exitCode - Печатает ошибку и подбирает код выхода по ошибке SDK.
+func exitCode(stderr io.Writer, err error) int {
+	if err == nil {
+		return exitOK
+	}
+	fmt.Fprintln(stderr, "ошибка:", err)
+
+	var usage *usageError
+	var apiErr *client.Error
+	var netErr net.Error
+	var opErr *net.OpError
+	switch {
+	case errors.As(err, &usage):
+		return exitUsage
+	case errors.Is(err, client.ErrNotFound):
+		return exitNotFound
+	case errors.Is(err, client.ErrValidation), errors.Is(err, client.ErrTooLarge):
+		return exitInvalid
+	case errors.Is(err, client.ErrUnauthorized), errors.Is(err, client.ErrForbidden), errors.Is(err, client.ErrQuotaExceeded):
+		return exitUnauthorized
+	case errors.Is(err, client.ErrAlreadyExists), errors.Is(err, client.ErrConflict):
+		return exitConflict
+	case errors.Is(err, client.ErrUnavailable):
+		return exitUnavailable
+	case errors.As(err, &apiErr):
+		return exitError
+	case errors.As(err, &opErr), errors.As(err, &netErr):
+		return exitUnavailable
+	}
+	return exitError
+}
@@ -0,0 +1,109 @@
+// quotectl - Клиент командной строки для сервиса цитат.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+)
+
+const usage = `Клиент сервиса цитат:
+  quotectl add -author ИМЯ ТЕКСТ        добавить цитату
+  quotectl add -author-id ID ТЕКСТ      добавить цитату одному из авторов-тёзок
+  quotectl add ... [-source НАЗВАНИЕ] [-source-type ВИД] [-publisher ...] [-year ГОД] [-pages 42-45] [-url URL] ТЕКСТ
+                                        добавить цитату с источником
+  quotectl list [-author ИМЯ]           все цитаты или цитаты автора
+  quotectl random                       случайная цитата
+  quotectl by-author ИМЯ                цитаты автора
+  quotectl delete ID...                 удалить цитаты
+  quotectl import [-file путь] [-keep-going]
+                                        загрузить цитаты из JSON (массив или по объекту в строке)
+  quotectl export [-file путь] [-author ИМЯ]
+                                        выгрузить цитаты в JSON
+  quotectl pending                      очередь модерации
+  quotectl approve ID...                одобрить цитаты с модерации
+  quotectl reject -reason ПРИЧИНА ID... отклонить цитаты с модерации
+  quotectl author ИМЯ|ID                автор и сведения о нём
+  quotectl author-set [-full-name ...] [-born ГОД] [-died ГОД] [-nationality ...] [-bio ...] [-alias ИМЯ]... [-link [ПОДПИСЬ=]URL]... ИМЯ|ID
+                                        заменить сведения об авторе
+  quotectl author-new [флаги author-set] ИМЯ
+                                        завести автора, в том числе тёзку
+  quotectl author-merge -into ID ИМЯ|ID перенести цитаты к другому автору, имя станет псевдонимом
+  quotectl cite [-style apa|mla|chicago|bibtex] ID
+                                        ссылка на цитату
+  quotectl cite -author ИМЯ|ID -style bibtex [-file путь]
+                                        ссылки на все цитаты автора, например файл .bib
+  quotectl profile list | use ИМЯ | set ИМЯ [-server URL] [-token T] ... | delete ИМЯ
+
+Общие флаги:
+  -profile ИМЯ   профиль из файла настроек (QUOTECTL_PROFILE)
+  -server URL    адрес сервиса, http(s)://host:port или unix:///путь.sock (QUOTECTL_SERVER)
+  -token T       bearer-токен (QUOTECTL_TOKEN)
+  -tenant ИМЯ    тенант (QUOTECTL_TENANT)
+  -o ФОРМАТ      table (по умолчанию), json или plain
+  -timeout 10s   предельное время одного запроса
+
+Профили хранятся в QUOTECTL_CONFIG или в каталоге настроек пользователя (quotectl/config.json).
+
+Коды выхода: 0 - успех, 1 - ошибка сервиса, 2 - неверный вызов, 3 - не найдено,
+4 - невалидные данные, 5 - нет доступа, 6 - уже существует, 7 - сервис недоступен.`
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	os.Exit(run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run - Разбирает команду и возвращает код выхода.
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "help" {
+		fmt.Fprintln(stderr, usage)
+		return exitUsage
+	}
+
+	// Общие флаги можно указать и перед командой: quotectl -profile prod list.
+	cli := &cli{stdin: stdin, stdout: stdout, stderr: stderr}
+	global := cli.flagSet("quotectl")
+	global.Usage = func() { fmt.Fprintln(stderr, usage) }
+	if err := global.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if global.NArg() == 0 {
+		fmt.Fprintln(stderr, usage)
+		return exitUsage
+	}
+
+	cmd, args := global.Arg(0), global.Args()[1:]
+	if cmd == "profile" {
+		return exitCode(stderr, runProfile(args, stdout, stderr))
+	}
+
+	setup, ok := commands[cmd]
+	if !ok {
+		fmt.Fprintf(stderr, "неизвестная команда %q\n\n%s\n", cmd, usage)
+		return exitUsage
+	}
+
+	fs := cli.flagSet("quotectl " + cmd)
+	runCommand := setup(fs)
+	args, err := parseArgs(fs, args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if err := cli.connect(); err != nil {
+		return exitCode(stderr, err)
+	}
+
+	return exitCode(stderr, runCommand(ctx, cli, args))
+}
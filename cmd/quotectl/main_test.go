@@ -0,0 +1,307 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"go-offline-test/client"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeService - Сервис цитат в памяти: отвечает как настоящий и запоминает запросы.
+type fakeService struct {
+	mu       sync.Mutex
+	requests []string
+}
+
+func (s *fakeService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	request := []string{r.Method, r.URL.Path}
+	for _, header := range []string{"author", "X-Tenant"} {
+		if v := r.Header.Get(header); v != "" {
+			request = append(request, v)
+		}
+	}
+	s.requests = append(s.requests, strings.Join(request, " "))
+	s.mu.Unlock()
+
+	if r.Header.Get("Authorization") != "Bearer secret" {
+		problem(w, http.StatusUnauthorized, client.CodeUnauthorized, "требуется токен")
+		return
+	}
+
+	quotes := []*client.Quote{
+		{ID: 2, Text: "Stay hungry, stay foolish", AuthorName: "Steve Jobs"},
+		{ID: 1, Text: "Думай иначе", AuthorName: "Steve Jobs"},
+	}
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/quotes":
+		if author := r.Header.Get("author"); author != "" && author != "Steve Jobs" {
+			problem(w, http.StatusNotFound, client.CodeAuthorNotFound, "автор не найден")
+			return
+		}
+		reply(w, http.StatusOK, quotes)
+	case r.Method == http.MethodGet && r.URL.Path == "/quotes/random":
+		reply(w, http.StatusOK, quotes[0])
+	case r.Method == http.MethodPost && r.URL.Path == "/quotes":
+		var quote client.Quote
+		if err := json.NewDecoder(r.Body).Decode(&quote); err != nil {
+			problem(w, http.StatusBadRequest, client.CodeMalformedBody, err.Error())
+			return
+		}
+		if quote.Text == quotes[0].Text {
+			problem(w, http.StatusConflict, client.CodeQuoteAlreadyExists, "цитата уже есть")
+			return
+		}
+		quote.ID = 3
+		reply(w, http.StatusCreated, quote)
+	case r.Method == http.MethodDelete && r.URL.Path == "/quotes/1":
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodDelete && r.URL.Path == "/quotes/5":
+		// Шлюз перед сервисом: ответ без кода ошибки.
+		http.Error(w, "bad gateway", http.StatusServiceUnavailable)
+	default:
+		problem(w, http.StatusNotFound, client.CodeQuoteNotFound, "цитата не найдена")
+	}
+}
+
+func reply(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func problem(w http.ResponseWriter, status int, code client.ErrorCode, detail string) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(client.Problem{Status: status, Detail: detail, Code: code})
+}
+
+// setupEnv - Изолирует тест от окружения и файла профилей пользователя.
+func setupEnv(t *testing.T) string {
+	t.Helper()
+	for _, env := range []string{"QUOTECTL_PROFILE", "QUOTECTL_SERVER", "QUOTECTL_TOKEN", "QUOTECTL_TENANT"} {
+		t.Setenv(env, "")
+	}
+	config := filepath.Join(t.TempDir(), "config.json")
+	t.Setenv("QUOTECTL_CONFIG", config)
+	return config
+}
+
+func TestRun(t *testing.T) {
+	service := &fakeService{}
+	srv := httptest.NewServer(service)
+	defer srv.Close()
+
+	tests := []struct {
+		name  string
+		args  []string
+		stdin string
+		code  int
+		// stdout - Ожидаемый вывод целиком, stderr - подстрока.
+		stdout, stderr string
+		// requests - Запросы к сервису по порядку: метод, путь, заголовки author и X-Tenant.
+		requests []string
+	}{
+		{
+			name:   "без команды",
+			code:   exitUsage,
+			stderr: "Клиент сервиса цитат",
+		},
+		{
+			name:   "неизвестная команда",
+			args:   []string{"-token", "secret", "quotes"},
+			code:   exitUsage,
+			stderr: `неизвестная команда "quotes"`,
+		},
+		{
+			name:     "list таблицей по id",
+			args:     []string{"-token", "secret", "list"},
+			stdout:   "ID  AUTHOR      QUOTE\n1   Steve Jobs  Думай иначе\n2   Steve Jobs  Stay hungry, stay foolish\n",
+			requests: []string{"GET /quotes"},
+		},
+		{
+			name:     "общие флаги после аргументов",
+			args:     []string{"by-author", "Steve", "Jobs", "-o", "plain", "-token", "secret", "-tenant", "acme"},
+			stdout:   "Думай иначе - Steve Jobs\nStay hungry, stay foolish - Steve Jobs\n",
+			requests: []string{"GET /quotes Steve Jobs acme"},
+		},
+		{
+			name:     "аргумент с дефисом после --",
+			args:     []string{"-token", "secret", "by-author", "--", "-o"},
+			code:     exitNotFound,
+			stderr:   "автор не найден",
+			requests: []string{"GET /quotes -o"},
+		},
+		{
+			name:     "random в json",
+			args:     []string{"-token", "secret", "-o", "json", "random"},
+			stdout:   "{\n  \"id\": 2,\n  \"quote\": \"Stay hungry, stay foolish\",\n  \"author\": \"Steve Jobs\",\n  \"created_at\": \"0001-01-01T00:00:00Z\"\n}\n",
+			requests: []string{"GET /quotes/random"},
+		},
+		{
+			name:     "add",
+			args:     []string{"-token", "secret", "add", "-author", "Steve Jobs", "-o", "plain", "Меньше", "значит", "больше"},
+			stdout:   "Меньше значит больше - Steve Jobs\n",
+			requests: []string{"POST /quotes"},
+		},
+		{
+			name:     "add дубликата",
+			args:     []string{"-token", "secret", "add", "-author", "Steve Jobs", "Stay hungry, stay foolish"},
+			code:     exitConflict,
+			stderr:   "цитата уже есть",
+			requests: []string{"POST /quotes"},
+		},
+		{
+			name:   "add без автора",
+			args:   []string{"-token", "secret", "add", "Текст"},
+			code:   exitUsage,
+			stderr: "использование: quotectl add",
+		},
+		{
+			name:     "delete останавливается на первой ошибке",
+			args:     []string{"-token", "secret", "delete", "1", "9", "1"},
+			code:     exitNotFound,
+			stdout:   "цитата 1 удалена\n",
+			stderr:   "цитата 9: цитата не найдена",
+			requests: []string{"DELETE /quotes/1", "DELETE /quotes/9"},
+		},
+		{
+			name:   "delete проверяет все id до запросов",
+			args:   []string{"-token", "secret", "delete", "1", "abc"},
+			code:   exitUsage,
+			stderr: `некорректный id "abc"`,
+		},
+		{
+			name:     "503 от шлюза без повтора",
+			args:     []string{"-token", "secret", "delete", "5"},
+			code:     exitUnavailable,
+			requests: []string{"DELETE /quotes/5"},
+		},
+		{
+			name:     "без токена",
+			args:     []string{"random"},
+			code:     exitUnauthorized,
+			stderr:   "требуется токен",
+			requests: []string{"GET /quotes/random"},
+		},
+		{
+			name:     "import пропускает дубликаты",
+			args:     []string{"-token", "secret", "import"},
+			stdin:    `{"quote":"Меньше значит больше","author":"Steve Jobs"}` + "\n" + `{"quote":"Stay hungry, stay foolish","author":"Steve Jobs"}`,
+			stderr:   "добавлено: 1, уже были: 1, с ошибкой: 0",
+			requests: []string{"POST /quotes", "POST /quotes"},
+		},
+		{
+			name:   "неизвестный формат вывода",
+			args:   []string{"-token", "secret", "-o", "xml", "list"},
+			code:   exitUsage,
+			stderr: `неизвестный формат вывода "xml"`,
+		},
+		{
+			name:   "неверная схема адреса",
+			args:   []string{"-token", "secret", "-server", "localhost:8080", "list"},
+			code:   exitUsage,
+			stderr: "адрес сервиса должен начинаться",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupEnv(t)
+			t.Setenv("QUOTECTL_SERVER", srv.URL)
+			service.requests = nil
+
+			var stdout, stderr bytes.Buffer
+			code := run(context.Background(), tt.args, strings.NewReader(tt.stdin), &stdout, &stderr)
+			if code != tt.code {
+				t.Errorf("код выхода %d, ожидался %d; stderr: %s", code, tt.code, stderr.String())
+			}
+			if got := stdout.String(); got != tt.stdout {
+				t.Errorf("stdout:\n%s\nожидалось:\n%s", got, tt.stdout)
+			}
+			if !strings.Contains(stderr.String(), tt.stderr) {
+				t.Errorf("stderr %q, ожидалась подстрока %q", stderr.String(), tt.stderr)
+			}
+			if got := strings.Join(service.requests, "\n"); got != strings.Join(tt.requests, "\n") {
+				t.Errorf("запросы:\n%s\nожидались:\n%s", got, strings.Join(tt.requests, "\n"))
+			}
+		})
+	}
+}
+
+// TestRunProfile - Профиль задаёт подключение, а флаги и переменные окружения его перекрывают.
+func TestRunProfile(t *testing.T) {
+	service := &fakeService{}
+	srv := httptest.NewServer(service)
+	defer srv.Close()
+	config := setupEnv(t)
+
+	var stdout, stderr bytes.Buffer
+	exec := func(args ...string) int {
+		t.Helper()
+		stdout.Reset()
+		stderr.Reset()
+		service.requests = nil
+		return run(context.Background(), args, strings.NewReader(""), &stdout, &stderr)
+	}
+
+	if code := exec("profile", "set", "prod", "-server", srv.URL, "-token", "secret", "-tenant", "acme"); code != exitOK {
+		t.Fatalf("profile set: код %d, %s", code, stderr.String())
+	}
+	if code := exec("profile", "set", "broken", "-server", "http://127.0.0.1:1", "-token", "wrong"); code != exitOK {
+		t.Fatalf("profile set: код %d, %s", code, stderr.String())
+	}
+	info, err := os.Stat(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("права файла профилей %o, ожидалось 600: в нём токены", perm)
+	}
+
+	if code := exec("profile", "list"); code != exitOK {
+		t.Fatalf("profile list: код %d", code)
+	}
+	var rows []string
+	for _, line := range strings.Split(strings.TrimSpace(stdout.String()), "\n") {
+		rows = append(rows, strings.Join(strings.Fields(line), " "))
+	}
+	if got, want := strings.Join(rows, "\n"), "NAME SERVER TENANT\nbroken http://127.0.0.1:1\n* prod "+srv.URL+" acme"; got != want {
+		t.Errorf("profile list:\n%s\nожидалось:\n%s", got, want)
+	}
+
+	// Первый сохранённый профиль становится текущим.
+	if code := exec("random"); code != exitOK {
+		t.Errorf("random с текущим профилем: код %d, %s", code, stderr.String())
+	}
+	if got := strings.Join(service.requests, "\n"); got != "GET /quotes/random acme" {
+		t.Errorf("запросы %q, ожидался запрос в тенант acme", got)
+	}
+
+	// Адрес и токен из флагов перекрывают профиль broken.
+	if code := exec("-profile", "broken", "-server", srv.URL, "-token", "secret", "random"); code != exitOK {
+		t.Errorf("random с перекрытым профилем: код %d, %s", code, stderr.String())
+	}
+	t.Setenv("QUOTECTL_TOKEN", "")
+	t.Setenv("QUOTECTL_SERVER", srv.URL)
+	if code := exec("-profile", "broken", "random"); code != exitUnauthorized {
+		t.Errorf("токен профиля broken: код %d, ожидался %d", code, exitUnauthorized)
+	}
+
+	if code := exec("-profile", "staging", "random"); code != exitUsage || !strings.Contains(stderr.String(), `профиль "staging" не найден`) {
+		t.Errorf("несуществующий профиль: код %d, %s", code, stderr.String())
+	}
+	if code := exec("profile", "delete", "prod"); code != exitOK {
+		t.Fatalf("profile delete: код %d", code)
+	}
+	t.Setenv("QUOTECTL_SERVER", "")
+	if code := exec("profile", "use", "prod"); code != exitUsage {
+		t.Errorf("profile use удалённого: код %d, ожидался %d", code, exitUsage)
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-offline-test/client"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+func (c *cli) printQuote(quote *client.Quote) error {
+	if c.output == outputJSON {
+		return c.printJSON(quote)
+	}
+	return c.printQuotes([]*client.Quote{quote})
+}
+
+// printQuotes - Цитаты в выбранном формате. В таблице и тексте упорядочены по id.
+func (c *cli) printQuotes(quotes []*client.Quote) error {
+	sort.Slice(quotes, func(i, j int) bool { return quotes[i].ID < quotes[j].ID })
+
+	switch c.output {
+	case outputJSON:
+		return c.printJSON(quotes)
+	case outputPlain:
+		for _, q := range quotes {
+			fmt.Fprintf(c.stdout, "%s - %s\n", q.Text, q.AuthorName)
+		}
+		return nil
+	default:
+		tw := tabwriter.NewWriter(c.stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tAUTHOR\tQUOTE")
+		for _, q := range quotes {
+			fmt.Fprintf(tw, "%d\t%s\t%s\n", q.ID, q.AuthorName, q.Text)
+		}
+		return tw.Flush()
+	}
+}
+
+// printQueue - Очередь модерации от старых цитат к новым, как её отдаёт сервис.
+func (c *cli) printQueue(queue *client.ModerationQueue) error {
+	switch c.output {
+	case outputJSON:
+		return c.printJSON(queue)
+	case outputPlain:
+		for _, q := range queue.Items {
+			fmt.Fprintf(c.stdout, "%d\t%s - %s\n", q.ID, q.Text, q.AuthorName)
+		}
+		return nil
+	default:
+		tw := tabwriter.NewWriter(c.stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tWAIT\tCREATED BY\tFLAGS\tAUTHOR\tQUOTE")
+		for _, q := range queue.Items {
+			wait := time.Duration(q.WaitSeconds) * time.Second
+			flags := strings.Join(q.Flags, ",")
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\n", q.ID, wait, q.CreatedBy, flags, q.AuthorName, q.Text)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.stdout, "на модерации: %d\n", queue.Pending)
+		return nil
+	}
+}
+
+// printAuthor - Автор и заполненные сведения о нём, по полю в строке.
+func (c *cli) printAuthor(author *client.Author) error {
+	if c.output == outputJSON {
+		return c.printJSON(author)
+	}
+
+	tw := tabwriter.NewWriter(c.stdout, 0, 4, 2, ' ', 0)
+	field := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(tw, "%s:\t%s\n", name, value)
+		}
+	}
+	year := func(y *int) string {
+		if y == nil {
+			return ""
+		}
+		return strconv.Itoa(*y)
+	}
+	field("id", strconv.Itoa(author.ID))
+	field("name", author.Name)
+	field("full name", author.FullName)
+	field("born", year(author.BirthYear))
+	field("died", year(author.DeathYear))
+	field("aliases", strings.Join(author.Aliases, ", "))
+	field("nationality", author.Nationality)
+	field("bio", author.Bio)
+	for _, link := range author.Links {
+		field("link", strings.TrimSpace(link.Title+" "+link.URL))
+	}
+	field("quotes", strconv.Itoa(author.Quotes))
+	return tw.Flush()
+}
+
+func (c *cli) printJSON(v any) error {
+	enc := json.NewEncoder(c.stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+)
+
+// profile - Настройки подключения к одному серверу.
+type profile struct {
+	Server string `json:"server"`
+	Token  string `json:"token,omitempty"`
+	Tenant string `json:"tenant,omitempty"`
+	// CAFile - CA для проверки сертификата сервера, например самоподписанного.
+	CAFile string `json:"ca_file,omitempty"`
+	// CertFile, KeyFile - Клиентский сертификат для mTLS.
+	CertFile string `json:"cert_file,omitempty"`
+	KeyFile  string `json:"key_file,omitempty"`
+	// Insecure - Не проверять сертификат сервера. Только для разработки.
+	Insecure bool `json:"insecure,omitempty"`
+}
+
+// profiles - Файл профилей. Current - профиль, который используется без -profile.
+type profiles struct {
+	Current  string              `json:"current,omitempty"`
+	Profiles map[string]*profile `json:"profiles"`
+}
+
+func profilesPath() (string, error) {
+	if path := os.Getenv("QUOTECTL_CONFIG"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "quotectl", "config.json"), nil
+}
+
+// loadProfiles - Читает файл профилей. Отсутствующий файл - пустой набор.
+func loadProfiles() (*profiles, string, error) {
+	path, err := profilesPath()
+	if err != nil {
+		return nil, "", err
+	}
+
+	p := &profiles{Profiles: map[string]*profile{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return p, path, nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, "", fmt.Errorf("файл профилей %s: %w", path, err)
+	}
+	if p.Profiles == nil {
+		p.Profiles = map[string]*profile{}
+	}
+	return p, path, nil
+}
+
+// save - Записывает профили с правами 0600: в них хранятся токены.
+func (p *profiles) save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
+
+// runProfile - Управление профилями: list, use, set, delete.
+func runProfile(args []string, stdout, stderr io.Writer) error {
+	if len(args) == 0 {
+		return usagef("укажите list, use, set или delete")
+	}
+
+	p, path, err := loadProfiles()
+	if err != nil {
+		return err
+	}
+
+	cmd, args := args[0], args[1:]
+	switch cmd {
+	case "list":
+		names := make([]string, 0, len(p.Profiles))
+		for name := range p.Profiles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		tw := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "\tNAME\tSERVER\tTENANT")
+		for _, name := range names {
+			mark := ""
+			if name == p.Current {
+				mark = "*"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", mark, name, p.Profiles[name].Server, p.Profiles[name].Tenant)
+		}
+		return tw.Flush()
+
+	case "use":
+		if len(args) != 1 {
+			return usagef("укажите имя профиля")
+		}
+		if _, ok := p.Profiles[args[0]]; !ok {
+			return usagef("профиль %q не найден", args[0])
+		}
+		p.Current = args[0]
+		return p.save(path)
+
+	case "set":
+		if len(args) == 0 || args[0] == "" || args[0][0] == '-' {
+			return usagef("укажите имя профиля перед флагами")
+		}
+		name := args[0]
+		pr, ok := p.Profiles[name]
+		if !ok {
+			pr = &profile{}
+		}
+
+		fset := flag.NewFlagSet("quotectl profile set", flag.ContinueOnError)
+		fset.SetOutput(stderr)
+		fset.StringVar(&pr.Server, "server", pr.Server, "адрес сервиса")
+		fset.StringVar(&pr.Token, "token", pr.Token, "bearer-токен")
+		fset.StringVar(&pr.Tenant, "tenant", pr.Tenant, "тенант")
+		fset.StringVar(&pr.CAFile, "ca-file", pr.CAFile, "CA для проверки сертификата сервера")
+		fset.StringVar(&pr.CertFile, "cert-file", pr.CertFile, "клиентский сертификат для mTLS")
+		fset.StringVar(&pr.KeyFile, "key-file", pr.KeyFile, "ключ клиентского сертификата")
+		fset.BoolVar(&pr.Insecure, "insecure", pr.Insecure, "не проверять сертификат сервера")
+		if err := fset.Parse(args[1:]); err != nil {
+			return usagef("%v", err)
+		}
+		if pr.Server == "" {
+			return usagef("у профиля должен быть -server")
+		}
+
+		p.Profiles[name] = pr
+		if p.Current == "" {
+			p.Current = name
+		}
+		return p.save(path)
+
+	case "delete":
+		if len(args) != 1 {
+			return usagef("укажите имя профиля")
+		}
+		if _, ok := p.Profiles[args[0]]; !ok {
+			return usagef("профиль %q не найден", args[0])
+		}
+		delete(p.Profiles, args[0])
+		if p.Current == args[0] {
+			p.Current = ""
+		}
+		return p.save(path)
+
+	default:
+		return usagef("неизвестная команда profile %s", cmd)
+	}
+}
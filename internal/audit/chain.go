@@ -0,0 +1,64 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var ErrChainBroken = errors.New("нарушена цепочка хешей журнала аудита")
+
+// hashLine - SHA-256 от JSON записи без поля hash. Ключи сортируются, значения берутся
+// байт в байт, поэтому хеш не зависит от того, какие поля знает текущая версия dto.
+// prev_hash входит в хеш, так что изменение или удаление любой записи ломает все последующие.
+func hashLine(line []byte) (string, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(line, &fields); err != nil {
+		return "", err
+	}
+	delete(fields, "hash")
+
+	canonical, err := json.Marshal(fields)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func verify(records []record) error {
+	prev := ""
+	var seq int64
+	for _, r := range records {
+		e := r.entry
+		if e.Seq != seq+1 {
+			return fmt.Errorf("%w: после seq=%d идёт seq=%d", ErrChainBroken, seq, e.Seq)
+		}
+		if e.PrevHash != prev {
+			return fmt.Errorf("%w: seq=%d ссылается на чужой prev_hash", ErrChainBroken, e.Seq)
+		}
+		hash, err := hashLine(r.line)
+		if err != nil {
+			return err
+		}
+		if hash != e.Hash {
+			return fmt.Errorf("%w: у seq=%d не совпадает hash", ErrChainBroken, e.Seq)
+		}
+		prev, seq = e.Hash, e.Seq
+	}
+	return nil
+}
+
+// VerifyFile - Проверяет целостность файла аудита и возвращает число записей.
+func VerifyFile(path string) (int, error) {
+	records, err := readFile(path)
+	if err != nil {
+		return 0, err
+	}
+	if err := verify(records); err != nil {
+		return 0, err
+	}
+	return len(records), nil
+}
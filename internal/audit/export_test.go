@@ -0,0 +1,11 @@
+package audit
+
+import "os"
+
+// File - Файл журнала для подмены в тестах.
+type File = file
+
+// WrapFile - Подменяет файл журнала обёрткой, которая может имитировать сбои записи.
+func WrapFile(l *Log, wrap func(*os.File) File) {
+	l.file = wrap(l.file.(*os.File))
+}
@@ -0,0 +1,278 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-offline-test/internal/auth"
+	"go-offline-test/internal/shared"
+	"go-offline-test/internal/shared/dto"
+	"io"
+	"os"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	OpQuoteAdd     = "quote.add"
+	OpQuoteDelete  = "quote.delete"
+	OpQuoteUpdate  = "quote.update"
+	OpQuoteApprove = "quote.approve"
+	OpQuoteReject  = "quote.reject"
+	OpAuthorCreate = "author.create"
+	OpAuthorUpdate = "author.update"
+	OpAuthorMerge  = "author.merge"
+)
+
+// Change - Что изменила операция: цитата или автор до и после. nil - объекта до операции не было
+// или после не стало.
+type Change struct {
+	Before, After             *dto.Quote
+	AuthorBefore, AuthorAfter *dto.Author
+	// MergedFrom, MovedAliases - При объединении: удалённый автор и имена, ставшие псевдонимами AuthorAfter.
+	MergedFrom   *dto.Author
+	MovedAliases []string
+}
+
+// Log - Журнал изменяющих операций. Записи только добавляются.
+// В памяти хранятся последние maxEntries записей, при заданном файле все записи
+// дописываются в него построчно в JSON со сцепкой хешей.
+type Log struct {
+	entries    []*dto.AuditEntry
+	maxEntries int
+	seq        int64
+	lastHash   string
+	file       file
+	// size - Длина файла до конца последней целиком записанной строки.
+	size int64
+	mu   sync.RWMutex
+}
+
+// file - Файл журнала. *os.File в работе, в тестах - обёртка, которая имитирует сбои записи.
+type file interface {
+	io.Writer
+	Truncate(size int64) error
+	Sync() error
+	Close() error
+}
+
+// NewLog - Создаёт журнал. Если path не пуст, существующий файл проверяется и продолжается.
+// maxEntries = 0 - хранить в памяти все записи.
+func NewLog(path string, maxEntries int) (*Log, error) {
+	l := &Log{maxEntries: maxEntries}
+	if path == "" {
+		return l, nil
+	}
+
+	records, err := readFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := verify(records); err != nil {
+		return nil, fmt.Errorf("файл аудита %s повреждён: %w", path, err)
+	}
+	for _, r := range records {
+		l.append(r.entry)
+	}
+	if n := len(records); n > 0 {
+		l.seq = records[n-1].entry.Seq
+		l.lastHash = records[n-1].entry.Hash
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	l.file, l.size = f, info.Size()
+	return l, nil
+}
+
+// Record - Записывает операцию. Исполнитель, IP, id запроса и тенант берутся из контекста.
+func (l *Log) Record(ctx context.Context, op string, change Change) error {
+	meta := shared.RequestMetaFromContext(ctx)
+	entry := &dto.AuditEntry{
+		Time:         time.Now().UTC(),
+		Operation:    op,
+		Tenant:       shared.TenantFromContext(ctx),
+		ClientIP:     meta.ClientIP,
+		RequestID:    meta.ID,
+		Before:       snapshot(change.Before),
+		After:        snapshot(change.After),
+		AuthorBefore: authorSnapshot(change.AuthorBefore),
+		AuthorAfter:  authorSnapshot(change.AuthorAfter),
+		MergedFrom:   authorSnapshot(change.MergedFrom),
+		MovedAliases: slices.Clone(change.MovedAliases),
+	}
+	if principal, ok := auth.PrincipalFromContext(ctx); ok {
+		entry.Actor = principal.Subject
+	}
+	switch {
+	case change.After != nil:
+		entry.QuoteID = change.After.ID
+	case change.Before != nil:
+		entry.QuoteID = change.Before.ID
+	}
+	switch {
+	case change.AuthorAfter != nil:
+		entry.AuthorID = change.AuthorAfter.ID
+	case change.AuthorBefore != nil:
+		entry.AuthorID = change.AuthorBefore.ID
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry.Seq = l.seq + 1
+	if l.file != nil {
+		entry.PrevHash = l.lastHash
+		unsigned, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		if entry.Hash, err = hashLine(unsigned); err != nil {
+			return err
+		}
+
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		line = append(line, '\n')
+		if _, err := l.file.Write(line); err != nil {
+			// Обрывок строки сломал бы сцепку для следующих записей и проверку файла при запуске.
+			if truncErr := l.file.Truncate(l.size); truncErr != nil {
+				return errors.Join(err, fmt.Errorf("не удалось отрезать недописанную запись: %w", truncErr))
+			}
+			return err
+		}
+		// Строка уже в файле, поэтому сцепка продолжается от неё, даже если Sync не удался:
+		// иначе следующая запись сослалась бы на предыдущий хеш и нарушила цепочку.
+		l.lastHash = entry.Hash
+		l.size += int64(len(line))
+	}
+	l.seq = entry.Seq
+	l.append(entry)
+
+	if l.file != nil {
+		return l.file.Sync()
+	}
+	return nil
+}
+
+// Query - Возвращает записи, подходящие под фильтр, от новых к старым.
+func (l *Log) Query(ctx context.Context, filter dto.AuditFilter) ([]*dto.AuditEntry, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	result := make([]*dto.AuditEntry, 0)
+	for i := len(l.entries) - 1; i >= 0; i-- {
+		e := l.entries[i]
+		if !matches(e, filter) {
+			continue
+		}
+		result = append(result, e)
+		if filter.Limit > 0 && len(result) >= filter.Limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
+
+func (l *Log) append(e *dto.AuditEntry) {
+	l.entries = append(l.entries, e)
+	if l.maxEntries > 0 && len(l.entries) > l.maxEntries {
+		// Копируем хвост, чтобы не держать в памяти вытесненные записи.
+		l.entries = append([]*dto.AuditEntry(nil), l.entries[len(l.entries)-l.maxEntries:]...)
+	}
+}
+
+func matches(e *dto.AuditEntry, f dto.AuditFilter) bool {
+	switch {
+	case f.Actor != "" && e.Actor != f.Actor,
+		f.Operation != "" && e.Operation != f.Operation,
+		f.Tenant != "" && e.Tenant != f.Tenant,
+		f.RequestID != "" && e.RequestID != f.RequestID,
+		f.QuoteID != 0 && e.QuoteID != f.QuoteID,
+		f.AuthorID != 0 && e.AuthorID != f.AuthorID && (e.MergedFrom == nil || e.MergedFrom.ID != f.AuthorID),
+		!f.Since.IsZero() && e.Time.Before(f.Since),
+		!f.Until.IsZero() && !e.Time.Before(f.Until):
+		return false
+	}
+	return true
+}
+
+func snapshot(q *dto.Quote) *dto.Quote {
+	if q == nil {
+		return nil
+	}
+	c := *q
+	return &c
+}
+
+// authorSnapshot - Копия автора: списки тоже копируются, чтобы запись не менялась вместе с DTO.
+func authorSnapshot(a *dto.Author) *dto.Author {
+	if a == nil {
+		return nil
+	}
+	c := *a
+	c.Aliases = slices.Clone(a.Aliases)
+	c.Links = slices.Clone(a.Links)
+	return &c
+}
+
+// record - Запись из файла вместе с исходной строкой, по которой считается хеш.
+type record struct {
+	entry *dto.AuditEntry
+	line  []byte
+}
+
+func readFile(path string) ([]record, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []record
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for n := 1; scanner.Scan(); n++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		line := append([]byte(nil), scanner.Bytes()...)
+		var e dto.AuditEntry
+		if err := json.Unmarshal(line, &e); err != nil {
+			return nil, fmt.Errorf("строка %d: %w", n, err)
+		}
+		records = append(records, record{entry: &e, line: line})
+	}
+	return records, scanner.Err()
+}
@@ -0,0 +1,204 @@
+package audit_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"go-offline-test/internal/audit"
+	"go-offline-test/internal/shared/dto"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// faultyFile - Файл журнала, который дописывает половину строки и падает или не может выполнить Sync.
+type faultyFile struct {
+	*os.File
+	failWrite, failSync bool
+}
+
+var errDiskFull = errors.New("no space left on device")
+
+func (f *faultyFile) Write(p []byte) (int, error) {
+	if f.failWrite {
+		f.failWrite = false
+		n, _ := f.File.Write(p[:len(p)/2])
+		return n, errDiskFull
+	}
+	return f.File.Write(p)
+}
+
+func (f *faultyFile) Sync() error {
+	if f.failSync {
+		f.failSync = false
+		return errDiskFull
+	}
+	return f.File.Sync()
+}
+
+func record(t *testing.T, l *audit.Log, id int) error {
+	t.Helper()
+	return l.Record(context.Background(), audit.OpQuoteAdd, audit.Change{After: &dto.Quote{ID: id, Text: "Цитата", AuthorName: "Автор"}})
+}
+
+func openLog(t *testing.T, path string) *audit.Log {
+	t.Helper()
+	l, err := audit.NewLog(path, 0)
+	if err != nil {
+		t.Fatalf("NewLog(): %v", err)
+	}
+	t.Cleanup(func() { l.Close() })
+	return l
+}
+
+func TestLogChain(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	l := openLog(t, path)
+	for id := 1; id <= 3; id++ {
+		if err := record(t, l, id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	l.Close()
+
+	if n, err := audit.VerifyFile(path); n != 3 || err != nil {
+		t.Fatalf("VerifyFile() = %d, %v, ожидалось 3 записи", n, err)
+	}
+
+	// Журнал продолжается после перезапуска: seq и сцепка идут дальше.
+	l = openLog(t, path)
+	if err := record(t, l, 4); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := l.Query(context.Background(), dto.AuditFilter{Limit: 1})
+	if err != nil || len(entries) != 1 || entries[0].Seq != 4 {
+		t.Fatalf("Query() = %+v, %v, ожидалась запись seq=4", entries, err)
+	}
+	l.Close()
+	if n, err := audit.VerifyFile(path); n != 4 || err != nil {
+		t.Fatalf("VerifyFile() после перезапуска = %d, %v", n, err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := bytes.SplitAfter(data, []byte("\n"))
+	tests := []struct {
+		name string
+		edit func() []byte
+	}{
+		{"изменена запись", func() []byte {
+			return bytes.Replace(data, []byte(`"quote_id":2`), []byte(`"quote_id":7`), 1)
+		}},
+		{"удалена запись из середины", func() []byte {
+			return bytes.Join([][]byte{lines[0], lines[2], lines[3]}, nil)
+		}},
+		{"записи переставлены", func() []byte {
+			return bytes.Join([][]byte{lines[1], lines[0], lines[2], lines[3]}, nil)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broken := filepath.Join(t.TempDir(), "audit.log")
+			if err := os.WriteFile(broken, tt.edit(), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := audit.VerifyFile(broken); !errors.Is(err, audit.ErrChainBroken) {
+				t.Errorf("VerifyFile() = %v, ожидалась %v", err, audit.ErrChainBroken)
+			}
+			if _, err := audit.NewLog(broken, 0); !errors.Is(err, audit.ErrChainBroken) {
+				t.Errorf("NewLog() повреждённого файла = %v, ожидалась %v", err, audit.ErrChainBroken)
+			}
+		})
+	}
+}
+
+// TestLogFailedWrite - Обрывок строки после сбоя записи отрезается, и следующая запись продолжает цепочку.
+func TestLogFailedWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	l := openLog(t, path)
+	var faulty *faultyFile
+	audit.WrapFile(l, func(f *os.File) audit.File {
+		faulty = &faultyFile{File: f}
+		return faulty
+	})
+
+	if err := record(t, l, 1); err != nil {
+		t.Fatal(err)
+	}
+	faulty.failWrite = true
+	if err := record(t, l, 2); !errors.Is(err, errDiskFull) {
+		t.Fatalf("Record() при сбое записи = %v, ожидалась %v", err, errDiskFull)
+	}
+	if n, err := audit.VerifyFile(path); n != 1 || err != nil {
+		t.Fatalf("VerifyFile() после сбоя = %d, %v: обрывок строки остался в файле", n, err)
+	}
+	if err := record(t, l, 3); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := audit.VerifyFile(path); n != 2 || err != nil {
+		t.Fatalf("VerifyFile() после следующей записи = %d, %v", n, err)
+	}
+
+	entries, err := l.Query(context.Background(), dto.AuditFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []int
+	for _, e := range entries {
+		ids = append(ids, e.QuoteID)
+	}
+	if len(ids) != 2 || ids[0] != 3 || ids[1] != 1 {
+		t.Errorf("в памяти записи %v, ожидались 3 и 1: несохранённая запись не должна попасть в журнал", ids)
+	}
+}
+
+// TestLogFailedSync - Строка уже в файле, поэтому после неудачного Sync цепочка продолжается от неё.
+func TestLogFailedSync(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	l := openLog(t, path)
+	var faulty *faultyFile
+	audit.WrapFile(l, func(f *os.File) audit.File {
+		faulty = &faultyFile{File: f}
+		return faulty
+	})
+
+	faulty.failSync = true
+	if err := record(t, l, 1); !errors.Is(err, errDiskFull) {
+		t.Fatalf("Record() при сбое Sync = %v, ожидалась %v", err, errDiskFull)
+	}
+	if err := record(t, l, 2); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := audit.VerifyFile(path); n != 2 || err != nil {
+		t.Fatalf("VerifyFile() = %d, %v, ожидалось 2 записи", n, err)
+	}
+}
+
+func TestLogMemory(t *testing.T) {
+	l, err := audit.NewLog("", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for id := 1; id <= 3; id++ {
+		if err := record(t, l, id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	entries, err := l.Query(context.Background(), dto.AuditFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].QuoteID != 3 || entries[1].QuoteID != 2 {
+		t.Errorf("Query() вернул %d записей, ожидались последние две от новых к старым", len(entries))
+	}
+	for _, e := range entries {
+		if e.Hash != "" || e.PrevHash != "" {
+			t.Errorf("без файла записи не сцепляются: %+v", e)
+		}
+	}
+	if entries, _ := l.Query(context.Background(), dto.AuditFilter{QuoteID: 1}); len(entries) != 0 {
+		t.Error("вытесненная запись осталась в памяти")
+	}
+}
@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"slices"
+	"strings"
+	"time"
+)
+
+const tokenPrefix = "qk"
+
+// APIKey - Запись о ключе. Сам токен не хранится, только его хеш.
+type APIKey struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Hash      string     `json:"hash"`
+	Scopes    []Scope    `json:"scopes"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	RotatedAt *time.Time `json:"rotated_at,omitempty"`
+}
+
+// Status - Возвращает ошибку, если ключ нельзя использовать в момент now.
+func (k *APIKey) Status(now time.Time) error {
+	if k.RevokedAt != nil {
+		return ErrKeyRevoked
+	}
+	if k.ExpiresAt != nil && !now.Before(*k.ExpiresAt) {
+		return ErrKeyExpired
+	}
+	return nil
+}
+
+// clone - Копия записи: снаружи хранилища ключи меняются только через его методы.
+func (k *APIKey) clone() *APIKey {
+	c := *k
+	c.Scopes = slices.Clone(k.Scopes)
+	c.ExpiresAt = cloneTime(k.ExpiresAt)
+	c.RevokedAt = cloneTime(k.RevokedAt)
+	c.RotatedAt = cloneTime(k.RotatedAt)
+	return &c
+}
+
+func cloneTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	c := *t
+	return &c
+}
+
+// newToken - Генерирует токен вида qk_<id>_<secret>.
+func newToken(id string) (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return tokenPrefix + "_" + id + "_" + base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// newKeyID - Генерирует идентификатор ключа.
+func newKeyID() (string, error) {
+	id := make([]byte, 6)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
+
+// parseToken - Достаёт id ключа из токена.
+func parseToken(token string) (string, bool) {
+	parts := strings.SplitN(token, "_", 3)
+	if len(parts) != 3 || parts[0] != tokenPrefix || parts[1] == "" || parts[2] == "" {
+		return "", false
+	}
+	return parts[1], true
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// matches - Сравнивает токен с хешем за постоянное время.
+func (k *APIKey) matches(token string) bool {
+	return subtle.ConstantTimeCompare([]byte(k.Hash), []byte(hashToken(token))) == 1
+}
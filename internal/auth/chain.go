@@ -0,0 +1,25 @@
+package auth
+
+import "context"
+
+// Chain - Перебирает способы аутентификации по очереди.
+// Следующий способ пробуется, только если предыдущий не распознал формат токена.
+type Chain []IAuthenticator
+
+func NewChain(authenticators ...IAuthenticator) Chain {
+	return Chain(authenticators)
+}
+
+func (c Chain) Authenticate(ctx context.Context, token string) (*Principal, error) {
+	for _, a := range c {
+		principal, err := a.Authenticate(ctx, token)
+		if err == nil {
+			return principal, nil
+		}
+		// Голый ErrInvalidToken без обёртки означает "формат токена не мой" - пробуем следующий способ.
+		if err != ErrInvalidToken {
+			return nil, err
+		}
+	}
+	return nil, ErrInvalidToken
+}
@@ -0,0 +1,33 @@
+package auth
+
+import (
+	"context"
+	"crypto/x509"
+)
+
+// ClientCertAuthenticator - Аутентификация по клиентскому сертификату, проверенному при mTLS-рукопожатии.
+// Права выдаются по CN или полному subject сертификата.
+type ClientCertAuthenticator struct {
+	scopes map[string][]Scope
+}
+
+func NewClientCertAuthenticator(scopes map[string][]Scope) *ClientCertAuthenticator {
+	return &ClientCertAuthenticator{scopes: scopes}
+}
+
+// AuthenticateCert - Возвращает клиента для проверенного сертификата. Сертификат подписан
+// доверенным CA, поэтому клиент без сопоставленных прав известен, но получает 403.
+func (a *ClientCertAuthenticator) AuthenticateCert(_ context.Context, cert *x509.Certificate) (*Principal, error) {
+	subject := cert.Subject.String()
+	scopes, ok := a.scopes[subject]
+	if !ok {
+		scopes = a.scopes[cert.Subject.CommonName]
+	}
+
+	return &Principal{
+		Subject: "cert:" + cert.Subject.CommonName,
+		Name:    subject,
+		Method:  MethodClientCert,
+		Scopes:  scopes,
+	}, nil
+}
@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"errors"
+	"go-offline-test/internal/i18n"
+)
+
+var (
+	ErrMissingToken  = i18n.NewError("auth.missing_token")
+	ErrInvalidToken  = i18n.NewError("auth.invalid_token")
+	ErrKeyNotFound   = i18n.NewError("auth.key_not_found")
+	ErrKeyRevoked    = i18n.NewError("auth.key_revoked")
+	ErrKeyExpired    = i18n.NewError("auth.key_expired")
+	ErrForbidden     = i18n.NewError("auth.forbidden")
+	ErrUnknownScope  = i18n.NewError("auth.unknown_scope")
+	ErrNoScopes      = i18n.NewError("auth.no_scopes")
+	ErrEmptyKeyName  = i18n.NewError("auth.empty_key_name")
+	ErrInvalidExpiry = i18n.NewError("auth.invalid_expiry")
+
+	ErrTokenExpired     = i18n.NewError("auth.token_expired")
+	ErrTokenNotYetValid = i18n.NewError("auth.token_not_yet_valid")
+	ErrInvalidIssuer    = i18n.NewError("auth.invalid_issuer")
+	ErrInvalidAudience  = i18n.NewError("auth.invalid_audience")
+)
+
+// IsUnauthenticated - Сообщает, что ошибка вызвана неверными учётными данными клиента, а не сбоем сервиса.
+func IsUnauthenticated(err error) bool {
+	for _, target := range []error{
+		ErrMissingToken, ErrInvalidToken, ErrKeyRevoked, ErrKeyExpired,
+		ErrTokenExpired, ErrTokenNotYetValid, ErrInvalidIssuer, ErrInvalidAudience,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
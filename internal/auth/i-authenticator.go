@@ -0,0 +1,8 @@
+package auth
+
+import "context"
+
+type IAuthenticator interface {
+	// Authenticate - Проверяет bearer-токен и возвращает клиента.
+	Authenticate(ctx context.Context, token string) (*Principal, error)
+}
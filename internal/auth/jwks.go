@@ -0,0 +1,241 @@
+package auth
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// minReloadInterval - Как часто можно перечитывать JWKS вне расписания: при неизвестном kid
+	// или после неудачного обновления.
+	minReloadInterval = time.Minute
+	maxJWKSSize       = 1 << 20
+)
+
+// jwk - Ключ в формате RFC 7517. Поддерживаются oct (HS256), RSA (RS256) и OKP/Ed25519 (EdDSA).
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	K   string `json:"k"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+}
+
+// verificationKey - Разобранный ключ, готовый к проверке подписи.
+type verificationKey struct {
+	kid string
+	alg string
+	// key - []byte для HS256, *rsa.PublicKey для RS256, ed25519.PublicKey для EdDSA.
+	key any
+}
+
+// JWKS - Набор ключей из локального файла или по URL с периодическим обновлением.
+type JWKS struct {
+	source      string
+	refresh     time.Duration
+	client      *http.Client
+	keys        []verificationKey
+	loadedAt    time.Time
+	lastAttempt time.Time
+	reloading   bool
+	mu          sync.RWMutex
+}
+
+func NewJWKS(ctx context.Context, source string, refresh time.Duration) (*JWKS, error) {
+	j := &JWKS{
+		source:  source,
+		refresh: refresh,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+	if err := j.load(ctx); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// lookup - Возвращает ключи, подходящие под kid и алгоритм токена.
+func (j *JWKS) lookup(ctx context.Context, kid, alg string) []verificationKey {
+	j.mu.RLock()
+	stale := j.refresh > 0 && time.Since(j.loadedAt) > j.refresh
+	j.mu.RUnlock()
+	// Устаревший набор обновляется в фоне: пока издатель недоступен, токены проверяются прежними
+	// ключами, а попытки повторяются не чаще раза в минуту.
+	if stale && j.beginReload(min(j.refresh, minReloadInterval)) {
+		go j.reload(context.WithoutCancel(ctx))
+	}
+
+	keys := j.match(kid, alg)
+	if len(keys) == 0 && kid != "" && j.beginReload(minReloadInterval) {
+		// Возможно, издатель сменил ключи - перечитываем набор, но не чаще раза в минуту.
+		j.reload(ctx)
+		keys = j.match(kid, alg)
+	}
+	return keys
+}
+
+func (j *JWKS) match(kid, alg string) []verificationKey {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	var keys []verificationKey
+	for _, k := range j.keys {
+		if k.alg != alg {
+			continue
+		}
+		if kid != "" && k.kid != kid {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// beginReload - Занимает право на обновление, если оно ещё не идёт и с прошлой попытки прошло не меньше interval.
+func (j *JWKS) beginReload(interval time.Duration) bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.reloading || time.Since(j.lastAttempt) < interval {
+		return false
+	}
+	j.reloading = true
+	j.lastAttempt = time.Now()
+	return true
+}
+
+// reload - Перечитывает набор после beginReload. При ошибке остаются прежние ключи.
+func (j *JWKS) reload(ctx context.Context) {
+	err := j.load(ctx)
+
+	j.mu.Lock()
+	j.reloading = false
+	j.mu.Unlock()
+
+	if err != nil {
+		slog.WarnContext(ctx, "не удалось обновить JWKS, используются прежние ключи", "source", j.source, "error", err)
+	}
+}
+
+func (j *JWKS) load(ctx context.Context) error {
+	data, err := j.read(ctx)
+	if err != nil {
+		return err
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return fmt.Errorf("некорректный JWKS: %w", err)
+	}
+
+	keys := make([]verificationKey, 0, len(set.Keys))
+	for _, raw := range set.Keys {
+		if raw.Use != "" && raw.Use != "sig" {
+			continue
+		}
+		key, err := parseJWK(raw)
+		if err != nil {
+			slog.WarnContext(ctx, "ключ из JWKS пропущен", "kid", raw.Kid, "error", err)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("в JWKS %s нет ни одного поддерживаемого ключа", j.source)
+	}
+
+	j.mu.Lock()
+	j.keys = keys
+	j.loadedAt = time.Now()
+	j.mu.Unlock()
+
+	return nil
+}
+
+func (j *JWKS) read(ctx context.Context) ([]byte, error) {
+	if !strings.HasPrefix(j.source, "http://") && !strings.HasPrefix(j.source, "https://") {
+		return os.ReadFile(j.source)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.source, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("JWKS %s вернул статус %d", j.source, resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxJWKSSize))
+}
+
+func parseJWK(raw jwk) (verificationKey, error) {
+	switch raw.Kty {
+	case "oct":
+		if raw.Alg != "" && raw.Alg != algHS256 {
+			return verificationKey{}, fmt.Errorf("алгоритм %s не поддерживается для oct", raw.Alg)
+		}
+		secret, err := base64.RawURLEncoding.DecodeString(raw.K)
+		if err != nil || len(secret) == 0 {
+			return verificationKey{}, fmt.Errorf("некорректное поле k")
+		}
+		return verificationKey{kid: raw.Kid, alg: algHS256, key: secret}, nil
+
+	case "RSA":
+		if raw.Alg != "" && raw.Alg != algRS256 {
+			return verificationKey{}, fmt.Errorf("алгоритм %s не поддерживается для RSA", raw.Alg)
+		}
+		n, err := base64.RawURLEncoding.DecodeString(raw.N)
+		if err != nil || len(n) == 0 {
+			return verificationKey{}, fmt.Errorf("некорректное поле n")
+		}
+		e, err := base64.RawURLEncoding.DecodeString(raw.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			return verificationKey{}, fmt.Errorf("некорректное поле e")
+		}
+		pub := &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+		if pub.N.BitLen() < 2048 {
+			return verificationKey{}, fmt.Errorf("RSA-ключ короче 2048 бит")
+		}
+		return verificationKey{kid: raw.Kid, alg: algRS256, key: pub}, nil
+
+	case "OKP":
+		if raw.Crv != "Ed25519" {
+			return verificationKey{}, fmt.Errorf("кривая %s не поддерживается", raw.Crv)
+		}
+		if raw.Alg != "" && raw.Alg != algEdDSA {
+			return verificationKey{}, fmt.Errorf("алгоритм %s не поддерживается для OKP", raw.Alg)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(raw.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return verificationKey{}, fmt.Errorf("некорректное поле x")
+		}
+		return verificationKey{kid: raw.Kid, alg: algEdDSA, key: ed25519.PublicKey(x)}, nil
+
+	default:
+		return verificationKey{}, fmt.Errorf("тип ключа %q не поддерживается", raw.Kty)
+	}
+}
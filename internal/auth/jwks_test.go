@@ -0,0 +1,71 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"go-offline-test/internal/auth"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestJWKSStaleRefreshDoesNotBlock - Пока издатель недоступен, токены проверяются прежними ключами,
+// а обновления идут в фоне и не чаще раза в минуту.
+func TestJWKSStaleRefreshDoesNotBlock(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwks, err := json.Marshal(map[string]any{"keys": []map[string]string{rsaJWK("rsa", &key.PublicKey)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var down atomic.Bool
+	var hits atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if down.Load() {
+			// Издатель завис: ответ не приходит, пока тест не закончится.
+			<-release
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(jwks)
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	ctx := context.Background()
+	set, err := auth.NewJWKS(ctx, srv.URL, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("NewJWKS(): %v", err)
+	}
+	verifier := auth.NewJWTVerifier(set, auth.JWTOptions{})
+	token := makeToken(t, map[string]any{"alg": "RS256", "kid": "rsa"}, map[string]any{
+		"sub": "user-1",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}, rsaSigner(t, key))
+
+	down.Store(true)
+	time.Sleep(20 * time.Millisecond)
+
+	start := time.Now()
+	for range 50 {
+		if _, err := verifier.Authenticate(ctx, token); err != nil {
+			t.Fatalf("Authenticate() устаревшими ключами: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("50 проверок заняли %v: запросы ждут обновления JWKS", elapsed)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if got := hits.Load(); got != 2 {
+		t.Errorf("запросов к JWKS %d, ожидалось 2: загрузка и одна попытка обновления", got)
+	}
+}
@@ -0,0 +1,265 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	algHS256 = "HS256"
+	algRS256 = "RS256"
+	algEdDSA = "EdDSA"
+)
+
+// JWTOptions - Правила проверки токенов и отображения claims в права сервиса.
+type JWTOptions struct {
+	// Issuer - Ожидаемое значение iss. Пустое значение отключает проверку.
+	Issuer string
+	// Audience - Допустимые значения aud. Пустой список отключает проверку.
+	Audience []string
+	// Leeway - Допустимое расхождение часов при проверке exp и nbf.
+	Leeway time.Duration
+	// ScopeClaim - Claim со списком прав (строка через пробел или массив).
+	ScopeClaim string
+	// ScopeMap - Какие значения claim дают какие права сервиса.
+	ScopeMap map[string][]Scope
+}
+
+// JWTVerifier - Проверяет JWT, выпущенные платформой, по ключам из JWKS.
+type JWTVerifier struct {
+	jwks *JWKS
+	opts JWTOptions
+}
+
+func NewJWTVerifier(jwks *JWKS, opts JWTOptions) *JWTVerifier {
+	if opts.ScopeClaim == "" {
+		opts.ScopeClaim = "scope"
+	}
+	scopeMap := make(map[string][]Scope, len(opts.ScopeMap)+len(knownScopes))
+	for scope := range knownScopes {
+		scopeMap[string(scope)] = []Scope{scope}
+	}
+	for value, scopes := range opts.ScopeMap {
+		scopeMap[value] = scopes
+	}
+	opts.ScopeMap = scopeMap
+
+	return &JWTVerifier{jwks: jwks, opts: opts}
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	Typ string `json:"typ"`
+}
+
+type jwtClaims struct {
+	Subject   string          `json:"sub"`
+	Issuer    string          `json:"iss"`
+	Audience  json.RawMessage `json:"aud"`
+	ExpiresAt *json.Number    `json:"exp"`
+	NotBefore *json.Number    `json:"nbf"`
+	Name      string          `json:"name"`
+}
+
+func (v *JWTVerifier) Authenticate(ctx context.Context, token string) (*Principal, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidToken
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("%w: заголовок: %v", ErrInvalidToken, err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: подпись: %v", ErrInvalidToken, err)
+	}
+
+	keys := v.jwks.lookup(ctx, header.Kid, header.Alg)
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%w: нет ключа для kid=%q alg=%q", ErrInvalidToken, header.Kid, header.Alg)
+	}
+	signingInput := parts[0] + "." + parts[1]
+	if !verifyAny(keys, signingInput, signature) {
+		return nil, fmt.Errorf("%w: подпись не прошла проверку", ErrInvalidToken)
+	}
+
+	// Claims разбираем дважды: типизированно для стандартных полей и как map для claim с правами.
+	var claims jwtClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("%w: claims: %v", ErrInvalidToken, err)
+	}
+	var raw map[string]any
+	if err := decodeSegment(parts[1], &raw); err != nil {
+		return nil, fmt.Errorf("%w: claims: %v", ErrInvalidToken, err)
+	}
+
+	if err := v.validateClaims(&claims, time.Now()); err != nil {
+		return nil, err
+	}
+
+	name := claims.Name
+	if name == "" {
+		name = claims.Subject
+	}
+	return &Principal{
+		Subject: claims.Subject,
+		Name:    name,
+		Method:  MethodJWT,
+		Scopes:  v.mapScopes(raw[v.opts.ScopeClaim]),
+	}, nil
+}
+
+func (v *JWTVerifier) validateClaims(claims *jwtClaims, now time.Time) error {
+	if claims.Subject == "" {
+		return fmt.Errorf("%w: отсутствует sub", ErrInvalidToken)
+	}
+
+	if claims.ExpiresAt == nil {
+		return fmt.Errorf("%w: отсутствует exp", ErrInvalidToken)
+	}
+	exp, err := numericDate(*claims.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("%w: exp: %v", ErrInvalidToken, err)
+	}
+	if !now.Before(exp.Add(v.opts.Leeway)) {
+		return ErrTokenExpired
+	}
+
+	if claims.NotBefore != nil {
+		nbf, err := numericDate(*claims.NotBefore)
+		if err != nil {
+			return fmt.Errorf("%w: nbf: %v", ErrInvalidToken, err)
+		}
+		if now.Add(v.opts.Leeway).Before(nbf) {
+			return ErrTokenNotYetValid
+		}
+	}
+
+	if v.opts.Issuer != "" && claims.Issuer != v.opts.Issuer {
+		return fmt.Errorf("%w: iss=%q", ErrInvalidIssuer, claims.Issuer)
+	}
+
+	if len(v.opts.Audience) > 0 {
+		audience, err := parseAudience(claims.Audience)
+		if err != nil {
+			return fmt.Errorf("%w: aud: %v", ErrInvalidToken, err)
+		}
+		if !intersects(audience, v.opts.Audience) {
+			return ErrInvalidAudience
+		}
+	}
+
+	return nil
+}
+
+// mapScopes - Переводит значения claim с правами в права сервиса. Неизвестные значения игнорируются.
+func (v *JWTVerifier) mapScopes(claim any) []Scope {
+	var values []string
+	switch c := claim.(type) {
+	case string:
+		values = strings.Fields(c)
+	case []any:
+		for _, item := range c {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+
+	var scopes []Scope
+	seen := make(map[Scope]bool)
+	for _, value := range values {
+		for _, scope := range v.opts.ScopeMap[value] {
+			if !seen[scope] {
+				seen[scope] = true
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	return scopes
+}
+
+func verifyAny(keys []verificationKey, signingInput string, signature []byte) bool {
+	for _, k := range keys {
+		switch key := k.key.(type) {
+		case []byte:
+			mac := hmac.New(sha256.New, key)
+			mac.Write([]byte(signingInput))
+			if hmac.Equal(mac.Sum(nil), signature) {
+				return true
+			}
+		case *rsa.PublicKey:
+			digest := sha256.Sum256([]byte(signingInput))
+			if rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature) == nil {
+				return true
+			}
+		case ed25519.PublicKey:
+			if ed25519.Verify(key, []byte(signingInput), signature) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(strings.NewReader(string(data)))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+func numericDate(n json.Number) (time.Time, error) {
+	f, err := n.Float64()
+	if err != nil {
+		return time.Time{}, err
+	}
+	sec := int64(f)
+	return time.Unix(sec, int64((f-float64(sec))*float64(time.Second))), nil
+}
+
+// parseAudience - aud может быть как строкой, так и массивом строк.
+func parseAudience(raw json.RawMessage) ([]string, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	var single string
+	if err := json.Unmarshal(raw, &single); err == nil {
+		return []string{single}, nil
+	}
+	var many []string
+	if err := json.Unmarshal(raw, &many); err != nil {
+		return nil, err
+	}
+	return many, nil
+}
+
+func intersects(a, b []string) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				return true
+			}
+		}
+	}
+	return false
+}
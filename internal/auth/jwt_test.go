@@ -0,0 +1,229 @@
+package auth_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"go-offline-test/internal/auth"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var b64 = base64.RawURLEncoding
+
+// signer - Подпись токена: по входу header.payload возвращает подпись.
+type signer func(signingInput []byte) []byte
+
+func rsaSigner(t *testing.T, key *rsa.PrivateKey) signer {
+	return func(in []byte) []byte {
+		digest := sha256.Sum256(in)
+		sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sig
+	}
+}
+
+func hmacSigner(secret []byte) signer {
+	return func(in []byte) []byte {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(in)
+		return mac.Sum(nil)
+	}
+}
+
+// makeToken - Собирает JWT из заголовка и claims.
+func makeToken(t *testing.T, header, claims map[string]any, sign signer) string {
+	t.Helper()
+	h, err := json.Marshal(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := b64.EncodeToString(h) + "." + b64.EncodeToString(c)
+	var sig []byte
+	if sign != nil {
+		sig = sign([]byte(input))
+	}
+	return input + "." + b64.EncodeToString(sig)
+}
+
+// writeJWKS - JWKS во временном файле.
+func writeJWKS(t *testing.T, keys ...map[string]string) string {
+	t.Helper()
+	data, err := json.Marshal(map[string]any{"keys": keys})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func rsaJWK(kid string, pub *rsa.PublicKey) map[string]string {
+	return map[string]string{
+		"kty": "RSA", "kid": kid, "alg": "RS256", "use": "sig",
+		"n": b64.EncodeToString(pub.N.Bytes()),
+		"e": b64.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+	}
+}
+
+func TestJWTVerifier(t *testing.T) {
+	ctx := context.Background()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edPub, edPriv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jwks, err := auth.NewJWKS(ctx, writeJWKS(t,
+		rsaJWK("rsa", &rsaKey.PublicKey),
+		map[string]string{"kty": "OKP", "crv": "Ed25519", "kid": "ed", "x": b64.EncodeToString(edPub)},
+	), 0)
+	if err != nil {
+		t.Fatalf("NewJWKS(): %v", err)
+	}
+	verifier := auth.NewJWTVerifier(jwks, auth.JWTOptions{
+		Issuer:   "https://idp.test",
+		Audience: []string{"quotes"},
+		Leeway:   30 * time.Second,
+		ScopeMap: map[string][]auth.Scope{"editor": {auth.ScopeQuotesWrite}},
+	})
+
+	now := time.Now()
+	// claims - Корректные claims, в которые вносится одно изменение.
+	claims := func(edit func(map[string]any)) map[string]any {
+		c := map[string]any{
+			"sub":   "user-1",
+			"iss":   "https://idp.test",
+			"aud":   "quotes",
+			"exp":   now.Add(time.Hour).Unix(),
+			"nbf":   now.Add(-time.Minute).Unix(),
+			"scope": "quotes:read editor unknown",
+		}
+		if edit != nil {
+			edit(c)
+		}
+		return c
+	}
+	rs256 := map[string]any{"alg": "RS256", "kid": "rsa", "typ": "JWT"}
+	ed := func(in []byte) []byte { return ed25519.Sign(edPriv, in) }
+
+	tests := []struct {
+		name   string
+		header map[string]any
+		claims map[string]any
+		sign   signer
+		err    error
+	}{
+		{"корректный RS256", rs256, claims(nil), rsaSigner(t, rsaKey), nil},
+		{"корректный EdDSA", map[string]any{"alg": "EdDSA", "kid": "ed"}, claims(nil), ed, nil},
+		{"aud массивом", rs256, claims(func(c map[string]any) { c["aud"] = []string{"other", "quotes"} }), rsaSigner(t, rsaKey), nil},
+		{"exp в пределах leeway", rs256, claims(func(c map[string]any) { c["exp"] = now.Add(-10 * time.Second).Unix() }), rsaSigner(t, rsaKey), nil},
+		{"nbf в пределах leeway", rs256, claims(func(c map[string]any) { c["nbf"] = now.Add(10 * time.Second).Unix() }), rsaSigner(t, rsaKey), nil},
+
+		{"exp истёк", rs256, claims(func(c map[string]any) { c["exp"] = now.Add(-time.Minute).Unix() }), rsaSigner(t, rsaKey), auth.ErrTokenExpired},
+		{"нет exp", rs256, claims(func(c map[string]any) { delete(c, "exp") }), rsaSigner(t, rsaKey), auth.ErrInvalidToken},
+		{"exp не число", rs256, claims(func(c map[string]any) { c["exp"] = "завтра" }), rsaSigner(t, rsaKey), auth.ErrInvalidToken},
+		{"nbf в будущем", rs256, claims(func(c map[string]any) { c["nbf"] = now.Add(time.Minute).Unix() }), rsaSigner(t, rsaKey), auth.ErrTokenNotYetValid},
+		{"nbf не число", rs256, claims(func(c map[string]any) { c["nbf"] = true }), rsaSigner(t, rsaKey), auth.ErrInvalidToken},
+		{"чужой iss", rs256, claims(func(c map[string]any) { c["iss"] = "https://evil.test" }), rsaSigner(t, rsaKey), auth.ErrInvalidIssuer},
+		{"нет iss", rs256, claims(func(c map[string]any) { delete(c, "iss") }), rsaSigner(t, rsaKey), auth.ErrInvalidIssuer},
+		{"чужой aud", rs256, claims(func(c map[string]any) { c["aud"] = "billing" }), rsaSigner(t, rsaKey), auth.ErrInvalidAudience},
+		{"aud массивом без нашего", rs256, claims(func(c map[string]any) { c["aud"] = []string{"billing"} }), rsaSigner(t, rsaKey), auth.ErrInvalidAudience},
+		{"нет aud", rs256, claims(func(c map[string]any) { delete(c, "aud") }), rsaSigner(t, rsaKey), auth.ErrInvalidAudience},
+		{"aud не строка", rs256, claims(func(c map[string]any) { c["aud"] = 42 }), rsaSigner(t, rsaKey), auth.ErrInvalidToken},
+		{"нет sub", rs256, claims(func(c map[string]any) { delete(c, "sub") }), rsaSigner(t, rsaKey), auth.ErrInvalidToken},
+
+		{"alg none без подписи", map[string]any{"alg": "none", "kid": "rsa"}, claims(nil), nil, auth.ErrInvalidToken},
+		{"alg none без kid", map[string]any{"alg": "none"}, claims(nil), nil, auth.ErrInvalidToken},
+		{"HS256, подписанный открытым RSA-ключом в DER", map[string]any{"alg": "HS256", "kid": "rsa"}, claims(nil), hmacSigner(pubDER), auth.ErrInvalidToken},
+		{"HS256, подписанный модулем RSA-ключа", map[string]any{"alg": "HS256", "kid": "rsa"}, claims(nil), hmacSigner(rsaKey.N.Bytes()), auth.ErrInvalidToken},
+		{"HS256, подписанный открытым Ed25519-ключом", map[string]any{"alg": "HS256", "kid": "ed"}, claims(nil), hmacSigner(edPub), auth.ErrInvalidToken},
+		{"RS256 чужим ключом", rs256, claims(nil), rsaSigner(t, otherKey), auth.ErrInvalidToken},
+		{"EdDSA с kid RSA-ключа", map[string]any{"alg": "EdDSA", "kid": "rsa"}, claims(nil), ed, auth.ErrInvalidToken},
+		{"неизвестный kid", map[string]any{"alg": "RS256", "kid": "old"}, claims(nil), rsaSigner(t, rsaKey), auth.ErrInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := verifier.Authenticate(ctx, makeToken(t, tt.header, tt.claims, tt.sign))
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Errorf("Authenticate() = %v, ожидалась %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Authenticate(): %v", err)
+			}
+			want := []auth.Scope{auth.ScopeQuotesRead, auth.ScopeQuotesWrite}
+			if p.Subject != "user-1" || p.Method != auth.MethodJWT || !reflect.DeepEqual(p.Scopes, want) {
+				t.Errorf("Authenticate() = %+v, ожидались user-1 и права %v", p, want)
+			}
+		})
+	}
+
+	t.Run("изменённые claims", func(t *testing.T) {
+		token := makeToken(t, rs256, claims(nil), rsaSigner(t, rsaKey))
+		forged := makeToken(t, rs256, claims(func(c map[string]any) { c["sub"] = "admin" }), nil)
+		parts, forgedParts := strings.Split(token, "."), strings.Split(forged, ".")
+		if _, err := verifier.Authenticate(ctx, parts[0]+"."+forgedParts[1]+"."+parts[2]); !errors.Is(err, auth.ErrInvalidToken) {
+			t.Errorf("Authenticate() = %v, ожидалась %v", err, auth.ErrInvalidToken)
+		}
+	})
+
+	for _, malformed := range []string{"", "a.b", "a.b.c.d", "!!!.e30.", b64.EncodeToString([]byte(`{"alg":"RS256"}`)) + ".e30.!!!"} {
+		if _, err := verifier.Authenticate(ctx, malformed); !errors.Is(err, auth.ErrInvalidToken) {
+			t.Errorf("Authenticate(%q) = %v, ожидалась %v", malformed, err, auth.ErrInvalidToken)
+		}
+	}
+}
+
+// TestJWKSSkipsUnsafeKeys - Ключи с чужим алгоритмом, слабые и не для подписи в набор не попадают.
+func TestJWKSSkipsUnsafeKeys(t *testing.T) {
+	weak, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret := b64.EncodeToString([]byte("секрет"))
+	path := writeJWKS(t,
+		rsaJWK("weak", &weak.PublicKey),
+		map[string]string{"kty": "oct", "kid": "oct-rs", "alg": "RS256", "k": secret},
+		map[string]string{"kty": "oct", "kid": "enc", "use": "enc", "k": secret},
+		map[string]string{"kty": "OKP", "crv": "X25519", "kid": "x", "x": secret},
+		map[string]string{"kty": "EC", "kid": "ec"},
+	)
+	if _, err := auth.NewJWKS(context.Background(), path, 0); err == nil {
+		t.Error("NewJWKS() без поддерживаемых ключей: ожидалась ошибка")
+	}
+}
@@ -0,0 +1,318 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// KeyStore - Хранилище API-ключей в JSON-файле.
+// Файл перечитывается при изменении, поэтому ключи, созданные через CLI, подхватываются без перезапуска.
+type KeyStore struct {
+	path    string
+	keys    map[string]*APIKey
+	modTime time.Time
+	mu      sync.RWMutex
+}
+
+func NewKeyStore(path string) (*KeyStore, error) {
+	ks := &KeyStore{
+		path: path,
+		keys: make(map[string]*APIKey),
+	}
+	if err := ks.reload(); err != nil {
+		return nil, err
+	}
+	return ks, nil
+}
+
+func (ks *KeyStore) Create(ctx context.Context, name string, scopes []Scope, expiresAt *time.Time) (string, *APIKey, error) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return "", nil, err
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", nil, ErrEmptyKeyName
+	}
+	if len(scopes) == 0 {
+		return "", nil, ErrNoScopes
+	}
+	now := time.Now().UTC()
+	if expiresAt != nil && !expiresAt.After(now) {
+		return "", nil, ErrInvalidExpiry
+	}
+
+	// Подтягиваем изменения, сделанные другими процессами, чтобы не затереть их при сохранении.
+	if err := ks.reloadLocked(); err != nil {
+		return "", nil, err
+	}
+
+	id, err := newKeyID()
+	if err != nil {
+		return "", nil, err
+	}
+	token, err := newToken(id)
+	if err != nil {
+		return "", nil, err
+	}
+
+	key := &APIKey{
+		ID:        id,
+		Name:      name,
+		Hash:      hashToken(token),
+		Scopes:    scopes,
+		CreatedAt: now,
+		ExpiresAt: expiresAt,
+	}
+	ks.keys[id] = key
+
+	if err := ks.saveLocked(); err != nil {
+		delete(ks.keys, id)
+		return "", nil, err
+	}
+
+	return token, key.clone(), nil
+}
+
+func (ks *KeyStore) List(ctx context.Context) ([]*APIKey, error) {
+	if err := ks.reload(); err != nil {
+		return nil, err
+	}
+
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	keys := make([]*APIKey, 0, len(ks.keys))
+	for _, key := range ks.keys {
+		keys = append(keys, key.clone())
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+	})
+
+	return keys, nil
+}
+
+func (ks *KeyStore) Revoke(ctx context.Context, id string) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := ks.reloadLocked(); err != nil {
+		return err
+	}
+
+	key, exists := ks.keys[id]
+	if !exists {
+		return ErrKeyNotFound
+	}
+	if key.RevokedAt != nil {
+		return ErrKeyRevoked
+	}
+
+	now := time.Now().UTC()
+	key.RevokedAt = &now
+
+	if err := ks.saveLocked(); err != nil {
+		key.RevokedAt = nil
+		return err
+	}
+	return nil
+}
+
+// Rotate - Выпускает новый секрет для ключа, старый токен сразу перестаёт работать.
+// Если expiresAt не nil, срок действия ключа тоже обновляется.
+func (ks *KeyStore) Rotate(ctx context.Context, id string, expiresAt *time.Time) (string, *APIKey, error) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return "", nil, err
+	}
+	if err := ks.reloadLocked(); err != nil {
+		return "", nil, err
+	}
+
+	key, exists := ks.keys[id]
+	if !exists {
+		return "", nil, ErrKeyNotFound
+	}
+	if key.RevokedAt != nil {
+		return "", nil, ErrKeyRevoked
+	}
+	now := time.Now().UTC()
+	if expiresAt != nil && !expiresAt.After(now) {
+		return "", nil, ErrInvalidExpiry
+	}
+
+	token, err := newToken(id)
+	if err != nil {
+		return "", nil, err
+	}
+
+	prev := *key
+	key.Hash = hashToken(token)
+	key.RotatedAt = &now
+	if expiresAt != nil {
+		key.ExpiresAt = expiresAt
+	}
+
+	if err := ks.saveLocked(); err != nil {
+		*key = prev
+		return "", nil, err
+	}
+
+	return token, key.clone(), nil
+}
+
+func (ks *KeyStore) Authenticate(ctx context.Context, token string) (*Principal, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	id, ok := parseToken(token)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	if err := ks.reload(); err != nil {
+		return nil, err
+	}
+
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	key, exists := ks.keys[id]
+	if !exists || !key.matches(token) {
+		return nil, ErrInvalidToken
+	}
+	if err := key.Status(time.Now()); err != nil {
+		return nil, err
+	}
+
+	return &Principal{Subject: key.ID, Name: key.Name, Method: MethodAPIKey, Scopes: key.Scopes}, nil
+}
+
+// reload - Перечитывает файл, если он изменился с момента последнего чтения.
+// Свежесть проверяется под блокировкой на чтение: запись блокирует проверки ключей, только когда файл изменился.
+func (ks *KeyStore) reload() error {
+	info, err := os.Stat(ks.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	ks.mu.RLock()
+	fresh := info.ModTime().Equal(ks.modTime)
+	ks.mu.RUnlock()
+	if fresh {
+		return nil
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	return ks.reloadLocked()
+}
+
+func (ks *KeyStore) reloadLocked() error {
+	info, err := os.Stat(ks.path)
+	if errors.Is(err, os.ErrNotExist) {
+		// Файла ещё нет - работаем с пустым набором ключей.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.ModTime().Equal(ks.modTime) {
+		return nil
+	}
+
+	data, err := os.ReadFile(ks.path)
+	if err != nil {
+		return err
+	}
+
+	var keys []*APIKey
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &keys); err != nil {
+			return err
+		}
+	}
+
+	ks.keys = make(map[string]*APIKey, len(keys))
+	for _, key := range keys {
+		ks.keys[key.ID] = key
+	}
+	ks.modTime = info.ModTime()
+
+	return nil
+}
+
+// saveLocked - Атомарно записывает ключи в файл через временный файл и rename.
+func (ks *KeyStore) saveLocked() error {
+	keys := make([]*APIKey, 0, len(ks.keys))
+	for _, key := range ks.keys {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+	})
+
+	data, err := json.MarshalIndent(keys, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(ks.path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, ".api-keys-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), ks.path); err != nil {
+		return err
+	}
+
+	info, err := os.Stat(ks.path)
+	if err != nil {
+		return err
+	}
+	ks.modTime = info.ModTime()
+
+	return nil
+}
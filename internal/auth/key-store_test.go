@@ -0,0 +1,203 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"go-offline-test/internal/auth"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newKeyStore(t *testing.T) (*auth.KeyStore, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "keys.json")
+	ks, err := auth.NewKeyStore(path)
+	if err != nil {
+		t.Fatalf("NewKeyStore(): %v", err)
+	}
+	return ks, path
+}
+
+func TestKeyStoreCreate(t *testing.T) {
+	ctx := context.Background()
+	ks, _ := newKeyStore(t)
+
+	token, key, err := ks.Create(ctx, "  бот  ", []auth.Scope{auth.ScopeQuotesRead}, nil)
+	if err != nil {
+		t.Fatalf("Create(): %v", err)
+	}
+	if key.Name != "бот" {
+		t.Errorf("имя %q, ожидалось %q", key.Name, "бот")
+	}
+	p, err := ks.Authenticate(ctx, token)
+	if err != nil {
+		t.Fatalf("Authenticate(): %v", err)
+	}
+	if p.Subject != key.ID || p.Method != auth.MethodAPIKey || !p.HasScope(auth.ScopeQuotesRead) || p.HasScope(auth.ScopeAdmin) {
+		t.Errorf("Authenticate() = %+v, ожидался ключ %s с правом quotes:read", p, key.ID)
+	}
+
+	past := time.Now().Add(-time.Minute)
+	tests := []struct {
+		name    string
+		keyName string
+		scopes  []auth.Scope
+		expires *time.Time
+		err     error
+	}{
+		{"пустое имя", " ", []auth.Scope{auth.ScopeAdmin}, nil, auth.ErrEmptyKeyName},
+		{"без прав", "бот", nil, nil, auth.ErrNoScopes},
+		{"срок в прошлом", "бот", []auth.Scope{auth.ScopeAdmin}, &past, auth.ErrInvalidExpiry},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := ks.Create(ctx, tt.keyName, tt.scopes, tt.expires); !errors.Is(err, tt.err) {
+				t.Errorf("Create() = %v, ожидалась %v", err, tt.err)
+			}
+		})
+	}
+
+	for _, bad := range []string{"", "qk_", "qk_" + key.ID + "_чужой", token + "x", "jwt.token.here"} {
+		if _, err := ks.Authenticate(ctx, bad); !errors.Is(err, auth.ErrInvalidToken) {
+			t.Errorf("Authenticate(%q) = %v, ожидалась %v", bad, err, auth.ErrInvalidToken)
+		}
+	}
+}
+
+func TestKeyStoreRotate(t *testing.T) {
+	ctx := context.Background()
+	ks, _ := newKeyStore(t)
+	old, key, err := ks.Create(ctx, "бот", []auth.Scope{auth.ScopeQuotesWrite}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expires := time.Now().Add(time.Hour).UTC()
+	fresh, rotated, err := ks.Rotate(ctx, key.ID, &expires)
+	if err != nil {
+		t.Fatalf("Rotate(): %v", err)
+	}
+	if rotated.ID != key.ID || rotated.RotatedAt == nil || !rotated.ExpiresAt.Equal(expires) {
+		t.Errorf("Rotate() = %+v, ожидались тот же id, rotated_at и новый срок", rotated)
+	}
+	if _, err := ks.Authenticate(ctx, old); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Errorf("старый токен: %v, ожидалась %v", err, auth.ErrInvalidToken)
+	}
+	if _, err := ks.Authenticate(ctx, fresh); err != nil {
+		t.Errorf("новый токен: %v", err)
+	}
+
+	if _, _, err := ks.Rotate(ctx, "нет-такого", nil); !errors.Is(err, auth.ErrKeyNotFound) {
+		t.Errorf("Rotate() неизвестного ключа = %v, ожидалась %v", err, auth.ErrKeyNotFound)
+	}
+}
+
+func TestKeyStoreRevoke(t *testing.T) {
+	ctx := context.Background()
+	ks, _ := newKeyStore(t)
+	token, key, err := ks.Create(ctx, "бот", []auth.Scope{auth.ScopeQuotesRead}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ks.Revoke(ctx, key.ID); err != nil {
+		t.Fatalf("Revoke(): %v", err)
+	}
+	if _, err := ks.Authenticate(ctx, token); !errors.Is(err, auth.ErrKeyRevoked) {
+		t.Errorf("Authenticate() отозванного ключа = %v, ожидалась %v", err, auth.ErrKeyRevoked)
+	}
+	if err := ks.Revoke(ctx, key.ID); !errors.Is(err, auth.ErrKeyRevoked) {
+		t.Errorf("повторный Revoke() = %v, ожидалась %v", err, auth.ErrKeyRevoked)
+	}
+	if _, _, err := ks.Rotate(ctx, key.ID, nil); !errors.Is(err, auth.ErrKeyRevoked) {
+		t.Errorf("Rotate() отозванного ключа = %v, ожидалась %v", err, auth.ErrKeyRevoked)
+	}
+	if err := ks.Revoke(ctx, "нет-такого"); !errors.Is(err, auth.ErrKeyNotFound) {
+		t.Errorf("Revoke() неизвестного ключа = %v, ожидалась %v", err, auth.ErrKeyNotFound)
+	}
+}
+
+func TestKeyStoreExpiry(t *testing.T) {
+	ctx := context.Background()
+	ks, _ := newKeyStore(t)
+	expires := time.Now().Add(50 * time.Millisecond)
+	token, key, err := ks.Create(ctx, "бот", []auth.Scope{auth.ScopeQuotesRead}, &expires)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ks.Authenticate(ctx, token); err != nil {
+		t.Fatalf("Authenticate() до истечения срока: %v", err)
+	}
+
+	if err := key.Status(expires); !errors.Is(err, auth.ErrKeyExpired) {
+		t.Errorf("Status() в момент истечения = %v, ожидалась %v", err, auth.ErrKeyExpired)
+	}
+	time.Sleep(time.Until(expires))
+	if _, err := ks.Authenticate(ctx, token); !errors.Is(err, auth.ErrKeyExpired) {
+		t.Errorf("Authenticate() после истечения срока = %v, ожидалась %v", err, auth.ErrKeyExpired)
+	}
+}
+
+// TestKeyStoreReload - Изменения, сделанные другим процессом (например, CLI), видны без перезапуска.
+func TestKeyStoreReload(t *testing.T) {
+	ctx := context.Background()
+	server, path := newKeyStore(t)
+	cli, err := auth.NewKeyStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, key, err := cli.Create(ctx, "бот", []auth.Scope{auth.ScopeQuotesRead}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := server.Authenticate(ctx, token); err != nil {
+		t.Fatalf("Authenticate() ключа, созданного другим хранилищем: %v", err)
+	}
+	if err := cli.Revoke(ctx, key.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := server.Authenticate(ctx, token); !errors.Is(err, auth.ErrKeyRevoked) {
+		t.Errorf("Authenticate() ключа, отозванного другим хранилищем = %v, ожидалась %v", err, auth.ErrKeyRevoked)
+	}
+
+	// Ключ, созданный сервером, не затирает изменения другого хранилища.
+	if _, _, err := server.Create(ctx, "второй", []auth.Scope{auth.ScopeAdmin}, nil); err != nil {
+		t.Fatal(err)
+	}
+	keys, err := cli.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 2 || keys[0].RevokedAt == nil || keys[1].Name != "второй" {
+		t.Errorf("List() = %+v, ожидались отозванный ключ и ключ \"второй\"", keys)
+	}
+}
+
+// TestKeyStoreListCopies - List отдаёт копии: изменения снаружи не попадают в хранилище.
+func TestKeyStoreListCopies(t *testing.T) {
+	ctx := context.Background()
+	ks, _ := newKeyStore(t)
+	token, _, err := ks.Create(ctx, "бот", []auth.Scope{auth.ScopeQuotesRead}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := ks.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	keys[0].Name = "чужое"
+	keys[0].Scopes[0] = auth.ScopeAdmin
+	keys[0].RevokedAt = &now
+
+	p, err := ks.Authenticate(ctx, token)
+	if err != nil {
+		t.Fatalf("Authenticate(): %v", err)
+	}
+	if p.Name != "бот" || p.HasScope(auth.ScopeAdmin) {
+		t.Errorf("Authenticate() = %+v, изменения копии попали в хранилище", p)
+	}
+}
@@ -0,0 +1,40 @@
+package auth
+
+import "context"
+
+type contextKey string
+
+const principalCtxKey contextKey = "principal"
+
+const (
+	MethodAPIKey     = "api-key"
+	MethodJWT        = "jwt"
+	MethodClientCert = "client-cert"
+)
+
+// Principal - Аутентифицированный клиент запроса.
+type Principal struct {
+	// Subject - Идентификатор клиента: id ключа, sub из JWT или cert:CN клиентского сертификата.
+	Subject string
+	// Name - Человекочитаемое имя клиента.
+	Name string
+	// Method - Способ аутентификации.
+	Method string
+	Scopes []Scope
+}
+
+// HasScope - Проверяет, есть ли у клиента указанное право.
+func (p *Principal) HasScope(scope Scope) bool {
+	return hasScope(p.Scopes, scope)
+}
+
+// WithPrincipal - Сохраняет клиента в контекст.
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalCtxKey, p)
+}
+
+// PrincipalFromContext - Достаёт клиента из контекста.
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalCtxKey).(*Principal)
+	return p, ok && p != nil
+}
@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Scope - Право доступа, выдаваемое ключу.
+type Scope string
+
+const (
+	ScopeQuotesRead  Scope = "quotes:read"
+	ScopeQuotesWrite Scope = "quotes:write"
+	// ScopeQuotesModerate - Очередь модерации и решения по цитатам.
+	ScopeQuotesModerate Scope = "quotes:moderate"
+	ScopeAdmin          Scope = "admin"
+)
+
+var knownScopes = map[Scope]bool{
+	ScopeQuotesRead:     true,
+	ScopeQuotesWrite:    true,
+	ScopeQuotesModerate: true,
+	ScopeAdmin:          true,
+}
+
+// ParseScopes - Разбирает список прав, переданный строками.
+func ParseScopes(raw []string) ([]Scope, error) {
+	scopes := make([]Scope, 0, len(raw))
+	seen := make(map[Scope]bool, len(raw))
+	for _, s := range raw {
+		scope := Scope(strings.TrimSpace(s))
+		if scope == "" {
+			continue
+		}
+		if !knownScopes[scope] {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownScope, scope)
+		}
+		if seen[scope] {
+			continue
+		}
+		seen[scope] = true
+		scopes = append(scopes, scope)
+	}
+	if len(scopes) == 0 {
+		return nil, ErrNoScopes
+	}
+
+	return scopes, nil
+}
+
+// hasScope - Проверяет наличие права. Право admin включает в себя все остальные.
+func hasScope(scopes []Scope, want Scope) bool {
+	for _, s := range scopes {
+		if s == want || s == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
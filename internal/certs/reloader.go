@@ -0,0 +1,132 @@
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Reloader - Отдаёт серверу актуальный сертификат. Файлы перечитываются, когда меняются их
+// время изменения, размер или сам файл, поэтому обновлённый сертификат подхватывается без перезапуска.
+type Reloader struct {
+	certFile string
+	keyFile  string
+	cert     atomic.Pointer[tls.Certificate]
+	// files - Сведения о файлах, с которыми загружен текущий сертификат.
+	files []os.FileInfo
+	mu    sync.Mutex
+}
+
+// NewReloader - Загружает сертификат и ключ. Ошибка при первой загрузке фатальна,
+// при последующих остаётся прежний сертификат.
+func NewReloader(certFile, keyFile string) (*Reloader, error) {
+	r := &Reloader{certFile: certFile, keyFile: keyFile}
+	if _, err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Static - Сертификат, который не перечитывается, например самоподписанный.
+func Static(cert tls.Certificate) *Reloader {
+	r := &Reloader{}
+	r.cert.Store(&cert)
+	return r
+}
+
+// GetCertificate - Подходит для tls.Config.GetCertificate.
+func (r *Reloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return r.cert.Load(), nil
+}
+
+// Reload - Перечитывает файлы, если они изменились. Возвращает true, если сертификат заменён.
+func (r *Reloader) Reload() (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.certFile == "" {
+		return false, nil
+	}
+
+	files, err := statFiles(r.certFile, r.keyFile)
+	if err != nil {
+		return false, err
+	}
+	// Время изменения сравнивается на равенство, а не «новее»: после cp -p или восстановления
+	// из резервной копии новый файл может оказаться старше прежнего.
+	if sameFiles(files, r.files) {
+		return false, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return false, fmt.Errorf("не удалось загрузить сертификат %s: %w", r.certFile, err)
+	}
+	r.cert.Store(&cert)
+	r.files = files
+	return true, nil
+}
+
+// Watch - Проверяет файлы раз в interval до отмены ctx.
+func (r *Reloader) Watch(ctx context.Context, interval time.Duration) {
+	if r.certFile == "" || interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			changed, err := r.Reload()
+			if err != nil {
+				slog.Error("сертификат не обновлён, используется прежний", "file", r.certFile, "error", err)
+				continue
+			}
+			if changed {
+				slog.Info("сертификат обновлён", "file", r.certFile, "not_after", r.NotAfter())
+			}
+		}
+	}
+}
+
+// NotAfter - Срок действия текущего сертификата.
+func (r *Reloader) NotAfter() time.Time {
+	cert := r.cert.Load()
+	if cert == nil || cert.Leaf == nil {
+		return time.Time{}
+	}
+	return cert.Leaf.NotAfter
+}
+
+func statFiles(files ...string) ([]os.FileInfo, error) {
+	infos := make([]os.FileInfo, 0, len(files))
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// sameFiles - Те же файлы (по inode, где он есть) с тем же временем изменения и размером.
+func sameFiles(a, b []os.FileInfo) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !os.SameFile(a[i], b[i]) || !a[i].ModTime().Equal(b[i].ModTime()) || a[i].Size() != b[i].Size() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,114 @@
+package certs_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"go-offline-test/internal/certs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writePair - Пишет самоподписанный сертификат и ключ в PEM и выставляет им время изменения.
+func writePair(t *testing.T, certFile, keyFile string, validFor time.Duration, modTime time.Time) tls.Certificate {
+	t.Helper()
+	cert, err := certs.SelfSigned(validFor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := x509.MarshalPKCS8PrivateKey(cert.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]*pem.Block{
+		certFile: {Type: "CERTIFICATE", Bytes: cert.Certificate[0]},
+		keyFile:  {Type: "PRIVATE KEY", Bytes: key},
+	}
+	for file, block := range files {
+		if err := os.WriteFile(file, pem.EncodeToMemory(block), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(file, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return cert
+}
+
+func current(t *testing.T, r *certs.Reloader) []byte {
+	t.Helper()
+	cert, err := r.GetCertificate(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert.Certificate[0]
+}
+
+func TestReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	now := time.Now().Truncate(time.Second)
+	first := writePair(t, certFile, keyFile, time.Hour, now)
+
+	r, err := certs.NewReloader(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("NewReloader(): %v", err)
+	}
+	if !r.NotAfter().Equal(first.Leaf.NotAfter) {
+		t.Errorf("NotAfter() = %v, ожидалось %v", r.NotAfter(), first.Leaf.NotAfter)
+	}
+
+	if changed, err := r.Reload(); changed || err != nil {
+		t.Errorf("Reload() без изменений = %v, %v", changed, err)
+	}
+
+	t.Run("файл с более старым временем изменения", func(t *testing.T) {
+		// Так бывает после cp -p или восстановления из резервной копии.
+		restored := writePair(t, certFile, keyFile, 2*time.Hour, now.Add(-24*time.Hour))
+		changed, err := r.Reload()
+		if !changed || err != nil {
+			t.Fatalf("Reload() = %v, %v, ожидалась замена", changed, err)
+		}
+		if string(current(t, r)) != string(restored.Certificate[0]) {
+			t.Error("GetCertificate() отдаёт прежний сертификат")
+		}
+	})
+
+	t.Run("испорченный файл оставляет прежний сертификат", func(t *testing.T) {
+		before := current(t, r)
+		if err := os.WriteFile(keyFile, []byte("не ключ"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if changed, err := r.Reload(); changed || err == nil {
+			t.Errorf("Reload() = %v, %v, ожидалась ошибка", changed, err)
+		}
+		if string(current(t, r)) != string(before) {
+			t.Error("после ошибки сертификат заменён")
+		}
+	})
+
+	t.Run("пропавший файл", func(t *testing.T) {
+		if err := os.Remove(certFile); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := r.Reload(); err == nil {
+			t.Error("Reload() без файла: ожидалась ошибка")
+		}
+	})
+}
+
+func TestStatic(t *testing.T) {
+	cert, err := certs.SelfSigned(time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := certs.Static(cert)
+	if changed, err := r.Reload(); changed || err != nil {
+		t.Errorf("Reload() статического сертификата = %v, %v", changed, err)
+	}
+	if string(current(t, r)) != string(cert.Certificate[0]) {
+		t.Error("GetCertificate() отдаёт не тот сертификат")
+	}
+}
@@ -0,0 +1,117 @@
+package citation
+
+import (
+	"go-offline-test/internal/models"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// bibtexEscape - Спецсимволы LaTeX в значениях полей. Адреса не экранируются: их читает пакет url.
+var bibtexEscape = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	"{", `\{`,
+	"}", `\}`,
+	"&", `\&`,
+	"%", `\%`,
+	"$", `\$`,
+	"#", `\#`,
+	"_", `\_`,
+	"~", `\textasciitilde{}`,
+	"^", `\textasciicircum{}`,
+)
+
+// translit - Кириллица и латиница с диакритикой в ключах записей: BibTeX принимает в ключах только ASCII.
+var translit = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh", 'з': "z", 'и': "i",
+	'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t",
+	'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch", 'ы': "y", 'э': "e",
+	'ю': "yu", 'я': "ya",
+
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ç': "c", 'č': "c", 'ć': "c",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ł': "l",
+	'ñ': "n", 'ń': "n", 'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ß': "ss",
+	'š': "s", 'ś': "s", 'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ý': "y", 'ÿ': "y", 'ž': "z", 'ź': "z", 'ż': "z",
+}
+
+// bibtex - Запись BibTeX. Книга - @book, статья - @article, остальное - @misc. Текст цитаты - в note.
+func bibtex(w Work) string {
+	var fields [][2]string
+	entry := "misc"
+	if src := w.Source; src != nil {
+		fields = append(fields, [2]string{"title", src.Title})
+		switch src.Type {
+		case models.SourceBook:
+			entry = "book"
+			fields = append(fields, [2]string{"publisher", src.Publisher})
+		case models.SourceArticle:
+			entry = "article"
+			fields = append(fields, [2]string{"journal", src.Publisher})
+		default:
+			fields = append(fields, [2]string{"howpublished", strings.Trim(typeLabel(src.Type)+", "+src.Publisher, ", ")})
+		}
+		if src.Year != nil {
+			fields = append(fields, [2]string{"year", strconv.Itoa(*src.Year)})
+		}
+		fields = append(fields, [2]string{"pages", strings.NewReplacer("–", "--", "-", "--").Replace(src.Pages)})
+	}
+	fields = append(fields, [2]string{"note", "«" + w.Quote + "»"})
+
+	var b strings.Builder
+	b.WriteString("@" + entry + "{" + Key(w) + ",\n")
+	if w.Author != "" {
+		b.WriteString("  author = {" + bibtexName(w.Author) + "},\n")
+	}
+	for _, field := range fields {
+		if field[1] != "" {
+			b.WriteString("  " + field[0] + " = {" + bibtexEscape.Replace(field[1]) + "},\n")
+		}
+	}
+	if w.Source != nil && w.Source.URL != "" {
+		b.WriteString("  url = {" + w.Source.URL + "},\n")
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// bibtexName - Имя в виде, который BibTeX разбирает верно: «Фамилия, Суффикс, Имя». Коллектив берётся
+// в дополнительные скобки, иначе BibTeX разделит его по and на нескольких авторов.
+func bibtexName(name string) string {
+	p := parseName(name)
+	switch {
+	case p.group:
+		return "{" + bibtexEscape.Replace(name) + "}"
+	case p.suffix != "" && len(p.given) > 0:
+		return bibtexEscape.Replace(p.family + ", " + p.suffix + ", " + strings.Join(p.given, " "))
+	default:
+		return bibtexEscape.Replace(name)
+	}
+}
+
+// Key - Ключ записи BibTeX: фамилия латиницей, год и id цитаты, например jobs2005-12.
+// id делает ключ уникальным в выгрузке по автору.
+func Key(w Work) string {
+	var b strings.Builder
+	words := strings.Fields(parseName(w.Author).family)
+	if len(words) > 0 {
+		for _, r := range strings.ToLower(words[len(words)-1]) {
+			switch {
+			case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+				b.WriteRune(r)
+			case translit[r] != "":
+				b.WriteString(translit[r])
+			}
+		}
+	}
+	if b.Len() == 0 {
+		b.WriteString("quote")
+	}
+	if w.Source != nil && w.Source.Year != nil {
+		if year := *w.Source.Year; year < 0 {
+			b.WriteString(strconv.Itoa(-year) + "bce")
+		} else {
+			b.WriteString(strconv.Itoa(year))
+		}
+	}
+	return b.String() + "-" + strconv.Itoa(w.QuoteID)
+}
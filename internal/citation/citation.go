@@ -0,0 +1,251 @@
+package citation
+
+import (
+	"fmt"
+	"go-offline-test/internal/models"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Стили ссылок.
+const (
+	StyleAPA     = "apa"
+	StyleMLA     = "mla"
+	StyleChicago = "chicago"
+	StyleBibTeX  = "bibtex"
+)
+
+// Styles - Поддерживаемые стили. Первый - стиль по умолчанию.
+var Styles = []string{StyleAPA, StyleMLA, StyleChicago, StyleBibTeX}
+
+// Supported - Есть ли такой стиль.
+func Supported(style string) bool {
+	return slices.Contains(Styles, style)
+}
+
+// Work - Цитируемая цитата: автор, текст и источник.
+type Work struct {
+	QuoteID int
+	Quote   string
+	// Author - Имя автора, как его пишут в ссылке: полное, если известно.
+	Author string
+	// Source - Источник. nil - не указан: ссылка ведёт на автора и текст цитаты.
+	Source *models.Source
+}
+
+// Format - Ссылка на цитату в стиле style. Неизвестный стиль - ошибка, проверять его стоит заранее.
+func Format(style string, w Work) (string, error) {
+	switch style {
+	case StyleAPA:
+		return apa(w), nil
+	case StyleMLA:
+		return mla(w), nil
+	case StyleChicago:
+		return chicago(w), nil
+	case StyleBibTeX:
+		return bibtex(w), nil
+	default:
+		return "", fmt.Errorf("неизвестный стиль ссылки %q", style)
+	}
+}
+
+// apa - APA 7: «Jobs, S. (2005). Title [Speech]. Stanford University. URL».
+func apa(w Work) string {
+	src := source(w)
+	year := "n.d."
+	if src.Year != nil {
+		year = formatYear(*src.Year)
+	}
+	title := src.Title
+	if label := typeLabel(src.Type); label != "" && src.Type != models.SourceBook && src.Type != models.SourceArticle {
+		title += " [" + label + "]"
+	}
+	if src.Pages != "" {
+		title += " (" + pagesPrefix(src.Pages) + " " + src.Pages + ")"
+	}
+	return withURL(sentences(apaName(w.Author), "("+year+")", title, src.Publisher), src.URL)
+}
+
+// mla - MLA 9: «Jobs, Steve. "Title." Stanford University, 2005, p. 42. Speech. URL».
+func mla(w Work) string {
+	src := source(w)
+	var container []string
+	if src.Publisher != "" {
+		container = append(container, src.Publisher)
+	}
+	if src.Year != nil {
+		container = append(container, formatYear(*src.Year))
+	}
+	if src.Pages != "" {
+		container = append(container, pagesPrefix(src.Pages)+" "+src.Pages)
+	}
+	label := ""
+	if src.Type == models.SourceSpeech || src.Type == models.SourceInterview || src.Type == models.SourceLetter {
+		label = typeLabel(src.Type)
+	}
+	return withURL(sentences(fullName(w.Author), titleOf(src), strings.Join(container, ", "), label), src.URL)
+}
+
+// chicago - Chicago, библиография: «Jobs, Steve. "Title." Speech, Stanford University, 2005. URL».
+func chicago(w Work) string {
+	src := source(w)
+	var facts []string
+	if src.Type == models.SourceSpeech || src.Type == models.SourceInterview || src.Type == models.SourceLetter {
+		facts = append(facts, typeLabel(src.Type))
+	}
+	if src.Publisher != "" {
+		facts = append(facts, src.Publisher)
+	}
+	if src.Year != nil {
+		facts = append(facts, formatYear(*src.Year))
+	}
+	if src.Pages != "" {
+		facts = append(facts, src.Pages)
+	}
+	return withURL(sentences(fullName(w.Author), titleOf(src), strings.Join(facts, ", ")), src.URL)
+}
+
+// source - Источник ссылки. Без источника цитируется сам текст цитаты.
+func source(w Work) models.Source {
+	if w.Source == nil {
+		return models.Source{Type: models.SourceOther, Title: "«" + w.Quote + "»"}
+	}
+	return *w.Source
+}
+
+// titleOf - Название: книги пишутся как есть, остальное - в кавычках. Двойные кавычки внутри
+// названия становятся одинарными, чтобы не закрыть внешние.
+func titleOf(src models.Source) string {
+	if src.Type == models.SourceBook || strings.HasPrefix(src.Title, "«") {
+		return src.Title
+	}
+	title := strings.ReplaceAll(src.Title, `"`, "'")
+	if ended(title) {
+		return `"` + title + `"`
+	}
+	return `"` + title + `."`
+}
+
+func typeLabel(sourceType string) string {
+	switch sourceType {
+	case models.SourceSpeech:
+		return "Speech"
+	case models.SourceInterview:
+		return "Interview"
+	case models.SourceLetter:
+		return "Letter"
+	default:
+		return ""
+	}
+}
+
+// pagesPrefix - «p.» для одной страницы, «pp.» для диапазона.
+func pagesPrefix(pages string) string {
+	if strings.ContainsAny(pages, "-–") {
+		return "pp."
+	}
+	return "p."
+}
+
+// formatYear - Год, до нашей эры - с пометкой BCE.
+func formatYear(year int) string {
+	if year < 0 {
+		return strconv.Itoa(-year) + " BCE"
+	}
+	return strconv.Itoa(year)
+}
+
+// personName - Имя автора по частям: «Martin Luther King, Jr.» - имена «Martin Luther», фамилия «King»,
+// суффикс «Jr.».
+type personName struct {
+	given  []string
+	family string
+	suffix string
+	// group - Коллектив, например «Simon and Garfunkel»: имя не делится на части.
+	group bool
+}
+
+// groupSeparators - Союзы, по которым имя коллектива отличается от имени человека.
+var groupSeparators = []string{" and ", " & ", " и "}
+
+// parseName - Фамилией считается последнее слово до запятой, всё после запятой - суффикс.
+func parseName(name string) personName {
+	name = strings.TrimSpace(name)
+	for _, sep := range groupSeparators {
+		if strings.Contains(name, sep) {
+			return personName{family: name, group: true}
+		}
+	}
+	before, suffix, _ := strings.Cut(name, ",")
+	words := strings.Fields(before)
+	if len(words) == 0 {
+		return personName{family: name}
+	}
+	return personName{given: words[:len(words)-1], family: words[len(words)-1], suffix: strings.TrimSpace(suffix)}
+}
+
+// fullName - «Фамилия, Имя, Суффикс». Имя из одного слова и имя коллектива не меняются.
+func fullName(name string) string {
+	p := parseName(name)
+	if len(p.given) == 0 {
+		return name
+	}
+	return withSuffix(p.family+", "+strings.Join(p.given, " "), p.suffix)
+}
+
+// apaName - «Фамилия, И. О.»: вместо имён - инициалы, у двойного имени через дефис - «Ж.-П.».
+func apaName(name string) string {
+	p := parseName(name)
+	if len(p.given) == 0 {
+		return name
+	}
+	initials := make([]string, 0, len(p.given))
+	for _, word := range p.given {
+		parts := strings.Split(word, "-")
+		for i, part := range parts {
+			parts[i] = string([]rune(part)[:1]) + "."
+		}
+		initials = append(initials, strings.Join(parts, "-"))
+	}
+	return withSuffix(p.family+", "+strings.Join(initials, " "), p.suffix)
+}
+
+func withSuffix(name, suffix string) string {
+	if suffix == "" {
+		return name
+	}
+	return name + ", " + suffix
+}
+
+// sentences - Непустые части через точку. Часть, которая уже кончается знаком препинания, точку не получает.
+func sentences(parts ...string) string {
+	var b strings.Builder
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(part)
+		if !ended(strings.TrimSuffix(part, `"`)) {
+			b.WriteByte('.')
+		}
+	}
+	return b.String()
+}
+
+// ended - Кончается ли текст знаком конца предложения.
+func ended(s string) bool {
+	return strings.HasSuffix(s, ".") || strings.HasSuffix(s, "?") || strings.HasSuffix(s, "!")
+}
+
+// withURL - Адрес в конце ссылки, без точки: точка исказила бы адрес.
+func withURL(citation, url string) string {
+	if url == "" {
+		return citation
+	}
+	return citation + " " + url
+}
@@ -0,0 +1,86 @@
+package citation_test
+
+import (
+	"flag"
+	"go-offline-test/internal/citation"
+	"go-offline-test/internal/models"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// update - Перезаписать эталоны: go test ./internal/citation -update.
+var update = flag.Bool("update", false, "перезаписать эталоны в testdata")
+
+func year(y int) *int { return &y }
+
+// works - Цитаты для эталонов: все типы источников, спецсимволы и имена с разделителями.
+var works = []struct {
+	name string
+	work citation.Work
+}{
+	{"без источника", citation.Work{QuoteID: 1, Quote: "Stay hungry, stay foolish", Author: "Steve Jobs"}},
+	{"речь с адресом", citation.Work{QuoteID: 2, Quote: "Stay hungry, stay foolish", Author: "Steve Jobs", Source: &models.Source{
+		Type: models.SourceSpeech, Title: "Stanford Commencement Address", Publisher: "Stanford University",
+		Year: year(2005), URL: "https://news.stanford.edu/2005/06/12/youve-got-find-love_jobs/",
+	}}},
+	{"книга на кириллице", citation.Work{QuoteID: 3, Quote: "Все счастливые семьи похожи друг на друга", Author: "Лев Николаевич Толстой", Source: &models.Source{
+		Type: models.SourceBook, Title: "Анна Каренина", Publisher: "Русский вестник", Year: year(1878), Pages: "5",
+	}}},
+	{"статья с кавычками и спецсимволами", citation.Work{QuoteID: 4, Quote: `Он сказал: "100% & $5 за #1_a {x} ~ ^ \ — «ёлки»"`, Author: "Жан-Поль Сартр", Source: &models.Source{
+		Type: models.SourceArticle, Title: `Что такое "литература"?`, Publisher: "Les Temps modernes", Year: year(1947), Pages: "42–45",
+	}}},
+	{"имя с запятой и суффиксом", citation.Work{QuoteID: 5, Quote: "I have a dream", Author: "Martin Luther King, Jr.", Source: &models.Source{
+		Type: models.SourceSpeech, Title: "I Have a Dream", Publisher: "March on Washington", Year: year(1963),
+	}}},
+	{"имя с and", citation.Work{QuoteID: 6, Quote: "Hello darkness, my old friend", Author: "Simon and Garfunkel", Source: &models.Source{
+		Type: models.SourceWeb, Title: "The Sound of Silence", Publisher: "Columbia", Year: year(1964),
+	}}},
+	{"имя не латиницей и год до нашей эры", citation.Work{QuoteID: 7, Quote: "学而时习之", Author: "孔子", Source: &models.Source{
+		Type: models.SourceOther, Title: "论语", Year: year(-479),
+	}}},
+	{"диакритика в имени", citation.Work{QuoteID: 8, Quote: "Ich denke, also bin ich", Author: "Gottfried Wilhelm Müller", Source: &models.Source{
+		Type: models.SourceLetter, Title: "Brief an Ölmann", Pages: "12",
+	}}},
+}
+
+// TestFormatGolden - Ссылки во всех стилях сверяются с эталонами testdata/<стиль>.golden.
+func TestFormatGolden(t *testing.T) {
+	for _, style := range citation.Styles {
+		t.Run(style, func(t *testing.T) {
+			var b strings.Builder
+			for _, w := range works {
+				out, err := citation.Format(style, w.work)
+				if err != nil {
+					t.Fatalf("Format(%s, %s): %v", style, w.name, err)
+				}
+				b.WriteString("# " + w.name + "\n" + out + "\n\n")
+			}
+			got := b.String()
+
+			path := filepath.Join("testdata", style+".golden")
+			if *update {
+				if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("нет эталона, запустите с -update: %v", err)
+			}
+			if got != string(want) {
+				t.Errorf("стиль %s не совпадает с эталоном %s:\n%s", style, path, got)
+			}
+		})
+	}
+}
+
+func TestFormatUnknownStyle(t *testing.T) {
+	if _, err := citation.Format("harvard", citation.Work{Quote: "x", Author: "y"}); err == nil {
+		t.Error("Format() неизвестного стиля: ожидалась ошибка")
+	}
+	if citation.Supported("harvard") || !citation.Supported(citation.StyleBibTeX) {
+		t.Error("Supported() вернул неверный ответ")
+	}
+}
@@ -0,0 +1,73 @@
+package codec
+
+import (
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// mediaRange - Элемент заголовка Accept: text/*;q=0.5.
+type mediaRange struct {
+	typ, subtype string
+	q            float64
+	order        int
+}
+
+// parseAccept - Диапазоны по убыванию q, при равном q более конкретные раньше, дальше - в порядке заголовка.
+// Некорректные элементы пропускаются.
+func parseAccept(accept string) []mediaRange {
+	var ranges []mediaRange
+	for i, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		typ, subtype, ok := strings.Cut(mediaType, "/")
+		if !ok {
+			continue
+		}
+		q := 1.0
+		if raw, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(raw, 64); err != nil || q < 0 || q > 1 {
+				continue
+			}
+		}
+		ranges = append(ranges, mediaRange{typ: typ, subtype: subtype, q: q, order: i})
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		if ranges[i].q != ranges[j].q {
+			return ranges[i].q > ranges[j].q
+		}
+		return ranges[i].specificity() > ranges[j].specificity()
+	})
+	return ranges
+}
+
+func (m mediaRange) specificity() int {
+	switch {
+	case m.typ == "*":
+		return 0
+	case m.subtype == "*":
+		return 1
+	}
+	return 2
+}
+
+func (m mediaRange) matches(mediaType string) bool {
+	typ, subtype, _ := strings.Cut(mediaType, "/")
+	return (m.typ == "*" || m.typ == typ) && (m.subtype == "*" || m.subtype == subtype)
+}
+
+// decidingRange - Место в ranges самого конкретного диапазона, под который подходит тип, или -1.
+// Из равных по конкретности выбирается первый, то есть с большим q.
+func decidingRange(ranges []mediaRange, mediaType string) int {
+	pos := -1
+	for i, rng := range ranges {
+		if rng.matches(mediaType) && (pos < 0 || rng.specificity() > ranges[pos].specificity()) {
+			pos = i
+		}
+	}
+	return pos
+}
@@ -0,0 +1,40 @@
+package codec
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseAccept(t *testing.T) {
+	tests := []struct {
+		name   string
+		accept string
+		want   []mediaRange
+	}{
+		{"порядок заголовка при равном q", "text/csv, application/json", []mediaRange{
+			{typ: "text", subtype: "csv", q: 1, order: 0},
+			{typ: "application", subtype: "json", q: 1, order: 1},
+		}},
+		{"по убыванию q", "text/csv;q=0.5, application/json;q=0.9", []mediaRange{
+			{typ: "application", subtype: "json", q: 0.9, order: 1},
+			{typ: "text", subtype: "csv", q: 0.5, order: 0},
+		}},
+		{"при равном q конкретные раньше", "*/*, text/*, text/csv", []mediaRange{
+			{typ: "text", subtype: "csv", q: 1, order: 2},
+			{typ: "text", subtype: "*", q: 1, order: 1},
+			{typ: "*", subtype: "*", q: 1, order: 0},
+		}},
+		{"q=0 сохраняется", "*/*;q=0", []mediaRange{{typ: "*", subtype: "*", q: 0, order: 0}}},
+		{"некорректные элементы пропускаются", "json, text/csv;q=2, text/plain;q=abc, ;, application/xml;q=0.1", []mediaRange{
+			{typ: "application", subtype: "xml", q: 0.1, order: 4},
+		}},
+		{"пустой заголовок", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseAccept(tt.accept); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseAccept(%q) = %+v, ожидалось %+v", tt.accept, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+// Package codec - Форматы тел запросов и ответов и выбор формата по заголовкам Accept и Content-Type.
+// Все форматы строятся по тегам json, поэтому одни и те же DTO отдаются в любом из них.
+package codec
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"sort"
+	"strings"
+)
+
+// ErrUnsupported - Формат не может представить это значение, например вложенные списки в CSV.
+var ErrUnsupported = errors.New("значение не представимо в этом формате")
+
+// Codec - Формат тела. Encode пишет значение целиком, Decode читает одно значение.
+type Codec interface {
+	// MediaTypes - Типы содержимого формата. Первый отдаётся в Content-Type ответа.
+	MediaTypes() []string
+	// ContentType - Заголовок Content-Type ответа, например с charset.
+	ContentType() string
+	Encode(w io.Writer, v any) error
+	Decode(r io.Reader, v any) error
+}
+
+// Registry - Набор форматов. Первый зарегистрированный формат используется по умолчанию.
+type Registry struct {
+	codecs []Codec
+	byType map[string]Codec
+}
+
+// NewRegistry - Реестр из указанных форматов.
+func NewRegistry(codecs ...Codec) *Registry {
+	r := &Registry{byType: make(map[string]Codec)}
+	for _, c := range codecs {
+		r.Register(c)
+	}
+	return r
+}
+
+// Default - JSON (по умолчанию), XML, CSV, YAML и text/plain.
+func Default() *Registry {
+	return NewRegistry(JSON(), XML(), CSV(), YAML(), Text())
+}
+
+// Register - Добавляет формат. Формат с уже известным типом содержимого заменяет прежний для этого типа.
+func (r *Registry) Register(c Codec) {
+	r.codecs = append(r.codecs, c)
+	for _, t := range c.MediaTypes() {
+		r.byType[t] = c
+	}
+}
+
+// Default - Формат по умолчанию: для запросов без Content-Type и ответов без Accept.
+func (r *Registry) Default() Codec {
+	return r.codecs[0]
+}
+
+// MediaTypes - Основные типы всех форматов для сообщений об ошибках.
+func (r *Registry) MediaTypes() []string {
+	types := make([]string, 0, len(r.codecs))
+	for _, c := range r.codecs {
+		types = append(types, c.MediaTypes()[0])
+	}
+	return types
+}
+
+// ForContentType - Формат тела запроса. Пустой Content-Type означает формат по умолчанию.
+func (r *Registry) ForContentType(contentType string) (Codec, bool) {
+	if strings.TrimSpace(contentType) == "" {
+		return r.Default(), true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, false
+	}
+	c, ok := r.byType[mediaType]
+	return c, ok
+}
+
+// Negotiate - Форматы, которые принимает клиент, от более предпочтительного к менее.
+// Пустой Accept - только формат по умолчанию. Пустой результат означает 406.
+// Формату достаётся q самого конкретного подходящего диапазона (RFC 9110, 12.5.1): в
+// "*/*;q=0, application/json" запрещены все форматы, кроме JSON.
+func (r *Registry) Negotiate(accept string) []Codec {
+	if strings.TrimSpace(accept) == "" {
+		return []Codec{r.Default()}
+	}
+
+	ranges := parseAccept(accept)
+	// rank - Место диапазона, который решает за формат: диапазоны уже упорядочены по предпочтению.
+	rank := make(map[Codec]int)
+	var out []Codec
+	for _, c := range r.codecs {
+		// У формата с несколькими типами решает самый конкретный диапазон: application/yaml;q=0
+		// запрещает YAML, даже если text/yaml подходит под */*.
+		best := -1
+		for _, t := range c.MediaTypes() {
+			pos := decidingRange(ranges, t)
+			if pos >= 0 && (best < 0 || ranges[pos].specificity() > ranges[best].specificity() ||
+				ranges[pos].specificity() == ranges[best].specificity() && pos < best) {
+				best = pos
+			}
+		}
+		if best >= 0 && ranges[best].q > 0 {
+			rank[c] = best
+			out = append(out, c)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return rank[out[i]] < rank[out[j]] })
+	return out
+}
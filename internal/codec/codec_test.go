@@ -0,0 +1,40 @@
+package codec_test
+
+import (
+	"go-offline-test/internal/codec"
+	"reflect"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	r := codec.Default()
+	tests := []struct {
+		name   string
+		accept string
+		want   []string
+	}{
+		{"пустой Accept - формат по умолчанию", "", []string{"application/json"}},
+		{"любой формат - все в порядке реестра", "*/*", []string{"application/json", "application/xml", "text/csv", "application/yaml", "text/plain"}},
+		{"по убыванию q", "application/json;q=0.5, text/csv", []string{"text/csv", "application/json"}},
+		{"конкретный тип раньше диапазона", "text/*, text/csv", []string{"text/csv", "application/xml", "application/yaml", "text/plain"}},
+		{"q=0 запрещает формат", "*/*, text/csv;q=0", []string{"application/json", "application/xml", "application/yaml", "text/plain"}},
+		{"конкретный тип сильнее запрета */*", "*/*;q=0, application/json", []string{"application/json"}},
+		{"конкретный тип сильнее запрета text/*", "text/*;q=0, text/plain;q=0.5", []string{"text/plain"}},
+		{"запрет конкретного типа сильнее диапазона", "text/*, text/csv;q=0", []string{"application/xml", "application/yaml", "text/plain"}},
+		{"запрет основного типа сильнее другого типа формата", "application/yaml;q=0, */*", []string{"application/json", "application/xml", "text/csv", "text/plain"}},
+		{"второй тип формата", "application/problem+json", []string{"application/json"}},
+		{"только запреты - 406", "*/*;q=0", nil},
+		{"неизвестный тип - 406", "image/png", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, c := range r.Negotiate(tt.accept) {
+				got = append(got, c.MediaTypes()[0])
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Negotiate(%q) = %v, ожидалось %v", tt.accept, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package codec
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// listSeparator - Разделитель элементов списка внутри одной ячейки CSV.
+const listSeparator = ";"
+
+type csvCodec struct{}
+
+// CSV - text/csv с заголовком. Объект - одна строка, список объектов - по строке на элемент.
+// Вложенные объекты разворачиваются в колонки quota.max_quotes, списки строк пишутся через ";".
+func CSV() Codec {
+	return csvCodec{}
+}
+
+func (csvCodec) MediaTypes() []string {
+	return []string{"text/csv"}
+}
+
+func (csvCodec) ContentType() string {
+	return "text/csv; charset=utf-8"
+}
+
+func (csvCodec) Encode(w io.Writer, v any) error {
+	tree, err := toTree(v)
+	if err != nil {
+		return err
+	}
+
+	rows := []*node{tree}
+	if tree.kind == kindArray {
+		rows = tree.items
+	}
+
+	var header []string
+	index := make(map[string]int)
+	records := make([]map[string]string, 0, len(rows))
+	for _, row := range rows {
+		if row.kind != kindObject {
+			return ErrUnsupported
+		}
+		record := make(map[string]string)
+		if err := flatten(row, "", record, func(column string) {
+			if _, ok := index[column]; !ok {
+				index[column] = len(header)
+				header = append(header, column)
+			}
+		}); err != nil {
+			return err
+		}
+		records = append(records, record)
+	}
+	if len(header) == 0 {
+		return nil
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	line := make([]string, len(header))
+	for _, record := range records {
+		for i, column := range header {
+			line[i] = record[column]
+		}
+		if err := cw.Write(line); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// flatten - Колонки строки. Списки объектов в ячейку не укладываются - такое значение CSV не отдаёт.
+func flatten(n *node, prefix string, record map[string]string, column func(string)) error {
+	for i, key := range n.keys {
+		name := joinPath(prefix, key)
+		field := n.fields[i]
+		switch field.kind {
+		case kindObject:
+			if err := flatten(field, name, record, column); err != nil {
+				return err
+			}
+			continue
+		case kindArray:
+			items := make([]string, 0, len(field.items))
+			for _, item := range field.items {
+				if item.kind == kindObject || item.kind == kindArray {
+					return ErrUnsupported
+				}
+				items = append(items, item.text)
+			}
+			record[name] = strings.Join(items, listSeparator)
+		default:
+			record[name] = field.text
+		}
+		column(name)
+	}
+	return nil
+}
+
+// Decode - Заголовок и одна строка. Колонки вида quota.max_quotes заполняют вложенные объекты.
+func (csvCodec) Decode(r io.Reader, v any) error {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return errors.New("пустой CSV: ожидается заголовок и строка")
+	}
+	if err != nil {
+		return err
+	}
+	record, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return errors.New("в CSV только заголовок, нет строки с данными")
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := cr.Read(); !errors.Is(err, io.EOF) {
+		return fmt.Errorf("ожидается одна строка с данными")
+	}
+
+	doc := make(map[string]any)
+	for i, column := range header {
+		obj := doc
+		parts := strings.Split(strings.TrimSpace(column), ".")
+		for _, part := range parts[:len(parts)-1] {
+			next, ok := obj[part].(map[string]any)
+			if !ok {
+				next = make(map[string]any)
+				obj[part] = next
+			}
+			obj = next
+		}
+		obj[parts[len(parts)-1]] = record[i]
+	}
+	return decodeInto(v, doc)
+}
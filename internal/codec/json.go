@@ -0,0 +1,29 @@
+package codec
+
+import (
+	"encoding/json"
+	"io"
+)
+
+type jsonCodec struct{}
+
+// JSON - application/json.
+func JSON() Codec {
+	return jsonCodec{}
+}
+
+func (jsonCodec) MediaTypes() []string {
+	return []string{"application/json", "application/problem+json"}
+}
+
+func (jsonCodec) ContentType() string {
+	return "application/json"
+}
+
+func (jsonCodec) Encode(w io.Writer, v any) error {
+	return json.NewEncoder(w).Encode(v)
+}
+
+func (jsonCodec) Decode(r io.Reader, v any) error {
+	return json.NewDecoder(r).Decode(v)
+}
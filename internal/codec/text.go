@@ -0,0 +1,120 @@
+package codec
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+type textCodec struct{}
+
+// Text - text/plain. Значения с методом String (цитата - "текст — автор") пишутся им, списки - по строке
+// на элемент. Остальные объекты - строками "поле: значение".
+func Text() Codec {
+	return textCodec{}
+}
+
+// textParser - Значение, которое разбирается из одной строки text/plain.
+type textParser interface {
+	ParseText(line string) error
+}
+
+func (textCodec) MediaTypes() []string {
+	return []string{"text/plain"}
+}
+
+func (textCodec) ContentType() string {
+	return "text/plain; charset=utf-8"
+}
+
+func (textCodec) Encode(w io.Writer, v any) error {
+	bw := bufio.NewWriter(w)
+	if lines, ok := stringLines(v); ok {
+		for _, line := range lines {
+			fmt.Fprintln(bw, line)
+		}
+		return bw.Flush()
+	}
+
+	tree, err := toTree(v)
+	if err != nil {
+		return err
+	}
+	if tree.kind == kindArray {
+		for i, item := range tree.items {
+			if i > 0 {
+				fmt.Fprintln(bw)
+			}
+			writeText(bw, item, "")
+		}
+	} else {
+		writeText(bw, tree, "")
+	}
+	return bw.Flush()
+}
+
+// stringLines - Строки для fmt.Stringer или списка из них.
+func stringLines(v any) ([]string, bool) {
+	if s, ok := v.(fmt.Stringer); ok {
+		return []string{s.String()}, true
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice || !rv.Type().Elem().Implements(reflect.TypeOf((*fmt.Stringer)(nil)).Elem()) {
+		return nil, false
+	}
+	lines := make([]string, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		lines = append(lines, rv.Index(i).Interface().(fmt.Stringer).String())
+	}
+	return lines, true
+}
+
+func writeText(w io.Writer, n *node, prefix string) {
+	switch n.kind {
+	case kindObject:
+		for i, key := range n.keys {
+			writeText(w, n.fields[i], joinPath(prefix, key))
+		}
+	case kindArray:
+		items := make([]string, 0, len(n.items))
+		for _, item := range n.items {
+			items = append(items, item.text)
+			if item.kind != kindScalar {
+				for j, nested := range n.items {
+					writeText(w, nested, fmt.Sprintf("%s[%d]", prefix, j))
+				}
+				return
+			}
+		}
+		fmt.Fprintf(w, "%s: %s\n", prefix, strings.Join(items, ", "))
+	case kindScalar:
+		if prefix == "" {
+			fmt.Fprintln(w, n.text)
+			return
+		}
+		fmt.Fprintf(w, "%s: %s\n", prefix, n.text)
+	}
+}
+
+// Decode - Одна строка в значение с методом ParseText.
+func (textCodec) Decode(r io.Reader, v any) error {
+	parser, ok := v.(textParser)
+	if !ok {
+		return ErrUnsupported
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	line := strings.TrimSpace(string(data))
+	if line == "" {
+		return errors.New("пустое тело запроса")
+	}
+	if strings.Contains(line, "\n") {
+		return errors.New("ожидается одна строка")
+	}
+	return parser.ParseText(line)
+}
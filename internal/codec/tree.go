@@ -0,0 +1,245 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// node - Значение после кодирования в JSON с сохранённым порядком полей. XML, CSV и текст
+// строятся по нему, поэтому имена полей, omitempty и формат дат совпадают с JSON.
+type node struct {
+	// keys, fields - Поля объекта в порядке вывода.
+	keys   []string
+	fields []*node
+	items  []*node
+	kind   nodeKind
+	// text - Скаляр: строка без кавычек, число или true/false.
+	text string
+}
+
+type nodeKind int
+
+const (
+	kindNull nodeKind = iota
+	kindScalar
+	kindObject
+	kindArray
+)
+
+func toTree(v any) (*node, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return readNode(dec)
+}
+
+func readNode(dec *json.Decoder) (*node, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		if t == '{' {
+			n := &node{kind: kindObject}
+			for dec.More() {
+				key, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				field, err := readNode(dec)
+				if err != nil {
+					return nil, err
+				}
+				n.keys = append(n.keys, key.(string))
+				n.fields = append(n.fields, field)
+			}
+			_, err := dec.Token()
+			return n, err
+		}
+		n := &node{kind: kindArray}
+		for dec.More() {
+			item, err := readNode(dec)
+			if err != nil {
+				return nil, err
+			}
+			n.items = append(n.items, item)
+		}
+		_, err := dec.Token()
+		return n, err
+	case nil:
+		return &node{kind: kindNull}, nil
+	case string:
+		return &node{kind: kindScalar, text: t}, nil
+	case json.Number:
+		return &node{kind: kindScalar, text: t.String()}, nil
+	case bool:
+		return &node{kind: kindScalar, text: strconv.FormatBool(t)}, nil
+	}
+	return nil, fmt.Errorf("неожиданный токен %v", tok)
+}
+
+// elementName - Имя типа в snake_case для корня XML: APIKey - api_key, AuditEntry - audit_entry.
+func elementName(t reflect.Type) string {
+	if t == nil {
+		return "response"
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	name := t.Name()
+	if name == "" {
+		return "response"
+	}
+
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prevLower := unicode.IsLower(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || (nextLower && unicode.IsUpper(runes[i-1])) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// assign - Записывает разобранное значение (объекты map[string]any, скаляры строками или числами)
+// в структуру по тегам json. Нужен форматам, в которых нет типов: XML, CSV и YAML.
+func assign(dst reflect.Value, src any, path string) error {
+	if src == nil {
+		return nil
+	}
+	if dst.Kind() == reflect.Pointer {
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		return assign(dst.Elem(), src, path)
+	}
+
+	if m, ok := src.(map[string]any); ok {
+		if dst.Kind() != reflect.Struct {
+			return fmt.Errorf("%s: ожидается одно значение, а не объект", path)
+		}
+		fields := jsonFields(dst.Type())
+		for key, value := range m {
+			i, ok := fields[key]
+			if !ok {
+				continue
+			}
+			if err := assign(dst.Field(i), value, joinPath(path, key)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if list, ok := src.([]any); ok {
+		if dst.Kind() != reflect.Slice {
+			return fmt.Errorf("%s: ожидается одно значение, а не список", path)
+		}
+		out := reflect.MakeSlice(dst.Type(), len(list), len(list))
+		for i, item := range list {
+			if err := assign(out.Index(i), item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		dst.Set(out)
+		return nil
+	}
+
+	raw := fmt.Sprint(src)
+	switch {
+	case dst.Type() == timeType:
+		t, err := time.Parse(time.RFC3339Nano, raw)
+		if err != nil {
+			return fmt.Errorf("%s: ожидается дата RFC3339, получено %q", path, raw)
+		}
+		dst.Set(reflect.ValueOf(t))
+	case dst.Type() == durationType:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("%s: ожидается длительность, получено %q", path, raw)
+		}
+		dst.SetInt(int64(d))
+	case dst.Kind() == reflect.String:
+		dst.SetString(raw)
+	case dst.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("%s: ожидается true или false, получено %q", path, raw)
+		}
+		dst.SetBool(b)
+	case dst.CanInt():
+		n, err := strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
+		if err != nil {
+			return fmt.Errorf("%s: ожидается целое число, получено %q", path, raw)
+		}
+		dst.SetInt(n)
+	case dst.CanFloat():
+		n, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+		if err != nil {
+			return fmt.Errorf("%s: ожидается число, получено %q", path, raw)
+		}
+		dst.SetFloat(n)
+	case dst.Kind() == reflect.Slice && dst.Type().Elem().Kind() == reflect.String:
+		// Список одной строкой, как в CSV: a;b.
+		dst.Set(reflect.ValueOf(strings.Split(raw, listSeparator)))
+	default:
+		return fmt.Errorf("%s: тип %s не поддерживается", path, dst.Type())
+	}
+	return nil
+}
+
+// jsonFields - Индексы полей структуры по именам из тегов json.
+func jsonFields(t reflect.Type) map[string]int {
+	fields := make(map[string]int, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = sf.Name
+		}
+		fields[name] = i
+	}
+	return fields
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// decodeInto - Проверяет, что v - указатель, и заполняет его.
+func decodeInto(v any, src any) error {
+	dst := reflect.ValueOf(v)
+	if dst.Kind() != reflect.Pointer || dst.IsNil() {
+		return fmt.Errorf("декодирование в %T: нужен непустой указатель", v)
+	}
+	return assign(dst.Elem(), src, "")
+}
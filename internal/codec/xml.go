@@ -0,0 +1,169 @@
+package codec
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+type xmlCodec struct{}
+
+// XML - application/xml, также принимается text/xml. Корень называется по типу значения
+// (quote, tenant), список - во множественном числе (quotes), элементы вложенных списков - item.
+func XML() Codec {
+	return xmlCodec{}
+}
+
+func (xmlCodec) MediaTypes() []string {
+	return []string{"application/xml", "text/xml", "application/problem+xml"}
+}
+
+func (xmlCodec) ContentType() string {
+	return "application/xml; charset=utf-8"
+}
+
+func (xmlCodec) Encode(w io.Writer, v any) error {
+	tree, err := toTree(v)
+	if err != nil {
+		return err
+	}
+
+	name, itemName := elementName(reflect.TypeOf(v)), "item"
+	if t := reflect.TypeOf(v); t != nil && t.Kind() == reflect.Slice {
+		itemName = elementName(t.Elem())
+		name = itemName + "s"
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := writeXML(enc, name, tree, itemName); err != nil {
+		return err
+	}
+	if err := enc.Flush(); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+func writeXML(enc *xml.Encoder, name string, n *node, itemName string) error {
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	if !validXMLName(name) {
+		// Ключ словаря, который не годится в имя элемента, уходит в атрибут.
+		start = xml.StartElement{Name: xml.Name{Local: "entry"}, Attr: []xml.Attr{{Name: xml.Name{Local: "key"}, Value: name}}}
+	}
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+
+	switch n.kind {
+	case kindScalar:
+		if err := enc.EncodeToken(xml.CharData(n.text)); err != nil {
+			return err
+		}
+	case kindObject:
+		for i, key := range n.keys {
+			if err := writeXML(enc, key, n.fields[i], "item"); err != nil {
+				return err
+			}
+		}
+	case kindArray:
+		for _, item := range n.items {
+			if err := writeXML(enc, itemName, item, "item"); err != nil {
+				return err
+			}
+		}
+	}
+	return enc.EncodeToken(start.End())
+}
+
+func validXMLName(name string) bool {
+	if name == "" || strings.HasPrefix(strings.ToLower(name), "xml") {
+		return false
+	}
+	for i, r := range name {
+		letter := r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+		if !letter && (i == 0 || !(r == '-' || r == '.' || (r >= '0' && r <= '9'))) {
+			return false
+		}
+	}
+	return true
+}
+
+// Decode - Дочерние элементы корня становятся полями, повторяющиеся элементы - списком.
+func (xmlCodec) Decode(r io.Reader, v any) error {
+	dec := xml.NewDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return errors.New("пустой XML-документ")
+			}
+			return err
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			value, err := readXML(dec, start)
+			if err != nil {
+				return err
+			}
+			return decodeInto(v, value)
+		}
+	}
+}
+
+// readXML - Элемент с дочерними элементами - map[string]any, без них - строка.
+func readXML(dec *xml.Decoder, start xml.StartElement) (any, error) {
+	var text strings.Builder
+	var fields map[string]any
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("элемент %s: %w", start.Name.Local, err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			value, err := readXML(dec, t)
+			if err != nil {
+				return nil, err
+			}
+			if fields == nil {
+				fields = make(map[string]any)
+			}
+			key := t.Name.Local
+			switch prev := fields[key].(type) {
+			case nil:
+				fields[key] = value
+			case []any:
+				fields[key] = append(prev, value)
+			default:
+				fields[key] = []any{prev, value}
+			}
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			if fields != nil {
+				return listOrFields(fields), nil
+			}
+			return strings.TrimSpace(text.String()), nil
+		}
+	}
+}
+
+// listOrFields - <scopes><item>a</item><item>b</item></scopes> - это список, а не объект с полем item.
+func listOrFields(fields map[string]any) any {
+	if len(fields) == 1 {
+		if items, ok := fields["item"]; ok {
+			if list, ok := items.([]any); ok {
+				return list
+			}
+			return []any{items}
+		}
+	}
+	return fields
+}
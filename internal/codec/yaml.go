@@ -0,0 +1,47 @@
+package codec
+
+import (
+	"go-offline-test/internal/shared/encoding/yaml"
+	"io"
+)
+
+type yamlCodec struct{}
+
+// yamlLimits - Тело запроса присылает клиент, поэтому документ разбирается с ограничениями,
+// которых с запасом хватает любому DTO. Размер тела, кроме того, ограничивает server.max_body_bytes.
+var yamlLimits = yaml.Limits{MaxBytes: 4 << 20, MaxDepth: 32, MaxNodes: 100_000}
+
+// YAML - application/yaml, также принимаются application/x-yaml и text/yaml.
+func YAML() Codec {
+	return yamlCodec{}
+}
+
+func (yamlCodec) MediaTypes() []string {
+	return []string{"application/yaml", "application/x-yaml", "text/yaml"}
+}
+
+func (yamlCodec) ContentType() string {
+	return "application/yaml; charset=utf-8"
+}
+
+func (yamlCodec) Encode(w io.Writer, v any) error {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func (yamlCodec) Decode(r io.Reader, v any) error {
+	// На байт больше предела: документ длиннее предела отклонит UnmarshalLimits.
+	data, err := io.ReadAll(io.LimitReader(r, int64(yamlLimits.MaxBytes)+1))
+	if err != nil {
+		return err
+	}
+	doc, err := yaml.UnmarshalLimits(data, yamlLimits)
+	if err != nil {
+		return err
+	}
+	return decodeInto(v, doc)
+}
@@ -0,0 +1,137 @@
+package filter
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Действия фильтра при находке.
+const (
+	// ActionReject - Цитата не сохраняется.
+	ActionReject = "reject"
+	// ActionFlag - Цитата сохраняется и ждёт модерации.
+	ActionFlag = "flag"
+	// ActionRewrite - Найденные фрагменты исправляются, цитата сохраняется.
+	ActionRewrite = "rewrite"
+)
+
+// Поля цитаты, которые проходят через фильтры.
+const (
+	FieldQuote  = "quote"
+	FieldAuthor = "author"
+)
+
+// Match - Найденный фрагмент: байтовые границы в тексте.
+type Match struct {
+	Start, End int
+}
+
+// Filter - Один фильтр цепочки.
+type Filter interface {
+	// Name - Имя фильтра в настройках и в ответах.
+	Name() string
+	// Find - Фрагменты текста, на которые сработал фильтр, по возрастанию и без пересечений.
+	Find(text string) []Match
+	// Replace - Чем заменить найденный фрагмент при действии rewrite.
+	Replace(fragment string) string
+}
+
+// stage - Фильтр и действие при находке.
+type stage struct {
+	filter Filter
+	action string
+}
+
+// Hit - Сработавший фильтр: поле, действие и ключ сообщения в каталоге.
+type Hit struct {
+	Filter string
+	Field  string
+	Action string
+	Key    string
+}
+
+// Result - Итог прохода цитаты через цепочку.
+type Result struct {
+	// Text, Author - Значения после исправлений.
+	Text, Author string
+	// Rejected - Фильтр, отклонивший цитату. nil - цитата принята.
+	Rejected *Hit
+	// Flagged - Фильтры, из-за которых цитата уходит на модерацию.
+	Flagged []Hit
+	// Rewritten - Фильтры, исправившие текст.
+	Rewritten []Hit
+}
+
+// Flags - Имена фильтров, отправивших цитату на модерацию, без повторов.
+func (r *Result) Flags() []string {
+	var flags []string
+	for _, hit := range r.Flagged {
+		if !slices.Contains(flags, hit.Filter) {
+			flags = append(flags, hit.Filter)
+		}
+	}
+	return flags
+}
+
+// Pipeline - Цепочка фильтров в порядке из настроек.
+type Pipeline struct {
+	stages []stage
+}
+
+// Filters - Имена фильтров цепочки по порядку.
+func (p *Pipeline) Filters() []string {
+	names := make([]string, 0, len(p.stages))
+	for _, s := range p.stages {
+		if !slices.Contains(names, s.filter.Name()) {
+			names = append(names, s.filter.Name())
+		}
+	}
+	return names
+}
+
+// Run - Пропускает цитату через цепочку. Исправления одного фильтра видят следующие. Первый
+// отклонивший фильтр останавливает проход.
+func (p *Pipeline) Run(text, author string) *Result {
+	res := &Result{Text: text, Author: author}
+	for _, s := range p.stages {
+		for _, field := range []string{FieldQuote, FieldAuthor} {
+			value := &res.Text
+			if field == FieldAuthor {
+				value = &res.Author
+			}
+			matches := s.filter.Find(*value)
+			if len(matches) == 0 {
+				continue
+			}
+
+			hit := Hit{Filter: s.filter.Name(), Field: field, Action: s.action, Key: "filter." + field + "_" + s.filter.Name()}
+			switch s.action {
+			case ActionReject:
+				res.Rejected = &hit
+				return res
+			case ActionFlag:
+				res.Flagged = append(res.Flagged, hit)
+			case ActionRewrite:
+				*value = rewrite(*value, matches, s.filter)
+				res.Rewritten = append(res.Rewritten, hit)
+			}
+		}
+	}
+	return res
+}
+
+var spaces = regexp.MustCompile(`[ \t]{2,}`)
+
+// rewrite - Заменяет фрагменты. Пробелы, оставшиеся на месте удалённых фрагментов, схлопываются.
+func rewrite(text string, matches []Match, f Filter) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		b.WriteString(text[last:m.Start])
+		b.WriteString(f.Replace(text[m.Start:m.End]))
+		last = m.End
+	}
+	b.WriteString(text[last:])
+	return strings.TrimSpace(spaces.ReplaceAllString(b.String(), " "))
+}
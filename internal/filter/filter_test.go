@@ -0,0 +1,204 @@
+package filter_test
+
+import (
+	"go-offline-test/internal/filter"
+	"go-offline-test/internal/shared/dto/config"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// load - Цепочка из настроек по умолчанию и слова «редиска» в словаре; edit меняет только нужное.
+func load(t *testing.T, edit func(*config.FilterConfig)) *filter.Pipeline {
+	t.Helper()
+	conf := config.FilterConfig{
+		Actions:        map[string]string{},
+		ProfanityWords: []string{"редиска"},
+		MaxRepeats:     3,
+		RegexRules:     map[string][]string{},
+	}
+	edit(&conf)
+	p, errs := filter.Load(conf)
+	if len(errs) > 0 {
+		t.Fatalf("Load(): %v", errs)
+	}
+	return p
+}
+
+func TestLoadChainOrder(t *testing.T) {
+	p := load(t, func(c *config.FilterConfig) {
+		c.Chain = []string{"regex", "repeats", "spam", "profanity"}
+		c.RegexRules = map[string][]string{"rewrite": {"плохо"}, "reject": {"запрет"}}
+	})
+	if got, want := p.Filters(), []string{"regex", "repeats", "spam", "profanity"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Filters() = %v, ожидалось %v", got, want)
+	}
+	if got := load(t, func(*config.FilterConfig) {}).Filters(); len(got) != 0 {
+		t.Errorf("Filters() пустой цепочки = %v", got)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	conf := config.FilterConfig{
+		Chain:      []string{"spam", "captcha", "spam"},
+		Actions:    map[string]string{"profanity": "delete", "regex": "reject", "links": "flag"},
+		MaxRepeats: 1,
+		RegexRules: map[string][]string{"block": {"a"}, "reject": {"("}},
+	}
+	p, errs := filter.Load(conf)
+	if p != nil {
+		t.Error("Load() с ошибками вернул цепочку")
+	}
+	wants := []string{
+		`filter.actions: неизвестный фильтр "links"`,
+		`filter.actions: фильтр profanity: неизвестное действие "delete"`,
+		"filter.actions: действия regex задаются в filter.regex_rules",
+		"filter.max_repeats: должно быть не меньше 2",
+		`filter.regex_rules: неизвестное действие "block"`,
+		"filter.regex_rules: reject: ",
+		`filter.chain: неизвестный фильтр "captcha"`,
+		`filter.chain: фильтр "spam" указан дважды`,
+	}
+	if len(errs) != len(wants) {
+		t.Errorf("Load() вернул %d ошибок, ожидалось %d: %v", len(errs), len(wants), errs)
+	}
+	for _, want := range wants {
+		found := false
+		for _, err := range errs {
+			found = found || strings.Contains(err.Error(), want)
+		}
+		if !found {
+			t.Errorf("Load(): нет ошибки %q среди %v", want, errs)
+		}
+	}
+}
+
+func TestPipelineRun(t *testing.T) {
+	tests := []struct {
+		name         string
+		conf         func(*config.FilterConfig)
+		text, author string
+		want         filter.Result
+	}{
+		{
+			name:   "маскированное слово отклоняется",
+			conf:   func(c *config.FilterConfig) { c.Chain = []string{"profanity"} },
+			text:   "Ты peдиcкa", // латинские p, e, c, a
+			author: "Автор",
+			want: filter.Result{Text: "Ты peдиcкa", Author: "Автор", Rejected: &filter.Hit{
+				Filter: "profanity", Field: "quote", Action: "reject", Key: "filter.quote_profanity",
+			}},
+		},
+		{
+			name: "то же слово при rewrite закрывается",
+			conf: func(c *config.FilterConfig) {
+				c.Chain = []string{"profanity"}
+				c.Actions["profanity"] = "rewrite"
+			},
+			text:   "Ты peдиcкa",
+			author: "Автор",
+			want: filter.Result{Text: "Ты p******", Author: "Автор", Rewritten: []filter.Hit{
+				{Filter: "profanity", Field: "quote", Action: "rewrite", Key: "filter.quote_profanity"},
+			}},
+		},
+		{
+			name:   "отклонение останавливает цепочку",
+			conf:   func(c *config.FilterConfig) { c.Chain = []string{"spam", "profanity", "repeats"} },
+			text:   "Редиска!!!!! www.example.com",
+			author: "Автор",
+			want: filter.Result{
+				Text: "Редиска!!!!! www.example.com", Author: "Автор",
+				Flagged:  []filter.Hit{{Filter: "spam", Field: "quote", Action: "flag", Key: "filter.quote_spam"}},
+				Rejected: &filter.Hit{Filter: "profanity", Field: "quote", Action: "reject", Key: "filter.quote_profanity"},
+			},
+		},
+		{
+			name: "исправления видят следующие фильтры: сначала profanity",
+			conf: func(c *config.FilterConfig) {
+				c.Chain = []string{"profanity", "repeats"}
+				c.Actions["profanity"] = "rewrite"
+			},
+			text:   "Редиииииска",
+			author: "Автор",
+			want: filter.Result{Text: "Р**********", Author: "Автор", Rewritten: []filter.Hit{
+				{Filter: "profanity", Field: "quote", Action: "rewrite", Key: "filter.quote_profanity"},
+			}},
+		},
+		{
+			name: "исправления видят следующие фильтры: сначала repeats",
+			conf: func(c *config.FilterConfig) {
+				c.Chain = []string{"repeats", "profanity"}
+				c.Actions["profanity"] = "rewrite"
+			},
+			text:   "Редиииииска",
+			author: "Автор",
+			want: filter.Result{Text: "Р********", Author: "Автор", Rewritten: []filter.Hit{
+				{Filter: "repeats", Field: "quote", Action: "rewrite", Key: "filter.quote_repeats"},
+				{Filter: "profanity", Field: "quote", Action: "rewrite", Key: "filter.quote_profanity"},
+			}},
+		},
+		{
+			name: "ссылка удаляется, пробелы схлопываются",
+			conf: func(c *config.FilterConfig) {
+				c.Chain = []string{"spam"}
+				c.Actions["spam"] = "rewrite"
+			},
+			text:   "Смотри www.example.com сейчас",
+			author: "Автор @channelname",
+			want: filter.Result{Text: "Смотри сейчас", Author: "Автор", Rewritten: []filter.Hit{
+				{Filter: "spam", Field: "quote", Action: "rewrite", Key: "filter.quote_spam"},
+				{Filter: "spam", Field: "author", Action: "rewrite", Key: "filter.author_spam"},
+			}},
+		},
+		{
+			name: "правила regex: отклонение раньше исправления",
+			conf: func(c *config.FilterConfig) {
+				c.Chain = []string{"regex"}
+				c.RegexRules = map[string][]string{"rewrite": {"плохо"}, "reject": {"запрет"}}
+			},
+			text:   "плохо и запрет",
+			author: "Автор",
+			want: filter.Result{Text: "плохо и запрет", Author: "Автор", Rejected: &filter.Hit{
+				Filter: "regex", Field: "quote", Action: "reject", Key: "filter.quote_regex",
+			}},
+		},
+		{
+			name: "правила regex: исправление",
+			conf: func(c *config.FilterConfig) {
+				c.Chain = []string{"regex"}
+				c.RegexRules = map[string][]string{"rewrite": {"плохо"}, "reject": {"запрет"}}
+			},
+			text:   "Это плохо",
+			author: "Автор",
+			want: filter.Result{Text: "Это *****", Author: "Автор", Rewritten: []filter.Hit{
+				{Filter: "regex", Field: "quote", Action: "rewrite", Key: "filter.quote_regex"},
+			}},
+		},
+		{
+			name:   "обычный текст проходит",
+			conf:   func(c *config.FilterConfig) { c.Chain = []string{"profanity", "spam", "repeats"} },
+			text:   "В 1000000 раз лучше, т.е. который год",
+			author: "Автор",
+			want:   filter.Result{Text: "В 1000000 раз лучше, т.е. который год", Author: "Автор"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := load(t, tt.conf).Run(tt.text, tt.author)
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("Run(%q, %q) = %+v, ожидалось %+v", tt.text, tt.author, *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResultFlags(t *testing.T) {
+	p := load(t, func(c *config.FilterConfig) {
+		c.Chain = []string{"spam", "repeats"}
+		c.Actions["repeats"] = "flag"
+	})
+	res := p.Run("Пишите на www.example.com!!!!!", "@channelname")
+	if got, want := res.Flags(), []string{"spam", "repeats"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Flags() = %v, ожидалось %v (сработавших фильтров %d)", got, want, len(res.Flagged))
+	}
+}
@@ -0,0 +1,111 @@
+package filter
+
+import (
+	"fmt"
+	"go-offline-test/internal/shared/dto/config"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Filters - Имена фильтров, которые можно включить в цепочку.
+var Filters = []string{"profanity", "spam", "repeats", "regex"}
+
+// defaultActions - Действия фильтров, для которых в настройках ничего не указано.
+var defaultActions = map[string]string{
+	"profanity": ActionReject,
+	"spam":      ActionFlag,
+	"repeats":   ActionRewrite,
+}
+
+// regexOrder - Правила regex применяются от строгих к мягким: отклонение не ждёт исправлений.
+var regexOrder = []string{ActionReject, ActionFlag, ActionRewrite}
+
+// Load - Собирает цепочку фильтров из настроек. Возвращает все найденные ошибки сразу, как и
+// проверка остальных настроек. При ошибках Pipeline не возвращается.
+func Load(conf config.FilterConfig) (*Pipeline, []error) {
+	var errs []error
+	fail := func(key, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("filter.%s: %s", key, fmt.Sprintf(format, args...)))
+	}
+
+	for _, name := range sortedKeys(conf.Actions) {
+		action := conf.Actions[name]
+		switch {
+		case name == "regex":
+			fail("actions", "действия regex задаются в filter.regex_rules")
+		case defaultActions[name] == "":
+			fail("actions", "неизвестный фильтр %q, допустимы: profanity, spam, repeats", name)
+		case !validAction(action):
+			fail("actions", "фильтр %s: неизвестное действие %q, допустимы: %s", name, action, strings.Join(regexOrder, ", "))
+		}
+	}
+	if conf.MaxRepeats < 2 {
+		fail("max_repeats", "должно быть не меньше 2")
+	}
+
+	regexRules := make(map[string][]*regexp.Regexp, len(conf.RegexRules))
+	for _, action := range sortedKeys(conf.RegexRules) {
+		if !validAction(action) {
+			fail("regex_rules", "неизвестное действие %q, допустимы: %s", action, strings.Join(regexOrder, ", "))
+			continue
+		}
+		for _, pattern := range conf.RegexRules[action] {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				fail("regex_rules", "%s: %v", action, err)
+				continue
+			}
+			regexRules[action] = append(regexRules[action], re)
+		}
+	}
+
+	p := &Pipeline{}
+	seen := make(map[string]bool, len(conf.Chain))
+	for _, name := range conf.Chain {
+		if seen[name] {
+			fail("chain", "фильтр %q указан дважды", name)
+			continue
+		}
+		seen[name] = true
+
+		action := conf.Actions[name]
+		if action == "" {
+			action = defaultActions[name]
+		}
+		switch name {
+		case "profanity":
+			p.stages = append(p.stages, stage{newProfanity(conf.ProfanityWords), action})
+		case "spam":
+			p.stages = append(p.stages, stage{spam{}, action})
+		case "repeats":
+			p.stages = append(p.stages, stage{repeats{max: conf.MaxRepeats}, action})
+		case "regex":
+			for _, action := range regexOrder {
+				if patterns := regexRules[action]; len(patterns) > 0 {
+					p.stages = append(p.stages, stage{regex{patterns: patterns}, action})
+				}
+			}
+		default:
+			fail("chain", "неизвестный фильтр %q, допустимы: %s", name, strings.Join(Filters, ", "))
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return p, nil
+}
+
+func validAction(action string) bool {
+	return action == ActionReject || action == ActionFlag || action == ActionRewrite
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,189 @@
+package filter
+
+import (
+	_ "embed"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+//go:embed profanity.txt
+var builtinWords string
+
+// toCyrillic, toLatin - Похожие символы другого алфавита и цифры, которыми маскируют буквы.
+var (
+	toCyrillic = strings.NewReplacer(
+		"A", "а", "B", "в", "C", "с", "E", "е", "H", "н", "K", "к", "M", "м", "O", "о", "P", "р", "T", "т", "X", "х", "Y", "у",
+		"a", "а", "c", "с", "e", "е", "k", "к", "m", "м", "o", "о", "p", "р", "x", "х", "y", "у", "u", "и",
+		"0", "о", "3", "з", "4", "ч", "6", "б", "@", "а",
+	)
+	toLatin = strings.NewReplacer(
+		"А", "a", "В", "b", "С", "c", "Е", "e", "Н", "h", "К", "k", "М", "m", "О", "o", "Р", "p", "Т", "t", "Х", "x", "У", "y",
+		"а", "a", "в", "b", "с", "c", "е", "e", "н", "h", "к", "k", "м", "m", "о", "o", "р", "p", "т", "t", "х", "x", "у", "y",
+		"0", "o", "1", "i", "3", "e", "4", "a", "5", "s", "7", "t", "@", "a", "$", "s",
+	)
+)
+
+// profanity - Нецензурная лексика по словарю. Ловит простые маскировки: буквы другого алфавита
+// и цифры вместо букв (с0ка, fuсk), растянутые буквы (бляяя) и слова по буквам через
+// разделитель (х.у.й, f u c k).
+type profanity struct {
+	words    map[string]bool
+	prefixes []string
+}
+
+// newProfanity - Встроенный словарь и дополнительные слова. "*" на конце слова - совпадение по началу.
+func newProfanity(extra []string) *profanity {
+	p := &profanity{words: make(map[string]bool)}
+	entries := append(strings.Split(builtinWords, "\n"), extra...)
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+		if root, ok := strings.CutSuffix(entry, "*"); ok {
+			p.prefixes = append(p.prefixes, normalize(root))
+			continue
+		}
+		p.words[normalize(entry)] = true
+	}
+	return p
+}
+
+func (p *profanity) Name() string {
+	return "profanity"
+}
+
+func (p *profanity) Find(text string) []Match {
+	var out []Match
+	for _, w := range words(text) {
+		if p.match(text[w.Start:w.End]) {
+			out = append(out, w)
+		}
+	}
+	return out
+}
+
+// Replace - Оставляет первую букву, остальные закрывает звёздочками.
+func (p *profanity) Replace(fragment string) string {
+	first, size := utf8.DecodeRuneInString(fragment)
+	return string(first) + strings.Repeat("*", utf8.RuneCountInString(fragment[size:]))
+}
+
+// match - Совпадает ли слово со словарём в одном из алфавитов. Растянутые буквы сжимаются, только
+// если в слове есть повтор из трёх и более букв: иначе обычное слово могло бы совпасть
+// со сжатым словарным.
+func (p *profanity) match(word string) bool {
+	word = stripSeparators(word)
+	for _, candidate := range []string{toCyrillic.Replace(word), toLatin.Replace(word)} {
+		candidate = normalize(candidate)
+		if p.matchWord(candidate) {
+			return true
+		}
+		if stretched(candidate) && p.matchWord(squeeze(candidate)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *profanity) matchWord(word string) bool {
+	if p.words[word] {
+		return true
+	}
+	for _, prefix := range p.prefixes {
+		if strings.HasPrefix(word, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalize - Нижний регистр и е вместо ё.
+func normalize(s string) string {
+	return strings.ReplaceAll(strings.ToLower(s), "ё", "е")
+}
+
+// wordRune - Символы, из которых состоит слово, включая замаскированное: буквы, цифры и @, $.
+func wordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r) || r == '@' || r == '$'
+}
+
+// words - Границы слов в тексте. Подряд идущие однобуквенные слова с разделителями не длиннее
+// двух символов склеиваются в одно: так пишут слово по буквам, чтобы обойти фильтр.
+func words(text string) []Match {
+	var tokens []Match
+	start := -1
+	for i, r := range text {
+		switch {
+		case wordRune(r) && start < 0:
+			start = i
+		case !wordRune(r) && start >= 0:
+			tokens = append(tokens, Match{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, Match{start, len(text)})
+	}
+
+	var out []Match
+	for i := 0; i < len(tokens); i++ {
+		j := i
+		for j+1 < len(tokens) && single(text, tokens[j]) && single(text, tokens[j+1]) &&
+			utf8.RuneCountInString(text[tokens[j].End:tokens[j+1].Start]) <= 2 {
+			j++
+		}
+		if j-i >= 2 {
+			out = append(out, Match{tokens[i].Start, tokens[j].End})
+			i = j
+			continue
+		}
+		out = append(out, tokens[i])
+	}
+	return out
+}
+
+func single(text string, m Match) bool {
+	return utf8.RuneCountInString(text[m.Start:m.End]) == 1
+}
+
+// stripSeparators - Слово без разделителей между буквами.
+func stripSeparators(word string) string {
+	return strings.Map(func(r rune) rune {
+		if wordRune(r) {
+			return r
+		}
+		return -1
+	}, word)
+}
+
+// stretched - Есть ли в слове одна буква три раза подряд.
+func stretched(word string) bool {
+	var prev rune
+	run := 0
+	for _, r := range word {
+		if r == prev {
+			run++
+		} else {
+			prev, run = r, 1
+		}
+		if run >= 3 {
+			return true
+		}
+	}
+	return false
+}
+
+// squeeze - Слово без повторов подряд идущих букв.
+func squeeze(word string) string {
+	var b strings.Builder
+	var prev rune = -1
+	for _, r := range word {
+		if r != prev {
+			b.WriteRune(r)
+		}
+		prev = r
+	}
+	return b.String()
+}
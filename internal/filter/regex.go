@@ -0,0 +1,32 @@
+package filter
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// regex - Собственные правила из настроек. Найденное при rewrite закрывается звёздочками.
+type regex struct {
+	patterns []*regexp.Regexp
+}
+
+func (r regex) Name() string {
+	return "regex"
+}
+
+func (r regex) Find(text string) []Match {
+	var found []Match
+	for _, re := range r.patterns {
+		for _, loc := range re.FindAllStringIndex(text, -1) {
+			if loc[0] < loc[1] {
+				found = append(found, Match{loc[0], loc[1]})
+			}
+		}
+	}
+	return merge(found)
+}
+
+func (r regex) Replace(fragment string) string {
+	return strings.Repeat("*", utf8.RuneCountInString(fragment))
+}
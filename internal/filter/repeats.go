@@ -0,0 +1,45 @@
+package filter
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// repeats - Один символ больше max раз подряд: "Нееееет!!!!!". Цифры и пробелы не считаются:
+// 1000000 - обычное число, а лишние пробелы и так схлопываются. Звёздочки тоже: ими другие
+// фильтры закрывают найденное.
+type repeats struct {
+	max int
+}
+
+func (r repeats) Name() string {
+	return "repeats"
+}
+
+func (r repeats) Find(text string) []Match {
+	var out []Match
+	start, run := 0, 0
+	var prev rune = -1
+	flush := func(end int) {
+		if run > r.max && !unicode.IsDigit(prev) && !unicode.IsSpace(prev) && prev != '*' {
+			out = append(out, Match{start, end})
+		}
+	}
+	for i, c := range text {
+		if unicode.ToLower(c) == unicode.ToLower(prev) {
+			run++
+			continue
+		}
+		flush(i)
+		start, run, prev = i, 1, c
+	}
+	flush(len(text))
+	return out
+}
+
+// Replace - Повтор укорачивается до max символов.
+func (r repeats) Replace(fragment string) string {
+	first, _ := utf8.DecodeRuneInString(fragment)
+	return strings.Repeat(string(first), r.max)
+}
@@ -0,0 +1,96 @@
+package filter
+
+import (
+	"regexp"
+	"sort"
+	"unicode"
+	"unicode/utf8"
+)
+
+// spamPatterns - Ссылки и контакты, которыми цитаты превращают в рекламу.
+var spamPatterns = []*regexp.Regexp{
+	// Ссылки со схемой или www.
+	regexp.MustCompile(`(?i)(?:https?://|www\.)\S+`),
+	// Адреса электронной почты.
+	regexp.MustCompile(`[\p{L}\d._%+-]+@[\p{L}\d-]+(?:\.[\p{L}\d-]+)+`),
+	// Домены без схемы: example.com, пример.рф, t.me/канал.
+	regexp.MustCompile(`(?i)[\p{L}\d-]+(?:\.[\p{L}\d-]+)*\.(?:com|net|org|info|biz|io|me|ru|su|рф|ua|by|kz|xyz|top|club|online|site|shop|store|ly)(?:/\S*)?`),
+	// Ники мессенджеров: @channel.
+	regexp.MustCompile(`@[A-Za-z\d_]{4,}`),
+}
+
+// phonePattern - Телефоны: цифры с пробелами, скобками и дефисами. Годы и числа в тексте короче,
+// поэтому телефоном считается только фрагмент из 10 и больше цифр.
+var phonePattern = regexp.MustCompile(`\+?\d[\d ()-]{8,}\d`)
+
+// spam - Ссылки, адреса почты, ники и телефоны.
+type spam struct{}
+
+func (spam) Name() string {
+	return "spam"
+}
+
+func (spam) Find(text string) []Match {
+	var found []Match
+	for _, re := range spamPatterns {
+		for _, loc := range re.FindAllStringIndex(text, -1) {
+			if m := (Match{loc[0], loc[1]}); boundary(text, m) {
+				found = append(found, m)
+			}
+		}
+	}
+	for _, loc := range phonePattern.FindAllStringIndex(text, -1) {
+		if m := (Match{loc[0], loc[1]}); boundary(text, m) && digits(text[m.Start:m.End]) >= 10 {
+			found = append(found, m)
+		}
+	}
+	return merge(found)
+}
+
+// Replace - Ссылки и контакты удаляются целиком.
+func (spam) Replace(string) string {
+	return ""
+}
+
+// boundary - Фрагмент не начинается и не заканчивается посреди слова: "т.е" внутри "т.ебе" - не домен.
+func boundary(text string, m Match) bool {
+	if before, _ := utf8.DecodeLastRuneInString(text[:m.Start]); m.Start > 0 && isWordChar(before) {
+		return false
+	}
+	if after, _ := utf8.DecodeRuneInString(text[m.End:]); m.End < len(text) && isWordChar(after) {
+		return false
+	}
+	return true
+}
+
+func isWordChar(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func digits(s string) int {
+	n := 0
+	for _, r := range s {
+		if unicode.IsDigit(r) {
+			n++
+		}
+	}
+	return n
+}
+
+// merge - Фрагменты по возрастанию, пересекающиеся объединены.
+func merge(found []Match) []Match {
+	if len(found) == 0 {
+		return nil
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].Start < found[j].Start })
+	out := []Match{found[0]}
+	for _, m := range found[1:] {
+		last := &out[len(out)-1]
+		if m.Start <= last.End {
+			last.End = max(last.End, m.End)
+			continue
+		}
+		out = append(out, m)
+	}
+	return out
+}
@@ -0,0 +1,30 @@
+package health
+
+import (
+	"context"
+	"fmt"
+)
+
+// QueueCheck - Проваливается, когда очередь заполнена на max элементов и больше.
+func QueueCheck(depth func() int, max int) CheckFunc {
+	return func(ctx context.Context) error {
+		if d := depth(); d >= max {
+			return fmt.Errorf("в очереди %d элементов, допустимо не более %d", d, max-1)
+		}
+		return nil
+	}
+}
+
+// DiskCheck - Проваливается, когда на разделе с каталогом path свободно меньше minFree байт.
+func DiskCheck(path string, minFree uint64) CheckFunc {
+	return func(ctx context.Context) error {
+		free, err := freeSpace(path)
+		if err != nil {
+			return err
+		}
+		if free < minFree {
+			return fmt.Errorf("на разделе %s свободно %d МБ, нужно не меньше %d МБ", path, free>>20, minFree>>20)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !linux && !darwin && !freebsd
+
+package health
+
+import "errors"
+
+func freeSpace(path string) (uint64, error) {
+	return 0, errors.New("проверка свободного места не поддерживается на этой платформе")
+}
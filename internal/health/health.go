@@ -0,0 +1,133 @@
+package health
+
+import (
+	"context"
+	"go-offline-test/internal/shared/dto"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusOK       = "ok"
+	StatusFail     = "fail"
+	StatusReady    = "ready"
+	StatusNotReady = "not_ready"
+
+	// checkTimeout - Сколько ждать одну проверку, прежде чем считать её проваленной.
+	checkTimeout = 2 * time.Second
+)
+
+// CheckFunc - Проверка зависимости. nil означает, что зависимость в порядке.
+type CheckFunc func(ctx context.Context) error
+
+type check struct {
+	name string
+	fn   CheckFunc
+}
+
+// Health - Состояние готовности сервиса: выключение, восстановление хранилищ и проверки зависимостей.
+type Health struct {
+	checks    []check
+	replaying map[string]bool
+	draining  atomic.Bool
+	mu        sync.RWMutex
+}
+
+func New() *Health {
+	return &Health{replaying: make(map[string]bool)}
+}
+
+// Register - Добавляет проверку, которая выполняется на каждый запрос /readyz.
+func (h *Health) Register(name string, fn CheckFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.checks = append(h.checks, check{name: name, fn: fn})
+}
+
+// SetDraining - Переводит сервис в режим выключения: новые запросы от балансировщика больше не нужны.
+func (h *Health) SetDraining(draining bool) {
+	h.draining.Store(draining)
+}
+
+// BeginReplay - Помечает хранилище как восстанавливающее данные. Возвращает функцию завершения.
+func (h *Health) BeginReplay(name string) func() {
+	h.mu.Lock()
+	h.replaying[name] = true
+	h.mu.Unlock()
+
+	return func() {
+		h.mu.Lock()
+		delete(h.replaying, name)
+		h.mu.Unlock()
+	}
+}
+
+// Ready - Выполняет все проверки параллельно и собирает итоговое состояние.
+func (h *Health) Ready(ctx context.Context) *dto.Health {
+	h.mu.RLock()
+	checks := make([]check, len(h.checks))
+	copy(checks, h.checks)
+	replaying := make([]string, 0, len(h.replaying))
+	for name := range h.replaying {
+		replaying = append(replaying, name)
+	}
+	h.mu.RUnlock()
+	sort.Strings(replaying)
+
+	results := make([]*dto.HealthCheck, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = run(ctx, c)
+		}()
+	}
+	wg.Wait()
+
+	resp := &dto.Health{Status: StatusReady, Replaying: replaying, Checks: results}
+	switch {
+	case h.draining.Load():
+		resp.Status, resp.Reason = StatusNotReady, "draining"
+	case len(replaying) > 0:
+		resp.Status, resp.Reason = StatusNotReady, "replaying"
+	default:
+		for _, result := range results {
+			if result.Status != StatusOK {
+				resp.Status, resp.Reason = StatusNotReady, "failed_checks"
+				break
+			}
+		}
+	}
+	return resp
+}
+
+func run(ctx context.Context, c check) *dto.HealthCheck {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	start := time.Now()
+	errCh := make(chan error, 1)
+	go func() { errCh <- c.fn(ctx) }()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result := &dto.HealthCheck{
+		Name:      c.name,
+		Status:    StatusOK,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,103 @@
+package i18n
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Localizer - Ошибка, сообщение которой можно перевести на язык клиента.
+type Localizer interface {
+	error
+	Localize(langs []Lang) string
+}
+
+// Error - Ошибка с ключом каталога. Error() отдаёт текст на языке по умолчанию, для логов.
+type Error struct {
+	Key  string
+	Args []any
+	// cause - Первая ошибка среди аргументов, доступна через errors.Is и errors.As.
+	cause error
+}
+
+// sentinels - Все ошибки-образцы, созданные NewError.
+var sentinels struct {
+	mu   sync.Mutex
+	list []*Error
+}
+
+// NewError - Ошибка-образец для сравнения через errors.Is. Аргументы подставляются через With.
+// Образец попадает в Sentinels.
+func NewError(key string) *Error {
+	e := &Error{Key: key}
+	sentinels.mu.Lock()
+	sentinels.list = append(sentinels.list, e)
+	sentinels.mu.Unlock()
+	return e
+}
+
+// Sentinels - Ошибки-образцы из NewError во всех загруженных пакетах, например для проверки,
+// что у каждой есть перевод.
+func Sentinels() []*Error {
+	sentinels.mu.Lock()
+	defer sentinels.mu.Unlock()
+	return slices.Clone(sentinels.list)
+}
+
+// Errorf - Ошибка с параметрами сообщения. Ошибка среди аргументов становится причиной, как %w в fmt.Errorf.
+func Errorf(key string, args ...any) *Error {
+	e := &Error{Key: key, Args: args}
+	for _, arg := range args {
+		if err, ok := arg.(error); ok {
+			e.cause = err
+			break
+		}
+	}
+	return e
+}
+
+// With - Та же ошибка с параметрами сообщения. errors.Is с образцом остаётся истинным.
+func (e *Error) With(args ...any) *Error {
+	return Errorf(e.Key, args...)
+}
+
+func (e *Error) Error() string {
+	return T(nil, e.Key, e.Args...)
+}
+
+// Localize - Сообщение на языке клиента. Ошибки среди параметров переводятся на тот же язык.
+func (e *Error) Localize(langs []Lang) string {
+	args := make([]any, len(e.Args))
+	for i, arg := range e.Args {
+		if err, ok := arg.(error); ok {
+			arg = Localize(err, langs)
+		}
+		args[i] = arg
+	}
+	return T(langs, e.Key, args...)
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Is - Ошибки с одним ключом равны, параметры не сравниваются.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Key == e.Key
+}
+
+// Localize - Сообщение ошибки на языке клиента. Обёртки вида fmt.Errorf("%w: подробности") переводят
+// начало сообщения, подробности остаются как есть. Ошибки без перевода отдаются текстом Error().
+func Localize(err error, langs []Lang) string {
+	text := err.Error()
+	var l Localizer
+	if !errors.As(err, &l) {
+		return text
+	}
+	if rest, ok := strings.CutPrefix(text, l.Error()); ok {
+		return l.Localize(langs) + rest
+	}
+	return text
+}
@@ -0,0 +1,108 @@
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Lang - Язык сообщений, базовая часть тега из Accept-Language (en-US -> en).
+type Lang string
+
+const (
+	RU Lang = "ru"
+	EN Lang = "en"
+
+	// Default - Язык, на который переводится всё, что не нашлось в выбранных клиентом языках.
+	// Им же пишутся логи.
+	Default = RU
+)
+
+// catalogs - Сообщения по языкам. Ключи во всех каталогах одинаковые.
+var catalogs = map[Lang]map[string]string{
+	RU: messagesRU,
+	EN: messagesEN,
+}
+
+// Languages - Поддерживаемые языки, язык по умолчанию первым.
+func Languages() []Lang {
+	langs := []Lang{Default}
+	for lang := range catalogs {
+		if lang != Default {
+			langs = append(langs, lang)
+		}
+	}
+	sort.Slice(langs[1:], func(i, j int) bool { return langs[i+1] < langs[j+1] })
+	return langs
+}
+
+// Keys - Ключи каталога языка по алфавиту.
+func Keys(lang Lang) []string {
+	keys := make([]string, 0, len(catalogs[lang]))
+	for key := range catalogs[lang] {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Has - Есть ли перевод ключа на язык.
+func Has(lang Lang, key string) bool {
+	_, ok := catalogs[lang][key]
+	return ok
+}
+
+// T - Сообщение по ключу на первом языке цепочки, где есть перевод, затем на языке по умолчанию.
+// Ключ без перевода возвращается как есть, чтобы пропуск в каталоге был заметен, но не ломал ответ.
+func T(langs []Lang, key string, args ...any) string {
+	for _, lang := range append(langs, Default) {
+		if format, ok := catalogs[lang][key]; ok {
+			if len(args) == 0 {
+				return format
+			}
+			return fmt.Sprintf(format, args...)
+		}
+	}
+	return key
+}
+
+// Negotiate - Цепочка языков из Accept-Language по убыванию q. Неподдерживаемые языки и q=0 пропускаются,
+// язык по умолчанию всегда замыкает цепочку.
+func Negotiate(header string) []Lang {
+	type weighted struct {
+		lang Lang
+		q    float64
+	}
+	var ranges []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		base, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if _, ok := catalogs[Lang(base)]; !ok {
+			continue
+		}
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(name, "q") {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q > 0 {
+			ranges = append(ranges, weighted{Lang(base), q})
+		}
+	}
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].q > ranges[j].q })
+
+	langs := make([]Lang, 0, len(ranges)+1)
+	seen := make(map[Lang]bool)
+	for _, r := range append(ranges, weighted{lang: Default}) {
+		if !seen[r.lang] {
+			seen[r.lang] = true
+			langs = append(langs, r.lang)
+		}
+	}
+	return langs
+}
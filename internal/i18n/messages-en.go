@@ -0,0 +1,127 @@
+package i18n
+
+var messagesEN = map[string]string{
+	"code.VALIDATION_FAILED":        "Validation failed",
+	"code.MALFORMED_BODY":           "Malformed request body",
+	"code.UNAUTHORIZED":             "Authentication required",
+	"code.FORBIDDEN":                "Access denied",
+	"code.QUOTA_EXCEEDED":           "Quota exceeded",
+	"code.QUOTE_NOT_FOUND":          "Quote not found",
+	"code.NO_QUOTES":                "No quotes",
+	"code.AUTHOR_NOT_FOUND":         "Author not found",
+	"code.AUTHOR_HAS_NO_QUOTES":     "Author has no quotes",
+	"code.TENANT_NOT_FOUND":         "Tenant not found",
+	"code.KEY_NOT_FOUND":            "Key not found",
+	"code.NOT_ACCEPTABLE":           "Response format not supported",
+	"code.QUOTE_ALREADY_EXISTS":     "Quote already exists",
+	"code.TENANT_ALREADY_EXISTS":    "Tenant already exists",
+	"code.DEFAULT_TENANT_PROTECTED": "Default tenant is protected",
+	"code.KEY_REVOKED":              "Key revoked",
+	"code.QUOTE_NOT_PENDING":        "Quote is not pending",
+	"code.AUTHOR_AMBIGUOUS":         "Ambiguous author",
+	"code.AUTHOR_ALIAS_TAKEN":       "Alias taken",
+	"code.PAYLOAD_TOO_LARGE":        "Payload too large",
+	"code.UNSUPPORTED_MEDIA_TYPE":   "Body format not supported",
+	"code.CONTENT_REJECTED":         "Content rejected",
+	"code.CONFIG_REJECTED":          "Configuration rejected",
+	"code.INTERNAL_ERROR":           "Internal error",
+
+	"quotes.none":                   "there are no quotes in the store",
+	"quote.not_found":               "quote not found",
+	"quote.exists":                  "quote already exists",
+	"quote.not_pending":             "quote is not awaiting moderation",
+	"quote.add_failed":              "failed to create quote",
+	"quote.get_failed":              "failed to get quote",
+	"quotes.list_failed":            "failed to list quotes",
+	"quotes.by_author_failed":       "failed to get quotes by author",
+	"author.not_found":              "author not found",
+	"author.no_quotes":              "no quotes found for this author",
+	"author.ambiguous":              "several authors are named %q (ids: %s), specify the author by id",
+	"author.alias_taken":            "alias %q already belongs to author %d",
+	"author.merge_target_not_found": "author %d to merge into not found",
+	"tenant.not_found":              "tenant not found",
+	"tenant.exists":                 "tenant already exists",
+	"tenant.default_delete":         "the default tenant cannot be deleted",
+	"tenant.invalid_name":           "tenant name must consist of lowercase latin letters, digits and hyphens (up to 63 characters)",
+	"tenant.negative_quota":         "quota cannot be negative: %+v",
+	"quota.exceeded":                "tenant quota exceeded",
+	"config.rejected":               "new configuration rejected, the previous one remains in effect",
+
+	"validation.quote_empty":            "quote cannot be empty",
+	"validation.quote_too_short":        "quote is too short (minimum %d characters)",
+	"validation.quote_too_long":         "quote is too long (maximum %d characters)",
+	"validation.quote_invalid_char":     "quote contains invalid character '%c'",
+	"validation.quote_edge":             "quote cannot start or end with '%c'",
+	"validation.quote_pattern":          "quote does not match pattern %s",
+	"validation.quote_forbidden_words":  "quote contains forbidden words: %s",
+	"validation.author_empty":           "author name cannot be empty",
+	"validation.author_too_short":       "author name is too short (minimum %d characters)",
+	"validation.author_too_long":        "author name is too long (maximum %d characters)",
+	"validation.author_invalid_char":    "author name contains invalid character '%c'",
+	"validation.author_edge":            "author name cannot start or end with '%c'",
+	"validation.author_pattern":         "author name does not match pattern %s",
+	"validation.author_forbidden_words": "author name contains forbidden words: %s",
+
+	"audit.limit_range":       "limit must be between 1 and 1000",
+	"audit.since_after_until": "since must be earlier than until",
+	"audit.invalid_int":       "invalid %s: %q",
+	"audit.invalid_time":      "invalid %s: expected RFC3339, got %q",
+
+	"moderation.reason_empty":    "rejection reason is required",
+	"moderation.reason_too_long": "rejection reason is too long (maximum %d characters)",
+
+	"filter.quote_profanity":  "quote contains profanity",
+	"filter.quote_spam":       "quote contains links or contact details",
+	"filter.quote_repeats":    "quote has too many repeated characters in a row",
+	"filter.quote_regex":      "quote did not pass the content filter",
+	"filter.author_profanity": "author name contains profanity",
+	"filter.author_spam":      "author name contains links or contact details",
+	"filter.author_repeats":   "author name has too many repeated characters in a row",
+	"filter.author_regex":     "author name did not pass the content filter",
+
+	"auth.missing_token":       "authorization token is missing",
+	"auth.invalid_token":       "invalid authorization token",
+	"auth.key_not_found":       "key not found",
+	"auth.key_revoked":         "key revoked",
+	"auth.key_expired":         "key expired",
+	"auth.forbidden":           "insufficient permissions",
+	"auth.scope_required":      "%v: %s is required",
+	"auth.unknown_scope":       "unknown access scope",
+	"auth.no_scopes":           "no access scopes specified",
+	"auth.empty_key_name":      "key name cannot be empty",
+	"auth.invalid_expiry":      "key expiry must be in the future",
+	"auth.token_expired":       "token expired",
+	"auth.token_not_yet_valid": "token is not valid yet",
+	"auth.invalid_issuer":      "token issued by an unknown issuer",
+	"auth.invalid_audience":    "token issued for another service",
+
+	"request.body_required":          "request body is required",
+	"request.body_malformed":         "invalid request body: %v",
+	"request.body_too_large":         "request body exceeds %d bytes",
+	"request.media_type_unsupported": "body format %q is not supported, available: %s",
+	"request.media_type_rejected":    "body in %s format is not accepted here",
+	"request.not_acceptable":         "response format %q is not supported, available: %s",
+	"request.quote_id_required":      "quote ID is required",
+	"request.quote_id_invalid":       "invalid quote ID",
+	"request.author_id_invalid":      "invalid author ID",
+	"request.tenant_mismatch":        "tenant in path (%s) does not match header %s (%s)",
+	"request.tenant_invalid":         "invalid tenant name %q",
+	"author.field_too_long":          "value is longer than %d characters",
+	"author.year_out_of_range":       "year must be between %d and %d",
+	"author.death_before_birth":      "death year is before birth year",
+	"author.too_many_links":          "no more than %d links allowed",
+	"author.too_many_aliases":        "no more than %d aliases allowed",
+	"author.name_required":           "author name is required",
+	"author.merge_self":              "an author cannot be merged into itself",
+	"author.merge_target_required":   "specify the id of the author to merge into",
+	"author.link_invalid":            "link must be an absolute http or https URL",
+	"request.expand_invalid":         "unknown expand value %q, allowed: %s",
+	"request.style_invalid":          "unknown citation style %q, allowed: %s",
+	"source.type_invalid":            "unknown source type %q, allowed: %s",
+	"source.title_required":          "source title is required",
+	"source.field_too_long":          "value is longer than %d characters",
+	"source.year_out_of_range":       "year must be between %d and %d",
+	"source.pages_invalid":           "expected a page or a page range such as 42 or 42-45, at most %d characters",
+	"source.url_invalid":             "source URL must be an absolute http or https URL",
+	"source.url_required":            "a web source must have a URL",
+}
@@ -0,0 +1,127 @@
+package i18n
+
+var messagesRU = map[string]string{
+	"code.VALIDATION_FAILED":        "Данные не прошли проверку",
+	"code.MALFORMED_BODY":           "Некорректное тело запроса",
+	"code.UNAUTHORIZED":             "Требуется авторизация",
+	"code.FORBIDDEN":                "Доступ запрещён",
+	"code.QUOTA_EXCEEDED":           "Превышена квота",
+	"code.QUOTE_NOT_FOUND":          "Цитата не найдена",
+	"code.NO_QUOTES":                "Цитат нет",
+	"code.AUTHOR_NOT_FOUND":         "Автор не найден",
+	"code.AUTHOR_HAS_NO_QUOTES":     "У автора нет цитат",
+	"code.TENANT_NOT_FOUND":         "Тенант не найден",
+	"code.KEY_NOT_FOUND":            "Ключ не найден",
+	"code.NOT_ACCEPTABLE":           "Формат ответа не поддерживается",
+	"code.QUOTE_ALREADY_EXISTS":     "Цитата уже существует",
+	"code.TENANT_ALREADY_EXISTS":    "Тенант уже существует",
+	"code.DEFAULT_TENANT_PROTECTED": "Тенант по умолчанию защищён",
+	"code.KEY_REVOKED":              "Ключ отозван",
+	"code.QUOTE_NOT_PENDING":        "Цитата не на модерации",
+	"code.AUTHOR_AMBIGUOUS":         "Автор указан неоднозначно",
+	"code.AUTHOR_ALIAS_TAKEN":       "Псевдоним занят",
+	"code.PAYLOAD_TOO_LARGE":        "Слишком большой запрос",
+	"code.UNSUPPORTED_MEDIA_TYPE":   "Формат тела не поддерживается",
+	"code.CONTENT_REJECTED":         "Содержимое отклонено",
+	"code.CONFIG_REJECTED":          "Настройки отклонены",
+	"code.INTERNAL_ERROR":           "Внутренняя ошибка",
+
+	"quotes.none":                   "в хранилище нет доступных цитат",
+	"quote.not_found":               "цитата не найдена",
+	"quote.exists":                  "цитата уже существует",
+	"quote.not_pending":             "цитата не ждёт модерации",
+	"quote.add_failed":              "ошибка создания цитаты",
+	"quote.get_failed":              "ошибка получения цитаты",
+	"quotes.list_failed":            "ошибка получения списка цитат",
+	"quotes.by_author_failed":       "ошибка получения цитаты по автору",
+	"author.not_found":              "автор не найден",
+	"author.no_quotes":              "цитаты этого автора не найдены",
+	"author.ambiguous":              "имя %q носят несколько авторов (id: %s), укажите автора по id",
+	"author.alias_taken":            "псевдоним %q уже относится к автору с id %d",
+	"author.merge_target_not_found": "автор с id %d, к которому переносятся цитаты, не найден",
+	"tenant.not_found":              "тенант не найден",
+	"tenant.exists":                 "тенант уже существует",
+	"tenant.default_delete":         "тенант по умолчанию нельзя удалить",
+	"tenant.invalid_name":           "имя тенанта должно состоять из латинских букв в нижнем регистре, цифр и дефисов (до 63 символов)",
+	"tenant.negative_quota":         "квота не может быть отрицательной: %+v",
+	"quota.exceeded":                "превышена квота тенанта",
+	"config.rejected":               "новые настройки отклонены, действуют прежние",
+
+	"validation.quote_empty":            "цитата не может быть пустой",
+	"validation.quote_too_short":        "цитата слишком короткая (минимум %d символов)",
+	"validation.quote_too_long":         "цитата слишком длинная (максимум %d символов)",
+	"validation.quote_invalid_char":     "цитата содержит недопустимый символ '%c'",
+	"validation.quote_edge":             "цитата не может начинаться или заканчиваться символом '%c'",
+	"validation.quote_pattern":          "цитата не соответствует шаблону %s",
+	"validation.quote_forbidden_words":  "цитата содержит запрещённые слова: %s",
+	"validation.author_empty":           "имя автора не может быть пустым",
+	"validation.author_too_short":       "имя автора слишком короткое (минимум %d символов)",
+	"validation.author_too_long":        "имя автора слишком длинное (максимум %d символов)",
+	"validation.author_invalid_char":    "имя автора содержит недопустимый символ '%c'",
+	"validation.author_edge":            "имя автора не может начинаться или заканчиваться символом '%c'",
+	"validation.author_pattern":         "имя автора не соответствует шаблону %s",
+	"validation.author_forbidden_words": "имя автора содержит запрещённые слова: %s",
+
+	"audit.limit_range":       "limit должен быть от 1 до 1000",
+	"audit.since_after_until": "since должен быть раньше until",
+	"audit.invalid_int":       "некорректное значение %s: %q",
+	"audit.invalid_time":      "некорректное значение %s: ожидается RFC3339, получено %q",
+
+	"moderation.reason_empty":    "укажите причину отклонения",
+	"moderation.reason_too_long": "причина отклонения слишком длинная (максимум %d символов)",
+
+	"filter.quote_profanity":  "цитата содержит нецензурную лексику",
+	"filter.quote_spam":       "цитата содержит ссылки или контакты",
+	"filter.quote_repeats":    "в цитате слишком много повторяющихся символов подряд",
+	"filter.quote_regex":      "цитата не прошла фильтр содержимого",
+	"filter.author_profanity": "имя автора содержит нецензурную лексику",
+	"filter.author_spam":      "имя автора содержит ссылки или контакты",
+	"filter.author_repeats":   "в имени автора слишком много повторяющихся символов подряд",
+	"filter.author_regex":     "имя автора не прошло фильтр содержимого",
+
+	"auth.missing_token":       "не передан токен авторизации",
+	"auth.invalid_token":       "недействительный токен авторизации",
+	"auth.key_not_found":       "ключ не найден",
+	"auth.key_revoked":         "ключ отозван",
+	"auth.key_expired":         "срок действия ключа истёк",
+	"auth.forbidden":           "недостаточно прав",
+	"auth.scope_required":      "%v: требуется %s",
+	"auth.unknown_scope":       "неизвестное право доступа",
+	"auth.no_scopes":           "не указано ни одного права доступа",
+	"auth.empty_key_name":      "имя ключа не может быть пустым",
+	"auth.invalid_expiry":      "дата истечения ключа должна быть в будущем",
+	"auth.token_expired":       "срок действия токена истёк",
+	"auth.token_not_yet_valid": "токен ещё не действителен",
+	"auth.invalid_issuer":      "токен выпущен неизвестным издателем",
+	"auth.invalid_audience":    "токен выпущен для другого сервиса",
+
+	"request.body_required":          "тело запроса обязательно",
+	"request.body_malformed":         "некорректное тело запроса: %v",
+	"request.body_too_large":         "тело запроса больше %d байт",
+	"request.media_type_unsupported": "формат тела %q не поддерживается, доступны: %s",
+	"request.media_type_rejected":    "тело в формате %s здесь не принимается",
+	"request.not_acceptable":         "формат ответа %q не поддерживается, доступны: %s",
+	"request.quote_id_required":      "не указан id цитаты",
+	"request.quote_id_invalid":       "некорректный id цитаты",
+	"request.author_id_invalid":      "некорректный id автора",
+	"request.tenant_mismatch":        "тенант в пути (%s) не совпадает с заголовком %s (%s)",
+	"request.tenant_invalid":         "некорректное имя тенанта %q",
+	"author.field_too_long":          "значение длиннее %d символов",
+	"author.year_out_of_range":       "год должен быть в диапазоне от %d до %d",
+	"author.death_before_birth":      "год смерти раньше года рождения",
+	"author.too_many_links":          "не больше %d ссылок",
+	"author.too_many_aliases":        "не больше %d псевдонимов",
+	"author.name_required":           "имя автора обязательно",
+	"author.merge_self":              "автора нельзя объединить с самим собой",
+	"author.merge_target_required":   "укажите id автора, к которому переносятся цитаты",
+	"author.link_invalid":            "ссылка должна быть абсолютным адресом http или https",
+	"request.expand_invalid":         "неизвестное значение expand %q, допустимо: %s",
+	"request.style_invalid":          "неизвестный стиль ссылки %q, допустимы: %s",
+	"source.type_invalid":            "неизвестный вид источника %q, допустимы: %s",
+	"source.title_required":          "название источника обязательно",
+	"source.field_too_long":          "значение длиннее %d символов",
+	"source.year_out_of_range":       "год должен быть в диапазоне от %d до %d",
+	"source.pages_invalid":           "страница или диапазон страниц, например 42 или 42-45, не длиннее %d символов",
+	"source.url_invalid":             "адрес источника должен быть абсолютным адресом http или https",
+	"source.url_required":            "у веб-источника должен быть адрес",
+}
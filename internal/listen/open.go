@@ -0,0 +1,167 @@
+package listen
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// listenFDsStart - Первый дескриптор, который передаёт systemd (SD_LISTEN_FDS_START).
+const listenFDsStart = 3
+
+// Open - Открывает слушатель по описанию.
+func Open(spec *Spec) (net.Listener, error) {
+	switch spec.Network {
+	case NetworkTCP:
+		return net.Listen("tcp", spec.Address)
+	case NetworkUnix:
+		return openUnix(spec)
+	case NetworkSystemd:
+		return openSystemd(spec.Address)
+	default:
+		return nil, fmt.Errorf("неизвестная схема %q", spec.Network)
+	}
+}
+
+func openUnix(spec *Spec) (net.Listener, error) {
+	// Сокет, оставшийся от прошлого запуска, мешает bind. Удаляем только сокеты, чтобы не стереть чужой файл.
+	if info, err := os.Lstat(spec.Address); err == nil && info.Mode()&os.ModeSocket != 0 {
+		if err := os.Remove(spec.Address); err != nil {
+			return nil, err
+		}
+	}
+
+	// bind создаёт сокет с правами по umask процесса, и до chmod к нему мог бы подключиться кто угодно.
+	// Поэтому сокет создаётся в закрытом (0700) каталоге рядом с целевым путём и переносится на место
+	// уже с нужными правами и группой. Менять сам umask нельзя: он общий для всех горутин процесса.
+	dir, err := os.MkdirTemp(filepath.Dir(spec.Address), ".sock-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	tmp := filepath.Join(dir, "s")
+	ln, err := net.ListenUnix("unix", &net.UnixAddr{Name: tmp, Net: "unix"})
+	if err != nil {
+		return nil, err
+	}
+	// Сокет удаляется по итоговому пути в unixListener.Close.
+	ln.SetUnlinkOnClose(false)
+	if err := setSocketPermissions(tmp, spec); err != nil {
+		ln.Close()
+		return nil, err
+	}
+	// Link, а не Rename: занятый путь - ошибка, как и у bind, а не молча затёртый файл.
+	if err := os.Link(tmp, spec.Address); err != nil {
+		ln.Close()
+		return nil, err
+	}
+	return &unixListener{UnixListener: ln, path: spec.Address}, nil
+}
+
+// unixListener - Слушатель сокета, перенесённого из временного каталога: адрес и удаление при закрытии -
+// по итоговому пути.
+type unixListener struct {
+	*net.UnixListener
+	path   string
+	unlink sync.Once
+}
+
+func (l *unixListener) Addr() net.Addr {
+	return &net.UnixAddr{Name: l.path, Net: "unix"}
+}
+
+func (l *unixListener) Close() error {
+	err := l.UnixListener.Close()
+	l.unlink.Do(func() {
+		if rmErr := os.Remove(l.path); rmErr != nil && !errors.Is(rmErr, os.ErrNotExist) && err == nil {
+			err = rmErr
+		}
+	})
+	return err
+}
+
+func setSocketPermissions(path string, spec *Spec) error {
+	if spec.Mode != 0 {
+		if err := os.Chmod(path, spec.Mode); err != nil {
+			return err
+		}
+	}
+	if spec.Group != "" {
+		group, err := user.LookupGroup(spec.Group)
+		if err != nil {
+			return err
+		}
+		gid, err := strconv.Atoi(group.Gid)
+		if err != nil {
+			return err
+		}
+		if err := os.Chown(path, -1, gid); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var (
+	activatedOnce sync.Once
+	activated     []*os.File
+	activatedErr  error
+)
+
+// activatedFiles - Сокеты, переданные systemd через LISTEN_FDS. Переменные окружения снимаются,
+// чтобы дочерние процессы не приняли сокеты за свои.
+func activatedFiles() ([]*os.File, error) {
+	activatedOnce.Do(func() {
+		defer func() {
+			os.Unsetenv("LISTEN_PID")
+			os.Unsetenv("LISTEN_FDS")
+			os.Unsetenv("LISTEN_FDNAMES")
+		}()
+
+		pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+		if err != nil || pid != os.Getpid() {
+			activatedErr = errors.New("сокеты systemd не переданы этому процессу (LISTEN_PID)")
+			return
+		}
+		count, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+		if err != nil || count <= 0 {
+			activatedErr = errors.New("systemd не передал ни одного сокета (LISTEN_FDS)")
+			return
+		}
+		names := strings.Split(os.Getenv("LISTEN_FDNAMES"), ":")
+		for i := 0; i < count; i++ {
+			name := strconv.Itoa(i)
+			if i < len(names) && names[i] != "" {
+				name = names[i]
+			}
+			activated = append(activated, os.NewFile(uintptr(listenFDsStart+i), name))
+		}
+	})
+	return activated, activatedErr
+}
+
+// openSystemd - Берёт сокет по имени из FileDescriptorName или по номеру.
+func openSystemd(address string) (net.Listener, error) {
+	files, err := activatedFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, f := range files {
+		if f.Name() == address || strconv.Itoa(i) == address {
+			ln, err := net.FileListener(f)
+			if err != nil {
+				return nil, fmt.Errorf("сокет systemd %s: %w", address, err)
+			}
+			return ln, nil
+		}
+	}
+	return nil, fmt.Errorf("systemd не передал сокет %s", address)
+}
@@ -0,0 +1,73 @@
+package listen_test
+
+import (
+	"go-offline-test/internal/listen"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestOpenUnix(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("права unix-сокета проверяются только на unix")
+	}
+	dir := t.TempDir()
+	path := filepath.Join(dir, "quotes.sock")
+	spec, err := listen.Parse("unix://" + path + "?mode=0600")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ln, err := listen.Open(spec)
+	if err != nil {
+		t.Fatalf("Open(): %v", err)
+	}
+	info, err := os.Lstat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode()&os.ModeSocket == 0 || info.Mode().Perm() != 0o600 {
+		t.Errorf("режим сокета %v, ожидался сокет с правами 0600", info.Mode())
+	}
+	if got := ln.Addr().String(); got != path {
+		t.Errorf("Addr() = %s, ожидалось %s", got, path)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("в каталоге %d файлов, ожидался только сокет: временный каталог не удалён", len(entries))
+	}
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("Dial(): %v", err)
+	}
+	conn.Close()
+
+	if err := ln.Close(); err != nil {
+		t.Fatalf("Close(): %v", err)
+	}
+	if _, err := os.Lstat(path); !os.IsNotExist(err) {
+		t.Errorf("после Close() сокет остался: %v", err)
+	}
+}
+
+// TestOpenUnixKeepsForeignFile - Обычный файл на месте сокета не затирается.
+func TestOpenUnixKeepsForeignFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quotes.sock")
+	if err := os.WriteFile(path, []byte("данные"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	spec, err := listen.Parse("unix://" + path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ln, err := listen.Open(spec); err == nil {
+		ln.Close()
+		t.Fatal("Open() поверх обычного файла: ожидалась ошибка")
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "данные" {
+		t.Errorf("файл изменён: %q, %v", data, err)
+	}
+}
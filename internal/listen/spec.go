@@ -0,0 +1,171 @@
+package listen
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	NetworkTCP     = "tcp"
+	NetworkUnix    = "unix"
+	NetworkSystemd = "systemd"
+)
+
+// RouteSet - Группы маршрутов, которые обслуживает слушатель.
+type RouteSet uint8
+
+const (
+	// RoutesPublic - Цитаты.
+	RoutesPublic RouteSet = 1 << iota
+	// RoutesAdmin - /admin/* и /audit.
+	RoutesAdmin
+	// RoutesOps - /metrics, /healthz, /readyz.
+	RoutesOps
+
+	RoutesAll = RoutesPublic | RoutesAdmin | RoutesOps
+)
+
+var routeNames = []struct {
+	set  RouteSet
+	name string
+}{
+	{RoutesPublic, "public"},
+	{RoutesAdmin, "admin"},
+	{RoutesOps, "ops"},
+}
+
+// Has - Входит ли группа в набор.
+func (rs RouteSet) Has(set RouteSet) bool {
+	return rs&set != 0
+}
+
+func (rs RouteSet) String() string {
+	var names []string
+	for _, r := range routeNames {
+		if rs.Has(r.set) {
+			names = append(names, r.name)
+		}
+	}
+	return strings.Join(names, "+")
+}
+
+// ParseRouteSet - Разбирает группы через +, например public+ops. all - все группы.
+// В query-строке + превращается в пробел, поэтому он тоже считается разделителем.
+func ParseRouteSet(raw string) (RouteSet, error) {
+	var rs RouteSet
+	for _, name := range strings.FieldsFunc(raw, func(r rune) bool { return r == '+' || r == ' ' }) {
+		if name == "all" {
+			rs |= RoutesAll
+			continue
+		}
+		found := false
+		for _, r := range routeNames {
+			if r.name == name {
+				rs |= r.set
+				found = true
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("неизвестная группа маршрутов %q, ожидается public, admin, ops или all", name)
+		}
+	}
+	return rs, nil
+}
+
+// Spec - Описание слушателя:
+//
+//	tcp://0.0.0.0:8080?routes=public
+//	unix:///run/quotes/quotes.sock?mode=0660&group=quotes&routes=public
+//	systemd://admin?routes=admin+ops
+//
+// Для systemd адрес - имя из FileDescriptorName или номер сокета, начиная с 0.
+type Spec struct {
+	Network string
+	Address string
+	Routes  RouteSet
+	// Mode, Group - Права и группа файла unix-сокета. Нулевой Mode оставляет права по umask.
+	Mode  os.FileMode
+	Group string
+	// TLS - Принимать ли на слушателе HTTPS, если он включён. По умолчанию для unix-сокета выключен.
+	TLS bool
+
+	raw string
+}
+
+func (s *Spec) String() string {
+	return s.raw
+}
+
+// Parse - Разбирает описание слушателя. Маршруты по умолчанию - все.
+func Parse(raw string) (*Spec, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("слушатель %q: %w", raw, err)
+	}
+
+	spec := &Spec{Network: u.Scheme, Routes: RoutesAll, raw: raw}
+	switch u.Scheme {
+	case NetworkTCP, NetworkSystemd:
+		spec.Address = u.Host
+		spec.TLS = true
+	case NetworkUnix:
+		spec.Address = u.Host + u.Path
+	default:
+		return nil, fmt.Errorf("слушатель %q: неизвестная схема %q, ожидается tcp, unix или systemd", raw, u.Scheme)
+	}
+	if spec.Address == "" {
+		return nil, fmt.Errorf("слушатель %q: не указан адрес", raw)
+	}
+
+	query := u.Query()
+	for key := range query {
+		switch key {
+		case "routes", "tls":
+		case "mode", "group":
+			if spec.Network != NetworkUnix {
+				return nil, fmt.Errorf("слушатель %q: параметр %s есть только у unix-сокета", raw, key)
+			}
+		default:
+			return nil, fmt.Errorf("слушатель %q: неизвестный параметр %s", raw, key)
+		}
+	}
+
+	if routes := query.Get("routes"); routes != "" {
+		if spec.Routes, err = ParseRouteSet(routes); err != nil {
+			return nil, fmt.Errorf("слушатель %q: %w", raw, err)
+		}
+	}
+	if tls := query.Get("tls"); tls != "" {
+		if spec.TLS, err = strconv.ParseBool(tls); err != nil {
+			return nil, fmt.Errorf("слушатель %q: tls ожидает true или false", raw)
+		}
+	}
+	if mode := query.Get("mode"); mode != "" {
+		perm, err := strconv.ParseUint(mode, 8, 32)
+		if err != nil || perm > 0o777 {
+			return nil, fmt.Errorf("слушатель %q: mode ожидает права в восьмеричном виде, например 0660", raw)
+		}
+		spec.Mode = os.FileMode(perm)
+	}
+	spec.Group = query.Get("group")
+
+	return spec, nil
+}
+
+// ParseAll - Разбирает все описания и собирает ошибки вместе.
+func ParseAll(raw []string) ([]*Spec, []error) {
+	var specs []*Spec
+	var errs []error
+	for _, r := range raw {
+		spec, err := Parse(r)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		specs = append(specs, spec)
+	}
+	return specs, errs
+}
@@ -0,0 +1,83 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+type contextKey string
+
+const scopeCtxKey contextKey = "logScope"
+
+// scope - Поля запроса, которые добавляются к каждой строке лога, написанной с его контекстом.
+// Хранится по указателю, чтобы статус ответа и исполнитель, ставшие известными позже,
+// попадали и в строки, которые пишут нижние слои.
+type scope struct {
+	start  time.Time
+	attrs  []slog.Attr
+	status int
+	mu     sync.RWMutex
+}
+
+// WithRequest - Открывает область запроса в контексте с начальными полями.
+func WithRequest(ctx context.Context, attrs ...slog.Attr) context.Context {
+	return context.WithValue(ctx, scopeCtxKey, &scope{start: time.Now(), attrs: attrs})
+}
+
+// AddAttrs - Дописывает поля в область запроса, например исполнителя после авторизации.
+func AddAttrs(ctx context.Context, attrs ...slog.Attr) {
+	if s, ok := ctx.Value(scopeCtxKey).(*scope); ok {
+		s.mu.Lock()
+		s.attrs = append(s.attrs, attrs...)
+		s.mu.Unlock()
+	}
+}
+
+// SetStatus - Запоминает HTTP-статус ответа.
+func SetStatus(ctx context.Context, status int) {
+	if s, ok := ctx.Value(scopeCtxKey).(*scope); ok {
+		s.mu.Lock()
+		s.status = status
+		s.mu.Unlock()
+	}
+}
+
+// Elapsed - Время с начала запроса.
+func Elapsed(ctx context.Context) time.Duration {
+	if s, ok := ctx.Value(scopeCtxKey).(*scope); ok {
+		return time.Since(s.start)
+	}
+	return 0
+}
+
+// contextHandler - Обёртка над slog.Handler, добавляющая поля области запроса.
+type contextHandler struct {
+	next slog.Handler
+}
+
+func (h *contextHandler) Enabled(ctx context.Context, lvl slog.Level) bool {
+	return h.next.Enabled(ctx, lvl)
+}
+
+func (h *contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if s, ok := ctx.Value(scopeCtxKey).(*scope); ok {
+		s.mu.RLock()
+		r.AddAttrs(s.attrs...)
+		if s.status != 0 {
+			r.AddAttrs(slog.Int("status", s.status))
+		}
+		s.mu.RUnlock()
+		r.AddAttrs(slog.Float64("elapsed_ms", float64(time.Since(s.start).Microseconds())/1000))
+	}
+	return h.next.Handle(ctx, r)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{next: h.next.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{next: h.next.WithGroup(name)}
+}
@@ -0,0 +1,61 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"sync/atomic"
+)
+
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+var (
+	// level - Текущий уровень логирования, может меняться на лету.
+	level = new(slog.LevelVar)
+	// redactQuotes - Скрывать ли тексты цитат в логах.
+	redactQuotes atomic.Bool
+)
+
+func init() {
+	redactQuotes.Store(true)
+}
+
+// New - Создаёт логгер в формате json или text, который добавляет к каждой строке поля запроса из контекста.
+func New(w io.Writer, format string, lvl slog.Level, redact bool) (*slog.Logger, error) {
+	level.Set(lvl)
+	redactQuotes.Store(redact)
+
+	opts := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	switch format {
+	case FormatJSON:
+		handler = slog.NewJSONHandler(w, opts)
+	case FormatText, "":
+		handler = slog.NewTextHandler(w, opts)
+	default:
+		return nil, fmt.Errorf("неизвестный формат логов %q, ожидается json или text", format)
+	}
+
+	return slog.New(&contextHandler{next: handler}), nil
+}
+
+// SetLevel - Меняет уровень логирования без пересоздания логгера.
+func SetLevel(lvl slog.Level) {
+	level.Set(lvl)
+}
+
+// SetRedactQuotes - Включает или выключает скрытие текстов цитат.
+func SetRedactQuotes(redact bool) {
+	redactQuotes.Store(redact)
+}
+
+// Quote - Атрибут с текстом цитаты. По умолчанию вместо текста пишется только его длина.
+func Quote(key, text string) slog.Attr {
+	if redactQuotes.Load() {
+		return slog.String(key, fmt.Sprintf("[скрыто, %d симв.]", len([]rune(text))))
+	}
+	return slog.String(key, text)
+}
@@ -0,0 +1,106 @@
+package metrics_test
+
+import (
+	"bytes"
+	"go-offline-test/internal/metrics"
+	"math"
+	"testing"
+)
+
+func TestRegistryExposition(t *testing.T) {
+	requests := metrics.NewCounterVec("http_requests_total", "Число запросов.\nПо маршрутам и коду.", "route", "code")
+	requests.Inc("GET /quotes", "200")
+	requests.Inc("GET /quotes", "200")
+	requests.Add(0.5, "DELETE /quotes/{id}", "404")
+	requests.Inc(`a\b"c`+"\n", "500")
+	// Неверное число меток и отрицательный шаг игнорируются.
+	requests.Inc("GET /quotes")
+	requests.Add(-1, "GET /quotes", "200")
+
+	duration := metrics.NewHistogramVec("http_request_duration_seconds", `Время с \ в описании.`, []float64{0.1, 1}, "route")
+	for _, v := range []float64{0.05, 0.1, 0.5, 5} {
+		duration.Observe(v, "GET /quotes")
+	}
+	duration.Observe(1, "POST /quotes")
+	duration.Observe(1, "лишняя", "метка")
+
+	pending := metrics.NewGaugeFunc("quotes_pending", "Цитаты на модерации.", func() []metrics.Sample {
+		return []metrics.Sample{
+			{Values: []string{"default"}, Value: 3},
+			{Values: []string{"без", "пары"}, Value: 1},
+			{Values: []string{"acme"}, Value: math.Inf(1)},
+		}
+	}, "tenant")
+
+	registry := metrics.NewRegistry()
+	registry.Register(requests)
+	registry.Register(duration)
+	registry.Register(pending)
+
+	var buf bytes.Buffer
+	n, err := registry.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("WriteTo(): %v", err)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("WriteTo() = %d, записано %d байт", n, buf.Len())
+	}
+
+	want := `# HELP http_requests_total Число запросов.\nПо маршрутам и коду.
+# TYPE http_requests_total counter
+http_requests_total{route="DELETE /quotes/{id}",code="404"} 0.5
+http_requests_total{route="GET /quotes",code="200"} 2
+http_requests_total{route="a\\b\"c\n",code="500"} 1
+# HELP http_request_duration_seconds Время с \\ в описании.
+# TYPE http_request_duration_seconds histogram
+http_request_duration_seconds_bucket{route="GET /quotes",le="0.1"} 2
+http_request_duration_seconds_bucket{route="GET /quotes",le="1"} 3
+http_request_duration_seconds_bucket{route="GET /quotes",le="+Inf"} 4
+http_request_duration_seconds_sum{route="GET /quotes"} 5.65
+http_request_duration_seconds_count{route="GET /quotes"} 4
+http_request_duration_seconds_bucket{route="POST /quotes",le="0.1"} 0
+http_request_duration_seconds_bucket{route="POST /quotes",le="1"} 1
+http_request_duration_seconds_bucket{route="POST /quotes",le="+Inf"} 1
+http_request_duration_seconds_sum{route="POST /quotes"} 1
+http_request_duration_seconds_count{route="POST /quotes"} 1
+# HELP quotes_pending Цитаты на модерации.
+# TYPE quotes_pending gauge
+quotes_pending{tenant="default"} 3
+quotes_pending{tenant="acme"} +Inf
+`
+	if got := buf.String(); got != want {
+		t.Errorf("WriteTo():\n%s\nожидалось:\n%s", got, want)
+	}
+}
+
+func TestHistogramBucketBounds(t *testing.T) {
+	tests := []struct {
+		name string
+		v    float64
+		want string
+	}{
+		{"ниже первой границы", 0.001, "1 1 1"},
+		{"ровно на границе попадает в неё", 0.005, "1 1 1"},
+		{"между границами", 0.007, "0 1 1"},
+		{"на последней границе", 0.01, "0 1 1"},
+		{"выше всех границ - только +Inf", 3, "0 0 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := metrics.NewHistogramVec("h", "h", []float64{0.005, 0.01})
+			h.Observe(tt.v)
+			var buf bytes.Buffer
+			h.Collect(&buf)
+			var counts []byte
+			for _, line := range bytes.Split(buf.Bytes(), []byte("\n")) {
+				if bytes.HasPrefix(line, []byte("h_bucket")) {
+					counts = append(counts, line[bytes.LastIndexByte(line, ' ')+1:]...)
+					counts = append(counts, ' ')
+				}
+			}
+			if got := string(bytes.TrimSpace(counts)); got != tt.want {
+				t.Errorf("Observe(%v): накопительные счётчики %q, ожидалось %q", tt.v, got, tt.want)
+			}
+		})
+	}
+}
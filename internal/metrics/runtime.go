@@ -0,0 +1,55 @@
+package metrics
+
+import (
+	"io"
+	"runtime"
+	"time"
+)
+
+// RuntimeCollector - Метрики рантайма Go: горутины, куча и паузы сборщика мусора.
+type RuntimeCollector struct {
+	start time.Time
+}
+
+func NewRuntimeCollector() *RuntimeCollector {
+	return &RuntimeCollector{start: time.Now()}
+}
+
+func (rc *RuntimeCollector) Collect(w io.Writer) {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+
+	WriteHeader(w, "go_info", "Версия Go, которой собран сервис.", "gauge")
+	WriteSample(w, "go_info", []string{"version"}, []string{runtime.Version()}, 1)
+
+	WriteHeader(w, "go_goroutines", "Число запущенных горутин.", "gauge")
+	WriteSample(w, "go_goroutines", nil, nil, float64(runtime.NumGoroutine()))
+
+	WriteHeader(w, "go_memstats_heap_alloc_bytes", "Байт занято живыми и ещё не собранными объектами в куче.", "gauge")
+	WriteSample(w, "go_memstats_heap_alloc_bytes", nil, nil, float64(ms.HeapAlloc))
+
+	WriteHeader(w, "go_memstats_heap_inuse_bytes", "Байт в используемых спанах кучи.", "gauge")
+	WriteSample(w, "go_memstats_heap_inuse_bytes", nil, nil, float64(ms.HeapInuse))
+
+	WriteHeader(w, "go_memstats_heap_objects", "Число объектов в куче.", "gauge")
+	WriteSample(w, "go_memstats_heap_objects", nil, nil, float64(ms.HeapObjects))
+
+	WriteHeader(w, "go_memstats_sys_bytes", "Байт памяти, полученных от ОС.", "gauge")
+	WriteSample(w, "go_memstats_sys_bytes", nil, nil, float64(ms.Sys))
+
+	WriteHeader(w, "go_gc_cycles_total", "Число завершённых циклов сборки мусора.", "counter")
+	WriteSample(w, "go_gc_cycles_total", nil, nil, float64(ms.NumGC))
+
+	WriteHeader(w, "go_gc_pause_seconds_total", "Суммарное время пауз сборщика мусора.", "counter")
+	WriteSample(w, "go_gc_pause_seconds_total", nil, nil, float64(ms.PauseTotalNs)/1e9)
+
+	var lastPause float64
+	if ms.NumGC > 0 {
+		lastPause = float64(ms.PauseNs[(ms.NumGC+255)%256]) / 1e9
+	}
+	WriteHeader(w, "go_gc_last_pause_seconds", "Длительность последней паузы сборщика мусора.", "gauge")
+	WriteSample(w, "go_gc_last_pause_seconds", nil, nil, lastPause)
+
+	WriteHeader(w, "process_start_time_seconds", "Время запуска процесса в секундах Unix.", "gauge")
+	WriteSample(w, "process_start_time_seconds", nil, nil, float64(rc.start.UnixNano())/1e9)
+}
@@ -0,0 +1,179 @@
+package metrics
+
+import (
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets - Границы гистограммы задержек в секундах, как в клиентских библиотеках Prometheus.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// labelKey - Ключ серии по значениям меток. Разделитель не может встретиться в UTF-8 тексте.
+func labelKey(values []string) string {
+	return strings.Join(values, "\xff")
+}
+
+// CounterVec - Счётчик с набором меток.
+type CounterVec struct {
+	name   string
+	help   string
+	labels []string
+	series map[string]*counterSeries
+	mu     sync.Mutex
+}
+
+type counterSeries struct {
+	values []string
+	value  float64
+}
+
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	return &CounterVec{name: name, help: help, labels: labels, series: make(map[string]*counterSeries)}
+}
+
+// Inc - Увеличивает счётчик серии на единицу. Значений должно быть столько же, сколько меток.
+func (c *CounterVec) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+func (c *CounterVec) Add(delta float64, values ...string) {
+	if len(values) != len(c.labels) || delta < 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := labelKey(values)
+	s, ok := c.series[key]
+	if !ok {
+		s = &counterSeries{values: append([]string(nil), values...)}
+		c.series[key] = s
+	}
+	s.value += delta
+}
+
+func (c *CounterVec) Collect(w io.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	WriteHeader(w, c.name, c.help, "counter")
+	for _, key := range sortedKeys(c.series) {
+		s := c.series[key]
+		WriteSample(w, c.name, c.labels, s.values, s.value)
+	}
+}
+
+// HistogramVec - Гистограмма с набором меток.
+type HistogramVec struct {
+	name    string
+	help    string
+	labels  []string
+	buckets []float64
+	series  map[string]*histogramSeries
+	mu      sync.Mutex
+}
+
+type histogramSeries struct {
+	values []string
+	// counts - Число наблюдений в каждом интервале (не накопительно).
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// NewHistogramVec - Создаёт гистограмму. buckets должны идти по возрастанию, +Inf добавляется сама.
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	return &HistogramVec{
+		name:    name,
+		help:    help,
+		labels:  labels,
+		buckets: buckets,
+		series:  make(map[string]*histogramSeries),
+	}
+}
+
+func (h *HistogramVec) Observe(v float64, values ...string) {
+	if len(values) != len(h.labels) {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	key := labelKey(values)
+	s, ok := h.series[key]
+	if !ok {
+		s = &histogramSeries{values: append([]string(nil), values...), counts: make([]uint64, len(h.buckets))}
+		h.series[key] = s
+	}
+
+	i := sort.SearchFloat64s(h.buckets, v)
+	if i < len(h.buckets) {
+		s.counts[i]++
+	}
+	s.count++
+	s.sum += v
+}
+
+func (h *HistogramVec) Collect(w io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	WriteHeader(w, h.name, h.help, "histogram")
+	bucketLabels := append(append([]string(nil), h.labels...), "le")
+	for _, key := range sortedKeys(h.series) {
+		s := h.series[key]
+		bucketValues := append(append([]string(nil), s.values...), "")
+
+		var cumulative uint64
+		for i, bound := range h.buckets {
+			cumulative += s.counts[i]
+			bucketValues[len(bucketValues)-1] = formatFloat(bound)
+			WriteSample(w, h.name+"_bucket", bucketLabels, bucketValues, float64(cumulative))
+		}
+		bucketValues[len(bucketValues)-1] = "+Inf"
+		WriteSample(w, h.name+"_bucket", bucketLabels, bucketValues, float64(s.count))
+		WriteSample(w, h.name+"_sum", h.labels, s.values, s.sum)
+		WriteSample(w, h.name+"_count", h.labels, s.values, float64(s.count))
+	}
+}
+
+// Sample - Значение серии, которое возвращает GaugeFunc.
+type Sample struct {
+	Values []string
+	Value  float64
+}
+
+// GaugeFunc - Показатель, значения которого вычисляются в момент запроса метрик.
+type GaugeFunc struct {
+	name   string
+	help   string
+	labels []string
+	fn     func() []Sample
+}
+
+func NewGaugeFunc(name, help string, fn func() []Sample, labels ...string) *GaugeFunc {
+	return &GaugeFunc{name: name, help: help, labels: labels, fn: fn}
+}
+
+func (g *GaugeFunc) Collect(w io.Writer) {
+	WriteHeader(w, g.name, g.help, "gauge")
+	for _, s := range g.fn() {
+		if len(s.Values) != len(g.labels) {
+			continue
+		}
+		WriteSample(w, g.name, g.labels, s.Values, s.Value)
+	}
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -1,8 +1,76 @@
 package models
 
-// Author - Бизнес-модель автора.
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Author - Бизнес-модель автора. Автор появляется вместе с первой цитатой, сведения о нём
+// заполняются отдельно. Разные авторы могут носить одно имя, различаются они по ID.
 type Author struct {
-	ID         int
-	AuthorName string
-	Quotes     []Quote
+	ID     int
+	Name   string
+	Quotes []*Quote
+	AuthorMeta
+}
+
+// AuthorRef - Ссылка на автора из запроса: по ID или по имени. Имя ищется и среди псевдонимов.
+type AuthorRef struct {
+	ID   int
+	Name string
+}
+
+// ParseAuthorRef - Число - ID автора, иначе имя.
+func ParseAuthorRef(s string) AuthorRef {
+	if id, err := strconv.Atoi(s); err == nil && id > 0 {
+		return AuthorRef{ID: id}
+	}
+	return AuthorRef{Name: s}
+}
+
+func (r AuthorRef) String() string {
+	if r.ID > 0 {
+		return "#" + strconv.Itoa(r.ID)
+	}
+	return r.Name
+}
+
+// NameKey - Ключ, по которому сравниваются имена и псевдонимы: без учёта регистра, лишних пробелов и ё.
+func NameKey(name string) string {
+	return strings.ReplaceAll(strings.Join(strings.Fields(strings.ToLower(name)), " "), "ё", "е")
+}
+
+// AuthorMeta - Сведения об авторе. Пустые поля - неизвестно.
+type AuthorMeta struct {
+	// FullName - Полное имя, если автор известен под псевдонимом или сокращением.
+	FullName string
+	// BirthYear, DeathYear - Годы жизни. Отрицательные - до нашей эры.
+	BirthYear *int
+	DeathYear *int
+	// Aliases - Другие имена автора: «Л. Толстой», «Leo Tolstoy». По ним автор находится так же, как по имени.
+	Aliases []string
+	// Nationality - Страна или народ.
+	Nationality string
+	Bio         string
+	Links       []Link
+	// UpdatedAt - Когда сведения менялись в последний раз. Нулевое время - не заполнялись.
+	UpdatedAt time.Time
+}
+
+// Link - Ссылка на внешний источник об авторе.
+type Link struct {
+	Title string
+	URL   string
+}
+
+// Published - Число опубликованных цитат автора.
+func (a *Author) Published() int {
+	n := 0
+	for _, q := range a.Quotes {
+		if q.Published() {
+			n++
+		}
+	}
+	return n
 }
@@ -1,8 +1,47 @@
 package models
 
+import "time"
+
+// Состояния модерации цитаты.
+const (
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusRejected = "rejected"
+)
+
 // Quote - Бизнес-модель цитаты.
 type Quote struct {
-	ID         int
-	Text       string
+	ID   int
+	Text string
+	// AuthorID - Автор цитаты. AuthorName - его основное имя на момент сохранения, даже если цитату
+	// добавили под псевдонимом.
+	AuthorID   int
 	AuthorName string
+	// CreatedBy - Кто добавил цитату (subject из токена).
+	CreatedBy string
+	CreatedAt time.Time
+	// Status - Состояние модерации. Пусто у цитат, добавленных до появления модерации.
+	Status string
+	// ModeratedBy, ModeratedAt - Кто и когда принял решение по цитате.
+	ModeratedBy string
+	ModeratedAt *time.Time
+	// RejectReason - Причина отклонения.
+	RejectReason string
+	// Flags - Фильтры содержимого, из-за которых цитата ушла на модерацию.
+	Flags []string
+	// Source - Источник цитаты. nil - не указан.
+	Source *Source
+}
+
+// Published - Участвует ли цитата в выдаче.
+func (q *Quote) Published() bool {
+	return q.Status == "" || q.Status == StatusApproved
+}
+
+// AuthorRef - Ссылка на автора цитаты: author_id важнее имени.
+func (q *Quote) AuthorRef() AuthorRef {
+	if q.AuthorID > 0 {
+		return AuthorRef{ID: q.AuthorID}
+	}
+	return AuthorRef{Name: q.AuthorName}
 }
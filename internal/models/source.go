@@ -0,0 +1,28 @@
+package models
+
+// Виды источника цитаты.
+const (
+	SourceBook      = "book"
+	SourceArticle   = "article"
+	SourceSpeech    = "speech"
+	SourceInterview = "interview"
+	SourceLetter    = "letter"
+	SourceWeb       = "web"
+	SourceOther     = "other"
+)
+
+// SourceTypes - Допустимые виды источника в порядке, в котором их перечисляет документация.
+var SourceTypes = []string{SourceBook, SourceArticle, SourceSpeech, SourceInterview, SourceLetter, SourceWeb, SourceOther}
+
+// Source - Откуда взята цитата. Пустые поля - неизвестно.
+type Source struct {
+	Type  string
+	Title string
+	// Publisher - Издательство, журнал или событие, на котором прозвучала речь.
+	Publisher string
+	// Year - Год издания или выступления. Отрицательный - до нашей эры.
+	Year *int
+	// Pages - Страница или диапазон страниц: «42», «42-45».
+	Pages string
+	URL   string
+}
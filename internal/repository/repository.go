@@ -3,9 +3,15 @@ package repository
 import (
 	"context"
 	"errors"
-	"go-offline-test/internal/shared/dto"
+	"fmt"
+	"go-offline-test/internal/models"
+	"go-offline-test/internal/tracing"
+	"log/slog"
 	"math/rand"
+	"slices"
+	"sort"
 	"sync"
+	"time"
 )
 
 var (
@@ -14,33 +20,146 @@ var (
 	ErrAuthorNotFound       = errors.New("автор не найден в памяти")
 	ErrQuoteNotFound        = errors.New("цитата не найдена в памяти")
 	ErrQuoteAlreadyExist    = errors.New("цитата уже существует")
+	ErrQuotaExceeded        = errors.New("превышена квота")
+	ErrQuoteNotPending      = errors.New("цитата не ждёт модерации")
+	ErrAuthorAmbiguous      = errors.New("имени соответствуют несколько авторов")
+	ErrAliasTaken           = errors.New("псевдоним занят другим автором")
+	ErrMergeSelf            = errors.New("автора нельзя объединить с самим собой")
+	// ErrMergeTarget - Ошибка поиска автора, которому переносятся цитаты.
+	ErrMergeTarget = errors.New("автор для объединения")
 )
 
+// AmbiguousAuthorError - Имя или псевдоним носят несколько авторов, выбрать нужно по ID.
+type AmbiguousAuthorError struct {
+	Name string
+	// IDs - Подходящие авторы по возрастанию ID.
+	IDs []int
+}
+
+func (e *AmbiguousAuthorError) Error() string {
+	return fmt.Sprintf("%v: %q, id %v", ErrAuthorAmbiguous, e.Name, e.IDs)
+}
+
+func (e *AmbiguousAuthorError) Unwrap() error {
+	return ErrAuthorAmbiguous
+}
+
+// AliasTakenError - Псевдоним совпадает с именем или псевдонимом другого автора.
+type AliasTakenError struct {
+	Alias    string
+	AuthorID int
+}
+
+func (e *AliasTakenError) Error() string {
+	return fmt.Sprintf("%v: %q, id %d", ErrAliasTaken, e.Alias, e.AuthorID)
+}
+
+func (e *AliasTakenError) Unwrap() error {
+	return ErrAliasTaken
+}
+
+// Quota - Ограничения на объём данных. Нулевое значение означает отсутствие ограничения.
+type Quota struct {
+	MaxQuotes  int
+	MaxAuthors int
+}
+
+// Stats - Размеры коллекций репозитория.
+type Stats struct {
+	Quotes  int
+	Authors int
+	FreeIDs int
+	// Pending - Цитаты на модерации, они входят и в Quotes.
+	Pending int
+	// OldestPending - Когда добавлена самая старая цитата на модерации. Нулевое время - очередь пуста.
+	OldestPending time.Time
+}
+
+// Merge - Итог объединения авторов.
+type Merge struct {
+	// From - Удалённый автор, каким он был до объединения.
+	From *models.Author
+	// IntoBefore, Into - Автор, которому достались цитаты, до и после объединения.
+	IntoBefore, Into *models.Author
+	// Moved - Перенесённые цитаты до и после переноса.
+	Moved []Moved
+	// Dropped - Цитаты, которые у Into уже были. Они удалены как дубликаты.
+	Dropped []*models.Quote
+}
+
+// Moved - Цитата до и после переноса к другому автору.
+type Moved struct {
+	Before, After *models.Quote
+}
+
 type QuoteRepository struct {
-	quotes        map[int]*dto.Quote
-	authors       map[string]*dto.Author
+	quotes map[int]*models.Quote
+	// authors - Авторы по ID. names - ID авторов по ключу имени или псевдонима (models.NameKey):
+	// одно имя могут носить несколько авторов.
+	authors       map[int]*models.Author
+	names         map[string][]int
 	quoteCounter  int
 	authorCounter int
 	freeIDs       map[int]bool
+	quota         Quota
 	mu            sync.RWMutex
 }
 
 func NewQuoteRepository() *QuoteRepository {
 	return &QuoteRepository{
-		quotes:  make(map[int]*dto.Quote),
-		authors: make(map[string]*dto.Author),
+		quotes:  make(map[int]*models.Quote),
+		authors: make(map[int]*models.Author),
+		names:   make(map[string][]int),
 		freeIDs: make(map[int]bool),
 	}
 }
 
-func (qr *QuoteRepository) AddQuote(ctx context.Context, quote *dto.Quote) error {
+// lock, rlock - Захватывают мьютекс и записывают время ожидания в span вызова.
+func (qr *QuoteRepository) lock(span *tracing.Span) {
+	start := time.Now()
 	qr.mu.Lock()
+	span.SetAttr("lock.wait_us", time.Since(start).Microseconds())
+}
+
+func (qr *QuoteRepository) rlock(span *tracing.Span) {
+	start := time.Now()
+	qr.mu.RLock()
+	span.SetAttr("lock.wait_us", time.Since(start).Microseconds())
+}
+
+func (qr *QuoteRepository) AddQuote(ctx context.Context, quote *models.Quote) error {
+	ctx, span := tracing.Start(ctx, "QuoteRepository.AddQuote")
+	defer span.End()
+
+	qr.lock(span)
 	defer qr.mu.Unlock()
 
 	if err := ctx.Err(); err != nil {
 		return err
 	}
 
+	// Проверяем квоту до выделения id, чтобы не тратить их впустую.
+	if qr.quota.MaxQuotes > 0 && len(qr.quotes) >= qr.quota.MaxQuotes {
+		return fmt.Errorf("%w: не более %d цитат", ErrQuotaExceeded, qr.quota.MaxQuotes)
+	}
+	// Автор ищется по ID, если он указан, иначе по имени и псевдонимам. Неизвестное имя - новый автор.
+	author, err := qr.resolve(models.AuthorRef{ID: quote.AuthorID, Name: quote.AuthorName})
+	exists := err == nil
+	if err != nil && (quote.AuthorID > 0 || !errors.Is(err, ErrAuthorNotFound)) {
+		return err
+	}
+	if !exists && qr.quota.MaxAuthors > 0 && len(qr.authors) >= qr.quota.MaxAuthors {
+		return fmt.Errorf("%w: не более %d авторов", ErrQuotaExceeded, qr.quota.MaxAuthors)
+	}
+	// Дубликат отклоняем до записи, иначе цитата сохранится, хотя клиент получит 409.
+	if exists {
+		for _, q := range author.Quotes {
+			if q.Text == quote.Text {
+				return ErrQuoteAlreadyExist
+			}
+		}
+	}
+
 	// Проверяем есть ли свободные id в списке для ключа
 	if len(qr.freeIDs) > 0 {
 		// Если есть - записываем по ключу этого id данные
@@ -57,32 +176,26 @@ func (qr *QuoteRepository) AddQuote(ctx context.Context, quote *dto.Quote) error
 		quote.ID = qr.quoteCounter
 	}
 
+	// Если автора нет - создаём. Цитата хранит основное имя автора, даже если её добавили под псевдонимом.
+	if !exists {
+		author = qr.newAuthor(quote.AuthorName, models.AuthorMeta{})
+	}
+	quote.AuthorID, quote.AuthorName = author.ID, author.Name
+	author.Quotes = append(author.Quotes, quote)
+
 	// Записываем данные
 	qr.quotes[quote.ID] = quote
-
-	// Проверяем, существует ли указанный автор, если нет - создаём. Логика со счётчиками такая же, как и с цитатами
-	if author, exists := qr.authors[quote.AuthorName]; !exists {
-		qr.authorCounter++
-		qr.authors[quote.AuthorName] = &dto.Author{
-			ID:         qr.authorCounter,
-			AuthorName: quote.AuthorName,
-			Quotes:     []*dto.Quote{quote},
-		}
-	} else {
-		// Проверяем, нет ли такой же цитаты у автора
-		for _, q := range author.Quotes {
-			if q.Text == quote.Text {
-				return ErrQuoteAlreadyExist
-			}
-		}
-		author.Quotes = append(author.Quotes, quote)
-	}
+	span.SetAttr("quote.id", quote.ID)
+	slog.DebugContext(ctx, "цитата сохранена в памяти", "quote_id", quote.ID, "author_id", author.ID, "free_ids", len(qr.freeIDs))
 
 	return nil
 }
 
-func (qr *QuoteRepository) Quotes(ctx context.Context) ([]*dto.Quote, error) {
-	qr.mu.RLock()
+func (qr *QuoteRepository) Quotes(ctx context.Context) ([]*models.Quote, error) {
+	ctx, span := tracing.Start(ctx, "QuoteRepository.Quotes")
+	defer span.End()
+
+	qr.rlock(span)
 	defer qr.mu.RUnlock()
 
 	if err := ctx.Err(); err != nil {
@@ -94,10 +207,10 @@ func (qr *QuoteRepository) Quotes(ctx context.Context) ([]*dto.Quote, error) {
 		return nil, ErrQuotesNotFound
 	}
 
-	// Переписываем из мапы в слайс.
-	quotes := make([]*dto.Quote, 0, len(qr.quotes))
+	// Переписываем из мапы в слайс. Цитаты на модерации в выдачу не попадают.
+	quotes := make([]*models.Quote, 0, len(qr.quotes))
 	for _, quote := range qr.quotes {
-		if quote != nil {
+		if quote != nil && quote.Published() {
 			quotes = append(quotes, quote)
 		}
 	}
@@ -109,8 +222,11 @@ func (qr *QuoteRepository) Quotes(ctx context.Context) ([]*dto.Quote, error) {
 	return quotes, nil
 }
 
-func (qr *QuoteRepository) RandomQuote(ctx context.Context) (*dto.Quote, error) {
-	qr.mu.RLock()
+func (qr *QuoteRepository) RandomQuote(ctx context.Context) (*models.Quote, error) {
+	ctx, span := tracing.Start(ctx, "QuoteRepository.RandomQuote")
+	defer span.End()
+
+	qr.rlock(span)
 	defer qr.mu.RUnlock()
 
 	if err := ctx.Err(); err != nil {
@@ -136,18 +252,40 @@ func (qr *QuoteRepository) RandomQuote(ctx context.Context) (*dto.Quote, error)
 	return quotes[random], nil
 }
 
-func (qr *QuoteRepository) QuotesByAuthor(ctx context.Context, authorName string) ([]*dto.Quote, error) {
-	qr.mu.RLock()
+func (qr *QuoteRepository) QuoteByID(ctx context.Context, idQuote int) (*models.Quote, error) {
+	ctx, span := tracing.Start(ctx, "QuoteRepository.QuoteByID")
+	defer span.End()
+
+	qr.rlock(span)
 	defer qr.mu.RUnlock()
 
 	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
 
-	// Проверяем, существует ли автор.
-	author, exists := qr.authors[authorName]
+	quote, exists := qr.quotes[idQuote]
 	if !exists {
-		return nil, ErrAuthorNotFound
+		return nil, ErrQuoteNotFound
+	}
+
+	return quote, nil
+}
+
+func (qr *QuoteRepository) QuotesByAuthor(ctx context.Context, authorName string) ([]*models.Quote, error) {
+	ctx, span := tracing.Start(ctx, "QuoteRepository.QuotesByAuthor")
+	defer span.End()
+
+	qr.rlock(span)
+	defer qr.mu.RUnlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Проверяем, существует ли автор. Имя ищется и среди псевдонимов.
+	author, err := qr.resolve(models.AuthorRef{Name: authorName})
+	if err != nil {
+		return nil, err
 	}
 
 	// Проверяем есть ли цитаты у автора.
@@ -155,25 +293,49 @@ func (qr *QuoteRepository) QuotesByAuthor(ctx context.Context, authorName string
 		return nil, ErrAuthorQuotesNotFound
 	}
 
-	return author.Quotes, nil
+	quotes := make([]*models.Quote, 0, len(author.Quotes))
+	for _, quote := range author.Quotes {
+		if quote.Published() {
+			quotes = append(quotes, quote)
+		}
+	}
+	// Автор, у которого все цитаты на модерации, для читателей ещё не существует.
+	if len(quotes) == 0 {
+		return nil, ErrAuthorNotFound
+	}
+
+	return quotes, nil
 }
 
-func (qr *QuoteRepository) DeleteQuote(ctx context.Context, idQuote int) error {
-	qr.mu.Lock()
+// DeleteQuote - Удаляет цитату и возвращает её: снимок для журнала берётся под той же блокировкой,
+// что и удаление, поэтому это не цитата, которая заняла освободившийся id.
+func (qr *QuoteRepository) DeleteQuote(ctx context.Context, idQuote int) (*models.Quote, error) {
+	ctx, span := tracing.Start(ctx, "QuoteRepository.DeleteQuote")
+	defer span.End()
+	span.SetAttr("quote.id", idQuote)
+
+	qr.lock(span)
 	defer qr.mu.Unlock()
 
 	if err := ctx.Err(); err != nil {
-		return err
+		return nil, err
 	}
 
 	// Проверяем существование цитаты с данным ID.
-	if _, exists := qr.quotes[idQuote]; !exists {
-		return ErrQuoteNotFound
+	quote, exists := qr.quotes[idQuote]
+	if !exists {
+		return nil, ErrQuoteNotFound
 	}
+	qr.remove(ctx, idQuote)
 
+	return quote, nil
+}
+
+// remove - Удаляет существующую цитату. Вызывается под блокировкой на запись.
+func (qr *QuoteRepository) remove(ctx context.Context, idQuote int) {
 	// Удаляем из автора.
 	quote := qr.quotes[idQuote]
-	if author, exists := qr.authors[quote.AuthorName]; exists {
+	if author, exists := qr.authors[quote.AuthorID]; exists {
 		for i, q := range author.Quotes {
 			if q.ID == idQuote {
 				author.Quotes = append(author.Quotes[:i], author.Quotes[i+1:]...)
@@ -185,6 +347,7 @@ func (qr *QuoteRepository) DeleteQuote(ctx context.Context, idQuote int) error {
 	// Удаляем из цитат всех.
 	delete(qr.quotes, idQuote)
 	qr.freeIDs[idQuote] = true
+	slog.DebugContext(ctx, "цитата удалена из памяти", "quote_id", idQuote)
 
 	// Декрементируем счётчик, если id был максимальным для счётчика
 	if idQuote == qr.quoteCounter {
@@ -197,6 +360,433 @@ func (qr *QuoteRepository) DeleteQuote(ctx context.Context, idQuote int) error {
 			}
 		}
 	}
+}
+
+// PendingQuotes - Цитаты на модерации от старых к новым.
+func (qr *QuoteRepository) PendingQuotes(ctx context.Context) ([]*models.Quote, error) {
+	ctx, span := tracing.Start(ctx, "QuoteRepository.PendingQuotes")
+	defer span.End()
+
+	qr.rlock(span)
+	defer qr.mu.RUnlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	quotes := make([]*models.Quote, 0)
+	for _, quote := range qr.quotes {
+		if quote.Status == models.StatusPending {
+			quotes = append(quotes, quote)
+		}
+	}
+	sort.Slice(quotes, func(i, j int) bool {
+		if !quotes[i].CreatedAt.Equal(quotes[j].CreatedAt) {
+			return quotes[i].CreatedAt.Before(quotes[j].CreatedAt)
+		}
+		return quotes[i].ID < quotes[j].ID
+	})
+	span.SetAttr("quotes.pending", len(quotes))
+
+	return quotes, nil
+}
+
+// Approve - Публикует цитату с модерации. Возвращает цитату до и после решения.
+func (qr *QuoteRepository) Approve(ctx context.Context, idQuote int, moderator string) (before, after *models.Quote, err error) {
+	ctx, span := tracing.Start(ctx, "QuoteRepository.Approve")
+	defer span.End()
+	span.SetAttr("quote.id", idQuote)
+
+	qr.lock(span)
+	defer qr.mu.Unlock()
+
+	if before, err = qr.pending(ctx, idQuote); err != nil {
+		return nil, nil, err
+	}
+
+	// Сохранённую цитату не меняем на месте: её могли уже отдать читателю, и он кодирует её без блокировки.
+	approved := *before
+	approved.Status = models.StatusApproved
+	approved.ModeratedBy = moderator
+	now := time.Now().UTC()
+	approved.ModeratedAt = &now
+
+	qr.quotes[idQuote] = &approved
+	if author, exists := qr.authors[approved.AuthorID]; exists {
+		for i, q := range author.Quotes {
+			if q.ID == idQuote {
+				author.Quotes[i] = &approved
+				break
+			}
+		}
+	}
+	slog.DebugContext(ctx, "цитата одобрена", "quote_id", idQuote)
+
+	return before, &approved, nil
+}
+
+// Reject - Удаляет цитату с модерации. Возвращает цитату до решения и её отклонённую копию с причиной.
+func (qr *QuoteRepository) Reject(ctx context.Context, idQuote int, moderator, reason string) (before, after *models.Quote, err error) {
+	ctx, span := tracing.Start(ctx, "QuoteRepository.Reject")
+	defer span.End()
+	span.SetAttr("quote.id", idQuote)
+
+	qr.lock(span)
+	defer qr.mu.Unlock()
+
+	if before, err = qr.pending(ctx, idQuote); err != nil {
+		return nil, nil, err
+	}
+
+	rejected := *before
+	rejected.Status = models.StatusRejected
+	rejected.ModeratedBy = moderator
+	now := time.Now().UTC()
+	rejected.ModeratedAt = &now
+	rejected.RejectReason = reason
+
+	qr.remove(ctx, idQuote)
+
+	return before, &rejected, nil
+}
+
+// pending - Цитата, которая ждёт модерации. Вызывается под блокировкой.
+func (qr *QuoteRepository) pending(ctx context.Context, idQuote int) (*models.Quote, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	quote, exists := qr.quotes[idQuote]
+	if !exists {
+		return nil, ErrQuoteNotFound
+	}
+	if quote.Status != models.StatusPending {
+		return nil, ErrQuoteNotPending
+	}
+	return quote, nil
+}
+
+// Author - Копия автора со сведениями и цитатами, включая ждущие модерации.
+func (qr *QuoteRepository) Author(ctx context.Context, ref models.AuthorRef) (*models.Author, error) {
+	ctx, span := tracing.Start(ctx, "QuoteRepository.Author")
+	defer span.End()
+
+	qr.rlock(span)
+	defer qr.mu.RUnlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	author, err := qr.resolve(ref)
+	if err != nil {
+		return nil, err
+	}
+	return cloneAuthor(author), nil
+}
+
+// Authors - Копии авторов по ID. Неизвестные ID пропускаются.
+func (qr *QuoteRepository) Authors(ctx context.Context, ids []int) (map[int]*models.Author, error) {
+	ctx, span := tracing.Start(ctx, "QuoteRepository.Authors")
+	defer span.End()
+	span.SetAttr("authors.requested", len(ids))
+
+	qr.rlock(span)
+	defer qr.mu.RUnlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	authors := make(map[int]*models.Author, len(ids))
+	for _, id := range ids {
+		if author, exists := qr.authors[id]; exists {
+			authors[id] = cloneAuthor(author)
+		}
+	}
+	return authors, nil
+}
+
+// CreateAuthor - Создаёт автора без цитат. Имя может совпадать с именем другого автора: так различают
+// тёзок, а цитаты к ним добавляют по ID. Псевдонимы совпадать с чужими именами не могут.
+func (qr *QuoteRepository) CreateAuthor(ctx context.Context, name string, meta models.AuthorMeta) (*models.Author, error) {
+	ctx, span := tracing.Start(ctx, "QuoteRepository.CreateAuthor")
+	defer span.End()
+
+	qr.lock(span)
+	defer qr.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if qr.quota.MaxAuthors > 0 && len(qr.authors) >= qr.quota.MaxAuthors {
+		return nil, fmt.Errorf("%w: не более %d авторов", ErrQuotaExceeded, qr.quota.MaxAuthors)
+	}
+	meta.Aliases = withoutName(meta.Aliases, name)
+	if err := qr.checkAliases(0, meta.Aliases); err != nil {
+		return nil, err
+	}
+	meta.UpdatedAt = time.Now().UTC()
+	author := qr.newAuthor(name, meta)
+	span.SetAttr("author.id", author.ID)
+	slog.DebugContext(ctx, "автор создан", "author_id", author.ID)
+
+	return cloneAuthor(author), nil
+}
+
+// UpdateAuthor - Заменяет сведения об авторе. Возвращает копии автора до и после изменения.
+func (qr *QuoteRepository) UpdateAuthor(ctx context.Context, ref models.AuthorRef, meta models.AuthorMeta) (before, after *models.Author, err error) {
+	ctx, span := tracing.Start(ctx, "QuoteRepository.UpdateAuthor")
+	defer span.End()
+
+	qr.lock(span)
+	defer qr.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	author, err := qr.resolve(ref)
+	if err != nil {
+		return nil, nil, err
+	}
+	meta.Aliases = withoutName(meta.Aliases, author.Name)
+	if err := qr.checkAliases(author.ID, meta.Aliases); err != nil {
+		return nil, nil, err
+	}
+	before = cloneAuthor(author)
+	meta.UpdatedAt = time.Now().UTC()
+	qr.unindex(author)
+	author.AuthorMeta = meta
+	qr.index(author)
+	slog.DebugContext(ctx, "сведения об авторе обновлены", "author_id", author.ID)
+
+	return before, cloneAuthor(author), nil
+}
+
+// MergeAuthors - Переносит цитаты автора from к автору into и удаляет from. Имя и псевдонимы from
+// становятся псевдонимами into, а сведения, которых у into нет, берутся у from. Цитаты, которые
+// у into уже есть, удаляются как дубликаты.
+func (qr *QuoteRepository) MergeAuthors(ctx context.Context, from, into models.AuthorRef) (*Merge, error) {
+	ctx, span := tracing.Start(ctx, "QuoteRepository.MergeAuthors")
+	defer span.End()
+
+	qr.lock(span)
+	defer qr.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	source, err := qr.resolve(from)
+	if err != nil {
+		return nil, err
+	}
+	target, err := qr.resolve(into)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMergeTarget, err)
+	}
+	if source.ID == target.ID {
+		return nil, ErrMergeSelf
+	}
+
+	texts := make(map[string]bool, len(target.Quotes))
+	for _, quote := range target.Quotes {
+		texts[quote.Text] = true
+	}
+	merge := &Merge{From: cloneAuthor(source), IntoBefore: cloneAuthor(target)}
+	// remove меняет source.Quotes, поэтому идём по копии.
+	for _, quote := range slices.Clone(source.Quotes) {
+		if texts[quote.Text] {
+			qr.remove(ctx, quote.ID)
+			merge.Dropped = append(merge.Dropped, quote)
+			continue
+		}
+		// Сохранённую цитату не меняем на месте, как и при одобрении.
+		moved := *quote
+		moved.AuthorID, moved.AuthorName = target.ID, target.Name
+		qr.quotes[moved.ID] = &moved
+		target.Quotes = append(target.Quotes, &moved)
+		texts[moved.Text] = true
+		merge.Moved = append(merge.Moved, Moved{Before: quote, After: &moved})
+	}
+
+	qr.unindex(source)
+	delete(qr.authors, source.ID)
+	qr.unindex(target)
+	target.Aliases = mergeAliases(target, source)
+	fillMeta(&target.AuthorMeta, source.AuthorMeta)
+	target.UpdatedAt = time.Now().UTC()
+	qr.index(target)
+	merge.Into = cloneAuthor(target)
+
+	span.SetAttr("quotes.moved", len(merge.Moved))
+	slog.DebugContext(ctx, "авторы объединены", "from_id", source.ID, "into_id", target.ID,
+		"moved", len(merge.Moved), "dropped", len(merge.Dropped))
+	return merge, nil
+}
 
+// resolve - Автор по ссылке. Вызывается под блокировкой.
+func (qr *QuoteRepository) resolve(ref models.AuthorRef) (*models.Author, error) {
+	if ref.ID > 0 {
+		author, exists := qr.authors[ref.ID]
+		if !exists {
+			return nil, ErrAuthorNotFound
+		}
+		return author, nil
+	}
+
+	ids := qr.names[models.NameKey(ref.Name)]
+	switch len(ids) {
+	case 0:
+		return nil, ErrAuthorNotFound
+	case 1:
+		return qr.authors[ids[0]], nil
+	default:
+		return nil, &AmbiguousAuthorError{Name: ref.Name, IDs: slices.Clone(ids)}
+	}
+}
+
+// newAuthor - Создаёт автора. Вызывается под блокировкой, квота уже проверена.
+// Логика со счётчиком такая же, как и с цитатами, но ID авторов не переиспользуются.
+func (qr *QuoteRepository) newAuthor(name string, meta models.AuthorMeta) *models.Author {
+	qr.authorCounter++
+	author := &models.Author{ID: qr.authorCounter, Name: name, AuthorMeta: meta}
+	qr.authors[author.ID] = author
+	qr.index(author)
+	return author
+}
+
+// checkAliases - Псевдонимы не должны совпадать с именами и псевдонимами других авторов: иначе
+// псевдоним перестанет однозначно указывать на автора. Вызывается под блокировкой.
+func (qr *QuoteRepository) checkAliases(authorID int, aliases []string) error {
+	for _, alias := range aliases {
+		for _, id := range qr.names[models.NameKey(alias)] {
+			if id != authorID {
+				return &AliasTakenError{Alias: alias, AuthorID: id}
+			}
+		}
+	}
 	return nil
 }
+
+// index, unindex - Добавляют имя и псевдонимы автора в поиск по имени и убирают их оттуда.
+// Вызываются под блокировкой на запись.
+func (qr *QuoteRepository) index(author *models.Author) {
+	for _, key := range nameKeys(author) {
+		ids := qr.names[key]
+		if i, found := slices.BinarySearch(ids, author.ID); !found {
+			qr.names[key] = slices.Insert(ids, i, author.ID)
+		}
+	}
+}
+
+func (qr *QuoteRepository) unindex(author *models.Author) {
+	for _, key := range nameKeys(author) {
+		ids := slices.DeleteFunc(qr.names[key], func(id int) bool { return id == author.ID })
+		if len(ids) == 0 {
+			delete(qr.names, key)
+			continue
+		}
+		qr.names[key] = ids
+	}
+}
+
+// nameKeys - Ключи имени и псевдонимов автора без повторов.
+func nameKeys(author *models.Author) []string {
+	keys := []string{models.NameKey(author.Name)}
+	for _, alias := range author.Aliases {
+		if key := models.NameKey(alias); !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// withoutName - Псевдонимы без совпадающих с именем автора: искать по ним незачем.
+func withoutName(aliases []string, name string) []string {
+	key := models.NameKey(name)
+	return slices.DeleteFunc(slices.Clone(aliases), func(alias string) bool { return models.NameKey(alias) == key })
+}
+
+// mergeAliases - Псевдонимы target, к которым добавлены имя и псевдонимы source. Совпадающие
+// с именем target и друг с другом пропускаются.
+func mergeAliases(target, source *models.Author) []string {
+	seen := map[string]bool{models.NameKey(target.Name): true}
+	var aliases []string
+	for _, alias := range slices.Concat(target.Aliases, []string{source.Name}, source.Aliases) {
+		if key := models.NameKey(alias); !seen[key] {
+			seen[key] = true
+			aliases = append(aliases, alias)
+		}
+	}
+	return aliases
+}
+
+// fillMeta - Заполняет пустые сведения dst сведениями src.
+func fillMeta(dst *models.AuthorMeta, src models.AuthorMeta) {
+	if dst.FullName == "" {
+		dst.FullName = src.FullName
+	}
+	if dst.BirthYear == nil {
+		dst.BirthYear = src.BirthYear
+	}
+	if dst.DeathYear == nil {
+		dst.DeathYear = src.DeathYear
+	}
+	if dst.Nationality == "" {
+		dst.Nationality = src.Nationality
+	}
+	if dst.Bio == "" {
+		dst.Bio = src.Bio
+	}
+	if len(dst.Links) == 0 {
+		dst.Links = slices.Clone(src.Links)
+	}
+}
+
+// cloneAuthor - Копия автора, которую можно читать без блокировки. Цитаты не копируются:
+// сохранённые цитаты не меняются на месте.
+func cloneAuthor(author *models.Author) *models.Author {
+	clone := *author
+	clone.Quotes = slices.Clone(author.Quotes)
+	clone.Aliases = slices.Clone(author.Aliases)
+	clone.Links = slices.Clone(author.Links)
+	return &clone
+}
+
+// SetQuota - Меняет квоту. Уже сохранённые данные не удаляются, даже если превышают новую квоту.
+func (qr *QuoteRepository) SetQuota(quota Quota) {
+	qr.mu.Lock()
+	defer qr.mu.Unlock()
+
+	qr.quota = quota
+}
+
+func (qr *QuoteRepository) Quota() Quota {
+	qr.mu.RLock()
+	defer qr.mu.RUnlock()
+
+	return qr.quota
+}
+
+func (qr *QuoteRepository) Stats() Stats {
+	qr.mu.RLock()
+	defer qr.mu.RUnlock()
+
+	stats := Stats{
+		Quotes:  len(qr.quotes),
+		Authors: len(qr.authors),
+		FreeIDs: len(qr.freeIDs),
+	}
+	for _, quote := range qr.quotes {
+		if quote.Status != models.StatusPending {
+			continue
+		}
+		stats.Pending++
+		if stats.OldestPending.IsZero() || quote.CreatedAt.Before(stats.OldestPending) {
+			stats.OldestPending = quote.CreatedAt
+		}
+	}
+	return stats
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"go-offline-test/internal/shared"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultTenant - Тенант, в который попадают запросы без явного указания тенанта.
+const DefaultTenant = shared.DefaultTenant
+
+var (
+	ErrTenantNotFound      = errors.New("тенант не найден")
+	ErrTenantAlreadyExist  = errors.New("тенант уже существует")
+	ErrInvalidTenantName   = errors.New("имя тенанта должно состоять из латинских букв в нижнем регистре, цифр и дефисов (до 63 символов)")
+	ErrDefaultTenantDelete = errors.New("тенант по умолчанию нельзя удалить")
+)
+
+// Tenant - Изолированный набор цитат со своими авторами, счётчиками id и квотой.
+type Tenant struct {
+	Name      string
+	CreatedAt time.Time
+	Repo      *QuoteRepository
+}
+
+// TenantRegistry - Реестр тенантов. Каждый тенант хранит данные в собственном QuoteRepository.
+type TenantRegistry struct {
+	tenants      map[string]*Tenant
+	defaultQuota Quota
+	mu           sync.RWMutex
+}
+
+// NewTenantRegistry - Создаёт реестр с тенантом по умолчанию без квоты.
+// defaultQuota применяется к новым тенантам, для которых квота не указана явно.
+func NewTenantRegistry(defaultQuota Quota) *TenantRegistry {
+	return &TenantRegistry{
+		tenants: map[string]*Tenant{
+			DefaultTenant: {Name: DefaultTenant, CreatedAt: time.Now().UTC(), Repo: NewQuoteRepository()},
+		},
+		defaultQuota: defaultQuota,
+	}
+}
+
+func (tr *TenantRegistry) Create(ctx context.Context, name string, quota *Quota) (*Tenant, error) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if !shared.ValidTenantName(name) {
+		return nil, ErrInvalidTenantName
+	}
+	if _, exists := tr.tenants[name]; exists {
+		return nil, ErrTenantAlreadyExist
+	}
+
+	repo := NewQuoteRepository()
+	repo.quota = tr.defaultQuota
+	if quota != nil {
+		repo.quota = *quota
+	}
+
+	tenant := &Tenant{Name: name, CreatedAt: time.Now().UTC(), Repo: repo}
+	tr.tenants[name] = tenant
+
+	return tenant, nil
+}
+
+func (tr *TenantRegistry) Get(ctx context.Context, name string) (*Tenant, error) {
+	tr.mu.RLock()
+	defer tr.mu.RUnlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	tenant, exists := tr.tenants[name]
+	if !exists {
+		return nil, ErrTenantNotFound
+	}
+	return tenant, nil
+}
+
+func (tr *TenantRegistry) List(ctx context.Context) ([]*Tenant, error) {
+	tr.mu.RLock()
+	defer tr.mu.RUnlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	tenants := make([]*Tenant, 0, len(tr.tenants))
+	for _, tenant := range tr.tenants {
+		tenants = append(tenants, tenant)
+	}
+	sort.Slice(tenants, func(i, j int) bool {
+		return tenants[i].Name < tenants[j].Name
+	})
+
+	return tenants, nil
+}
+
+// Delete - Удаляет тенанта вместе со всеми его данными.
+func (tr *TenantRegistry) Delete(ctx context.Context, name string) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if name == DefaultTenant {
+		return ErrDefaultTenantDelete
+	}
+	if _, exists := tr.tenants[name]; !exists {
+		return ErrTenantNotFound
+	}
+	delete(tr.tenants, name)
+
+	return nil
+}
+
+// SetDefaultQuota - Меняет квоту для тенантов, которые будут созданы позже. У существующих квота не меняется.
+func (tr *TenantRegistry) SetDefaultQuota(quota Quota) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	tr.defaultQuota = quota
+}
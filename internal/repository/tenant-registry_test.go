@@ -0,0 +1,201 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"go-offline-test/internal/models"
+	"go-offline-test/internal/repository"
+	"reflect"
+	"testing"
+)
+
+func TestTenantRegistry(t *testing.T) {
+	ctx := context.Background()
+	tr := repository.NewTenantRegistry(repository.Quota{MaxQuotes: 10})
+
+	if _, err := tr.Create(ctx, "Acme_Corp", nil); !errors.Is(err, repository.ErrInvalidTenantName) {
+		t.Errorf("Create() с некорректным именем = %v, ожидалась %v", err, repository.ErrInvalidTenantName)
+	}
+	if _, err := tr.Create(ctx, repository.DefaultTenant, nil); !errors.Is(err, repository.ErrTenantAlreadyExist) {
+		t.Errorf("Create(default) = %v, ожидалась %v", err, repository.ErrTenantAlreadyExist)
+	}
+	if _, err := tr.Get(ctx, "acme"); !errors.Is(err, repository.ErrTenantNotFound) {
+		t.Errorf("Get() несуществующего = %v, ожидалась %v", err, repository.ErrTenantNotFound)
+	}
+
+	acme, err := tr.Create(ctx, "acme", nil)
+	if err != nil {
+		t.Fatalf("Create(): %v", err)
+	}
+	if got := acme.Repo.Quota(); got != (repository.Quota{MaxQuotes: 10}) {
+		t.Errorf("квота без явной = %+v, ожидалась квота по умолчанию", got)
+	}
+	tr.SetDefaultQuota(repository.Quota{MaxQuotes: 5})
+	if got := acme.Repo.Quota(); got.MaxQuotes != 10 {
+		t.Errorf("SetDefaultQuota() изменил квоту существующего тенанта: %+v", got)
+	}
+	beta, err := tr.Create(ctx, "beta", &repository.Quota{MaxAuthors: 1})
+	if err != nil {
+		t.Fatalf("Create(): %v", err)
+	}
+	if got := beta.Repo.Quota(); got != (repository.Quota{MaxAuthors: 1}) {
+		t.Errorf("явная квота = %+v", got)
+	}
+	gamma, err := tr.Create(ctx, "gamma", nil)
+	if err != nil {
+		t.Fatalf("Create(): %v", err)
+	}
+	if got := gamma.Repo.Quota(); got.MaxQuotes != 5 {
+		t.Errorf("квота нового тенанта = %+v, ожидалась новая квота по умолчанию", got)
+	}
+
+	list, err := tr.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, tenant := range list {
+		names = append(names, tenant.Name)
+	}
+	if want := []string{"acme", "beta", "default", "gamma"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("List() = %v, ожидалось %v", names, want)
+	}
+
+	if err := tr.Delete(ctx, repository.DefaultTenant); !errors.Is(err, repository.ErrDefaultTenantDelete) {
+		t.Errorf("Delete(default) = %v, ожидалась %v", err, repository.ErrDefaultTenantDelete)
+	}
+	if err := tr.Delete(ctx, "gamma"); err != nil {
+		t.Fatalf("Delete(): %v", err)
+	}
+	if _, err := tr.Get(ctx, "gamma"); !errors.Is(err, repository.ErrTenantNotFound) {
+		t.Errorf("Get() удалённого = %v, ожидалась %v", err, repository.ErrTenantNotFound)
+	}
+	if err := tr.Delete(ctx, "gamma"); !errors.Is(err, repository.ErrTenantNotFound) {
+		t.Errorf("повторный Delete() = %v, ожидалась %v", err, repository.ErrTenantNotFound)
+	}
+}
+
+func TestQuota(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name   string
+		quota  repository.Quota
+		quotes []models.Quote
+		// errAt - Номер цитаты, на которой срабатывает квота, -1 - не срабатывает.
+		errAt int
+	}{
+		{"лимит цитат", repository.Quota{MaxQuotes: 2}, []models.Quote{
+			{AuthorName: "Толстой", Text: "1"}, {AuthorName: "Толстой", Text: "2"}, {AuthorName: "Толстой", Text: "3"},
+		}, 2},
+		{"лимит авторов", repository.Quota{MaxAuthors: 1}, []models.Quote{
+			{AuthorName: "Толстой", Text: "1"}, {AuthorName: "Чехов", Text: "2"},
+		}, 1},
+		{"новые цитаты известного автора в пределах лимита авторов", repository.Quota{MaxAuthors: 1}, []models.Quote{
+			{AuthorName: "Толстой", Text: "1"}, {AuthorName: "Толстой", Text: "2"},
+		}, -1},
+		{"без квоты", repository.Quota{}, []models.Quote{
+			{AuthorName: "Толстой", Text: "1"}, {AuthorName: "Чехов", Text: "2"}, {AuthorName: "Гоголь", Text: "3"},
+		}, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := repository.NewTenantRegistry(repository.Quota{})
+			tenant, err := tr.Create(ctx, "acme", &tt.quota)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, quote := range tt.quotes {
+				err := tenant.Repo.AddQuote(ctx, &quote)
+				switch {
+				case i == tt.errAt && !errors.Is(err, repository.ErrQuotaExceeded):
+					t.Errorf("AddQuote(%d) = %v, ожидалась %v", i, err, repository.ErrQuotaExceeded)
+				case i != tt.errAt && err != nil:
+					t.Errorf("AddQuote(%d): %v", i, err)
+				}
+			}
+			// Отклонённая по квоте цитата не тратит id.
+			if got := tenant.Repo.Stats().FreeIDs; got != 0 {
+				t.Errorf("свободных id %d, ожидалось 0", got)
+			}
+		})
+	}
+
+	t.Run("освобождённое место снова доступно", func(t *testing.T) {
+		tr := repository.NewTenantRegistry(repository.Quota{MaxQuotes: 1})
+		tenant, err := tr.Create(ctx, "acme", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		first := &models.Quote{AuthorName: "Толстой", Text: "1"}
+		if err := tenant.Repo.AddQuote(ctx, first); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tenant.Repo.DeleteQuote(ctx, first.ID); err != nil {
+			t.Fatal(err)
+		}
+		if err := tenant.Repo.AddQuote(ctx, &models.Quote{AuthorName: "Толстой", Text: "2"}); err != nil {
+			t.Errorf("AddQuote() после удаления: %v", err)
+		}
+	})
+}
+
+// TestTenantIsolation - У каждого тенанта свои цитаты, авторы и счётчики id, включая освободившиеся id.
+func TestTenantIsolation(t *testing.T) {
+	ctx := context.Background()
+	tr := repository.NewTenantRegistry(repository.Quota{})
+	acme, err := tr.Create(ctx, "acme", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	beta, err := tr.Create(ctx, "beta", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	add := func(repo *repository.QuoteRepository, text string) int {
+		t.Helper()
+		quote := &models.Quote{AuthorName: "Толстой", Text: text}
+		if err := repo.AddQuote(ctx, quote); err != nil {
+			t.Fatalf("AddQuote(%q): %v", text, err)
+		}
+		return quote.ID
+	}
+	for _, text := range []string{"1", "2", "3"} {
+		add(acme.Repo, text)
+	}
+	if _, err := acme.Repo.DeleteQuote(ctx, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	if id := add(beta.Repo, "1"); id != 1 {
+		t.Errorf("первая цитата beta получила id %d, ожидался 1: свободный id acme попал к beta", id)
+	}
+	if got := acme.Repo.Stats().FreeIDs; got != 1 {
+		t.Errorf("свободных id у acme %d, ожидался 1", got)
+	}
+	if id := add(acme.Repo, "4"); id != 2 {
+		t.Errorf("новая цитата acme получила id %d, ожидался освободившийся 2", id)
+	}
+	if id := add(beta.Repo, "2"); id != 2 {
+		t.Errorf("вторая цитата beta получила id %d, ожидался 2", id)
+	}
+
+	// Одинаковые цитаты в разных тенантах не считаются дубликатами.
+	if err := beta.Repo.AddQuote(ctx, &models.Quote{AuthorName: "Толстой", Text: "3"}); err != nil {
+		t.Errorf("AddQuote() цитаты, которая есть у acme: %v", err)
+	}
+	if got := acme.Repo.Stats(); got.Quotes != 3 || got.Authors != 1 {
+		t.Errorf("Stats() acme = %+v, ожидалось 3 цитаты и 1 автор", got)
+	}
+	if got := beta.Repo.Stats(); got.Quotes != 3 || got.Authors != 1 {
+		t.Errorf("Stats() beta = %+v, ожидалось 3 цитаты и 1 автор", got)
+	}
+
+	def, err := tr.Get(ctx, repository.DefaultTenant)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := def.Repo.Quotes(ctx); !errors.Is(err, repository.ErrQuotesNotFound) {
+		t.Errorf("Quotes() тенанта по умолчанию = %v, ожидалась %v", err, repository.ErrQuotesNotFound)
+	}
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+	"go-offline-test/internal/audit"
+	"go-offline-test/internal/shared/dto"
+	"log/slog"
+)
+
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+type IAuditService interface {
+	// QueryAudit - Получает записи журнала аудита по фильтру, от новых к старым.
+	QueryAudit(ctx context.Context, filter dto.AuditFilter) ([]*dto.AuditEntry, error)
+}
+
+type AuditService struct {
+	log *audit.Log
+}
+
+func NewAuditService(auditLog *audit.Log) *AuditService {
+	return &AuditService{log: auditLog}
+}
+
+func (as *AuditService) QueryAudit(ctx context.Context, filter dto.AuditFilter) ([]*dto.AuditEntry, error) {
+	switch {
+	case filter.Limit < 0 || filter.Limit > maxAuditLimit:
+		err := NewErrInvalidField("limit", "audit.limit_range")
+		slog.WarnContext(ctx, "ошибка валидации фильтра аудита", "error", err)
+		return nil, err
+	case filter.Limit == 0:
+		filter.Limit = defaultAuditLimit
+	}
+	if !filter.Since.IsZero() && !filter.Until.IsZero() && !filter.Since.Before(filter.Until) {
+		err := NewErrInvalidField("since", "audit.since_after_until")
+		slog.WarnContext(ctx, "ошибка валидации фильтра аудита", "error", err)
+		return nil, err
+	}
+
+	entries, err := as.log.Query(ctx, filter)
+	if err != nil {
+		slog.ErrorContext(ctx, "не удалось получить записи аудита", "error", err)
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,320 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-offline-test/internal/audit"
+	"go-offline-test/internal/models"
+	"go-offline-test/internal/repository"
+	"go-offline-test/internal/shared"
+	"go-offline-test/internal/shared/dto"
+	"go-offline-test/internal/tracing"
+	"go-offline-test/internal/validation"
+	"log/slog"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Ограничения сведений об авторе.
+const (
+	maxAuthorFullName    = 200
+	maxAuthorNationality = 100
+	maxAuthorBio         = 2000
+	maxAuthorLinks       = 10
+	maxAuthorLinkTitle   = 100
+	maxAuthorAliases     = 20
+	// minAuthorYear - Самый ранний год жизни, который принимается без подозрений на опечатку.
+	minAuthorYear = -3000
+)
+
+// IAuthorService - Авторы. ref - id автора числом или имя; имя ищется и среди псевдонимов.
+type IAuthorService interface {
+	// Author - Автор и сведения о нём. Автор без опубликованных цитат для читателей не существует.
+	Author(ctx context.Context, ref string) (*dto.Author, error)
+	// CreateAuthor - Заводит автора без цитат, в том числе тёзку уже существующего.
+	CreateAuthor(ctx context.Context, create *dto.CreateAuthor) (*dto.Author, error)
+	// UpdateAuthor - Заменяет сведения об авторе.
+	UpdateAuthor(ctx context.Context, ref string, update *dto.UpdateAuthor) (*dto.Author, error)
+	// MergeAuthors - Переносит цитаты автора к другому автору, прежние имена становятся псевдонимами.
+	MergeAuthors(ctx context.Context, ref string, merge *dto.MergeAuthors) (*dto.AuthorMerge, error)
+	// ExpandAuthors - Добавляет к цитатам сведения об их авторах.
+	ExpandAuthors(ctx context.Context, quotes []*dto.Quote) error
+}
+
+func (qs *QuoteService) Author(ctx context.Context, ref string) (*dto.Author, error) {
+	ctx, span := tracing.Start(ctx, "QuoteService.Author")
+	defer span.End()
+
+	repo, err := qs.repo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	author, err := repo.Author(ctx, models.ParseAuthorRef(ref))
+	if err == nil && author.Published() == 0 {
+		err = repository.ErrAuthorNotFound
+	}
+	if err != nil {
+		return nil, qs.authorError(ctx, "не удалось получить автора", ref, err)
+	}
+	return authorToDTO(author), nil
+}
+
+func (qs *QuoteService) CreateAuthor(ctx context.Context, create *dto.CreateAuthor) (*dto.Author, error) {
+	ctx, span := tracing.Start(ctx, "QuoteService.CreateAuthor")
+	defer span.End()
+
+	create.Name = strings.TrimSpace(create.Name)
+	update := create.Update()
+	trimAuthorUpdate(update)
+	errs := qs.validateAuthorName(ctx, "name", create.Name)
+	errs = append(errs, qs.validateAuthorUpdate(ctx, update)...)
+	if len(errs) > 0 {
+		slog.WarnContext(ctx, "ошибка валидации нового автора", "author", create.Name, "error", errs)
+		return nil, errs
+	}
+
+	repo, err := qs.repo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	author, err := repo.CreateAuthor(ctx, create.Name, authorMetaFromDTO(update))
+	if err != nil {
+		return nil, qs.authorError(ctx, "не удалось создать автора", create.Name, err)
+	}
+	slog.InfoContext(ctx, "автор создан", "author", author.Name, "author_id", author.ID)
+
+	out := authorToDTO(author)
+	qs.recordAuthor(ctx, audit.OpAuthorCreate, nil, out)
+	return out, nil
+}
+
+func (qs *QuoteService) UpdateAuthor(ctx context.Context, ref string, update *dto.UpdateAuthor) (*dto.Author, error) {
+	ctx, span := tracing.Start(ctx, "QuoteService.UpdateAuthor")
+	defer span.End()
+
+	trimAuthorUpdate(update)
+	if errs := qs.validateAuthorUpdate(ctx, update); len(errs) > 0 {
+		slog.WarnContext(ctx, "ошибка валидации сведений об авторе", "author", ref, "error", errs)
+		return nil, errs
+	}
+
+	repo, err := qs.repo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	before, after, err := repo.UpdateAuthor(ctx, models.ParseAuthorRef(ref), authorMetaFromDTO(update))
+	if err != nil {
+		return nil, qs.authorError(ctx, "не удалось обновить сведения об авторе", ref, err)
+	}
+	slog.InfoContext(ctx, "сведения об авторе обновлены", "author", ref, "author_id", after.ID)
+
+	out := authorToDTO(after)
+	qs.recordAuthor(ctx, audit.OpAuthorUpdate, authorToDTO(before), out)
+	return out, nil
+}
+
+func (qs *QuoteService) MergeAuthors(ctx context.Context, ref string, merge *dto.MergeAuthors) (*dto.AuthorMerge, error) {
+	ctx, span := tracing.Start(ctx, "QuoteService.MergeAuthors")
+	defer span.End()
+
+	if merge.Into <= 0 {
+		return nil, ErrValidation{NewErrInvalidField("into", "author.merge_target_required")}
+	}
+
+	repo, err := qs.repo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := repo.MergeAuthors(ctx, models.ParseAuthorRef(ref), models.AuthorRef{ID: merge.Into})
+	if err != nil {
+		if errors.Is(err, repository.ErrMergeTarget) && errors.Is(err, repository.ErrAuthorNotFound) {
+			slog.WarnContext(ctx, "не удалось объединить авторов", "author", ref, "into", merge.Into, "error", err)
+			return nil, ErrMergeTargetNotFound.With(merge.Into)
+		}
+		return nil, qs.authorError(ctx, "не удалось объединить авторов", ref, err)
+	}
+
+	// Само объединение, перенос цитат и удаление дубликатов попадают в журнал аудита отдельными записями.
+	out := &dto.AuthorMerge{Author: authorToDTO(res.Into), Moved: []int{}, Dropped: []int{}}
+	qs.recordChange(ctx, audit.OpAuthorMerge, audit.Change{
+		AuthorBefore: authorToDTO(res.IntoBefore),
+		AuthorAfter:  out.Author,
+		MergedFrom:   authorToDTO(res.From),
+		MovedAliases: movedAliases(res.IntoBefore, res.Into),
+	})
+	for _, moved := range res.Moved {
+		qs.record(ctx, audit.OpQuoteUpdate, quoteToDTO(moved.Before), quoteToDTO(moved.After))
+		out.Moved = append(out.Moved, moved.After.ID)
+	}
+	for _, dropped := range res.Dropped {
+		qs.record(ctx, audit.OpQuoteDelete, quoteToDTO(dropped), nil)
+		out.Dropped = append(out.Dropped, dropped.ID)
+	}
+	slog.InfoContext(ctx, "авторы объединены", "author", ref, "into_id", res.Into.ID,
+		"moved", len(out.Moved), "dropped", len(out.Dropped))
+
+	return out, nil
+}
+
+// movedAliases - Псевдонимы, которые автор получил при объединении.
+func movedAliases(before, after *models.Author) []string {
+	var moved []string
+	for _, alias := range after.Aliases {
+		if !slices.Contains(before.Aliases, alias) {
+			moved = append(moved, alias)
+		}
+	}
+	return moved
+}
+
+func (qs *QuoteService) ExpandAuthors(ctx context.Context, quotes []*dto.Quote) error {
+	ctx, span := tracing.Start(ctx, "QuoteService.ExpandAuthors")
+	defer span.End()
+
+	repo, err := qs.repo(ctx)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]int, 0, len(quotes))
+	for _, quote := range quotes {
+		if !slices.Contains(ids, quote.AuthorID) {
+			ids = append(ids, quote.AuthorID)
+		}
+	}
+
+	authors, err := repo.Authors(ctx, ids)
+	if err != nil {
+		slog.ErrorContext(ctx, "не удалось получить сведения об авторах", "error", err)
+		return err
+	}
+	// Один автор на несколько цитат кодируется одинаково, поэтому DTO общий.
+	details := make(map[int]*dto.Author, len(authors))
+	for id, author := range authors {
+		details[id] = authorToDTO(author)
+	}
+	for _, quote := range quotes {
+		quote.AuthorDetails = details[quote.AuthorID]
+	}
+	return nil
+}
+
+// authorError - Ошибка репозитория в ошибку сервиса. Неоднозначное имя и занятый псевдоним
+// сообщают клиенту, какие авторы мешают.
+func (qs *QuoteService) authorError(ctx context.Context, msg, ref string, err error) error {
+	var ambiguous *repository.AmbiguousAuthorError
+	var taken *repository.AliasTakenError
+	switch {
+	case errors.As(err, &ambiguous):
+		err = ErrAuthorAmbiguous.With(ambiguous.Name, joinIDs(ambiguous.IDs))
+	case errors.As(err, &taken):
+		err = ErrAliasTaken.With(taken.Alias, taken.AuthorID)
+	case errors.Is(err, repository.ErrAuthorNotFound):
+		err = ErrAuthorNotFound
+	case errors.Is(err, repository.ErrMergeSelf):
+		err = ErrValidation{NewErrInvalidField("into", "author.merge_self")}
+	case errors.Is(err, repository.ErrQuotaExceeded):
+		err = fmt.Errorf("%w %q", ErrQuotaExceeded, shared.TenantFromContext(ctx))
+	default:
+		slog.ErrorContext(ctx, msg, "author", ref, "error", err)
+		return err
+	}
+	slog.WarnContext(ctx, msg, "author", ref, "error", err)
+	return err
+}
+
+func joinIDs(ids []int) string {
+	out := make([]string, len(ids))
+	for i, id := range ids {
+		out[i] = strconv.Itoa(id)
+	}
+	return strings.Join(out, ", ")
+}
+
+// trimAuthorUpdate - Обрезает пробелы. Пустые псевдонимы и повторы без учёта регистра отбрасываются.
+func trimAuthorUpdate(update *dto.UpdateAuthor) {
+	aliases := update.Aliases[:0]
+	seen := make(map[string]bool, len(update.Aliases))
+	for _, alias := range update.Aliases {
+		alias = strings.TrimSpace(alias)
+		if key := models.NameKey(alias); key != "" && !seen[key] {
+			seen[key] = true
+			aliases = append(aliases, alias)
+		}
+	}
+	update.Aliases = aliases
+	update.FullName = strings.TrimSpace(update.FullName)
+	update.Nationality = strings.TrimSpace(update.Nationality)
+	update.Bio = strings.TrimSpace(update.Bio)
+	for i := range update.Links {
+		update.Links[i].Title = strings.TrimSpace(update.Links[i].Title)
+		update.Links[i].URL = strings.TrimSpace(update.Links[i].URL)
+	}
+}
+
+// validateAuthorName - Имя или псевдоним автора по правилам проверки тенанта. field - поле запроса.
+func (qs *QuoteService) validateAuthorName(ctx context.Context, field, name string) ErrValidation {
+	if name == "" {
+		return ErrValidation{NewErrInvalidField(field, "author.name_required")}
+	}
+	set := qs.rules.Load().Select(shared.TenantFromContext(ctx), shared.RequestMetaFromContext(ctx).Route)
+	var errs ErrValidation
+	for _, v := range set.Check("", name, validation.FieldAuthor) {
+		errs = append(errs, NewErrInvalidField(field, v.Key, v.Args...))
+	}
+	return errs
+}
+
+// validateAuthorUpdate - Все нарушения в сведениях об авторе сразу, как и при проверке цитаты.
+// Псевдонимы проверяются по тем же правилам, что и имя.
+func (qs *QuoteService) validateAuthorUpdate(ctx context.Context, update *dto.UpdateAuthor) ErrValidation {
+	var errs ErrValidation
+	if len(update.Aliases) > maxAuthorAliases {
+		errs = append(errs, NewErrInvalidField("aliases", "author.too_many_aliases", maxAuthorAliases))
+	}
+	for i, alias := range update.Aliases {
+		errs = append(errs, qs.validateAuthorName(ctx, fmt.Sprintf("aliases[%d]", i), alias)...)
+	}
+
+	tooLong := func(field, value string, limit int) {
+		if utf8.RuneCountInString(value) > limit {
+			errs = append(errs, NewErrInvalidField(field, "author.field_too_long", limit))
+		}
+	}
+	tooLong("full_name", update.FullName, maxAuthorFullName)
+	tooLong("nationality", update.Nationality, maxAuthorNationality)
+	tooLong("bio", update.Bio, maxAuthorBio)
+
+	maxYear := time.Now().Year()
+	checkYear := func(field string, year *int) {
+		if year != nil && (*year < minAuthorYear || *year > maxYear) {
+			errs = append(errs, NewErrInvalidField(field, "author.year_out_of_range", minAuthorYear, maxYear))
+		}
+	}
+	checkYear("birth_year", update.BirthYear)
+	checkYear("death_year", update.DeathYear)
+	if update.BirthYear != nil && update.DeathYear != nil && *update.DeathYear < *update.BirthYear {
+		errs = append(errs, NewErrInvalidField("death_year", "author.death_before_birth"))
+	}
+
+	if len(update.Links) > maxAuthorLinks {
+		errs = append(errs, NewErrInvalidField("links", "author.too_many_links", maxAuthorLinks))
+	}
+	for i, link := range update.Links {
+		field := fmt.Sprintf("links[%d]", i)
+		if u, err := url.Parse(link.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, NewErrInvalidField(field+".url", "author.link_invalid"))
+		}
+		tooLong(field+".title", link.Title, maxAuthorLinkTitle)
+	}
+	return errs
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"go-offline-test/internal/citation"
+	"go-offline-test/internal/models"
+	"go-offline-test/internal/repository"
+	"go-offline-test/internal/shared/dto"
+	"go-offline-test/internal/tracing"
+	"log/slog"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Ограничения источника цитаты.
+const (
+	maxSourceTitle     = 300
+	maxSourcePublisher = 200
+	maxSourcePages     = 20
+	// minSourceYear - Самый ранний год издания или выступления, как и для годов жизни автора.
+	minSourceYear = minAuthorYear
+)
+
+// sourcePages - Страница или диапазон страниц: «42», «42-45», «42–45».
+var sourcePages = regexp.MustCompile(`^(\d+)(?:\s*[-–]\s*(\d+))?$`)
+
+type ICitationService interface {
+	// Cite - Ссылка на опубликованную цитату в стиле style. Пустой style - APA.
+	Cite(ctx context.Context, quoteID int, style string) (*dto.Citation, error)
+	// AuthorCitations - Ссылки на все опубликованные цитаты автора по возрастанию id.
+	// ref - id автора числом или имя.
+	AuthorCitations(ctx context.Context, ref, style string) ([]*dto.Citation, error)
+}
+
+func (qs *QuoteService) Cite(ctx context.Context, quoteID int, style string) (*dto.Citation, error) {
+	ctx, span := tracing.Start(ctx, "QuoteService.Cite")
+	defer span.End()
+
+	style, err := citationStyle(style)
+	if err != nil {
+		return nil, err
+	}
+
+	repo, err := qs.repo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Цитата на модерации читателям не видна, сослаться на неё нельзя.
+	quote, err := repo.QuoteByID(ctx, quoteID)
+	if err == nil && !quote.Published() {
+		err = repository.ErrQuoteNotFound
+	}
+	if err != nil {
+		if errors.Is(err, repository.ErrQuoteNotFound) {
+			slog.WarnContext(ctx, "не удалось оформить ссылку на цитату", "quote_id", quoteID, "error", err)
+			return nil, ErrQuoteNotFound
+		}
+		slog.ErrorContext(ctx, "не удалось оформить ссылку на цитату", "quote_id", quoteID, "error", err)
+		return nil, err
+	}
+
+	authors, err := repo.Authors(ctx, []int{quote.AuthorID})
+	if err != nil {
+		slog.ErrorContext(ctx, "не удалось получить сведения об авторе", "author_id", quote.AuthorID, "error", err)
+		return nil, err
+	}
+	return cite(style, quote, citedName(quote.AuthorName, authors[quote.AuthorID]))
+}
+
+func (qs *QuoteService) AuthorCitations(ctx context.Context, ref, style string) ([]*dto.Citation, error) {
+	ctx, span := tracing.Start(ctx, "QuoteService.AuthorCitations")
+	defer span.End()
+
+	style, err := citationStyle(style)
+	if err != nil {
+		return nil, err
+	}
+
+	repo, err := qs.repo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	author, err := repo.Author(ctx, models.ParseAuthorRef(ref))
+	if err == nil && author.Published() == 0 {
+		err = repository.ErrAuthorNotFound
+	}
+	if err != nil {
+		return nil, qs.authorError(ctx, "не удалось оформить ссылки на цитаты автора", ref, err)
+	}
+
+	quotes := slices.DeleteFunc(slices.Clone(author.Quotes), func(q *models.Quote) bool { return !q.Published() })
+	slices.SortFunc(quotes, func(a, b *models.Quote) int { return a.ID - b.ID })
+	name := citedName(author.Name, author)
+	out := make([]*dto.Citation, 0, len(quotes))
+	for _, quote := range quotes {
+		c, err := cite(style, quote, name)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	span.SetAttr("citations", len(out))
+	return out, nil
+}
+
+// citationStyle - Стиль из запроса. Пустой - стиль по умолчанию.
+func citationStyle(style string) (string, error) {
+	style = strings.ToLower(strings.TrimSpace(style))
+	if style == "" {
+		return citation.Styles[0], nil
+	}
+	if !citation.Supported(style) {
+		return "", NewErrInvalidField("style", "request.style_invalid", style, strings.Join(citation.Styles, ", "))
+	}
+	return style, nil
+}
+
+// citedName - Имя автора в ссылке: полное, если оно заполнено в сведениях об авторе.
+func citedName(name string, author *models.Author) string {
+	if author != nil && author.FullName != "" {
+		return author.FullName
+	}
+	return name
+}
+
+func cite(style string, quote *models.Quote, author string) (*dto.Citation, error) {
+	text, err := citation.Format(style, citation.Work{QuoteID: quote.ID, Quote: quote.Text, Author: author, Source: quote.Source})
+	if err != nil {
+		return nil, err
+	}
+	return &dto.Citation{QuoteID: quote.ID, Style: style, Text: text}, nil
+}
+
+// trimSource - Обрезает пробелы. Вид источника приводится к нижнему регистру, пустой - other.
+func trimSource(source *dto.Source) {
+	source.Type = strings.ToLower(strings.TrimSpace(source.Type))
+	if source.Type == "" {
+		source.Type = models.SourceOther
+	}
+	source.Title = strings.TrimSpace(source.Title)
+	source.Publisher = strings.TrimSpace(source.Publisher)
+	source.Pages = strings.TrimSpace(source.Pages)
+	source.URL = strings.TrimSpace(source.URL)
+}
+
+// validateSource - Все нарушения в источнике цитаты сразу. Поля - с префиксом source.
+func validateSource(source *dto.Source) ErrValidation {
+	var errs ErrValidation
+	if !slices.Contains(models.SourceTypes, source.Type) {
+		errs = append(errs, NewErrInvalidField("source.type", "source.type_invalid", source.Type, strings.Join(models.SourceTypes, ", ")))
+	}
+
+	if source.Title == "" {
+		errs = append(errs, NewErrInvalidField("source.title", "source.title_required"))
+	}
+	tooLong := func(field, value string, limit int) {
+		if utf8.RuneCountInString(value) > limit {
+			errs = append(errs, NewErrInvalidField(field, "source.field_too_long", limit))
+		}
+	}
+	tooLong("source.title", source.Title, maxSourceTitle)
+	tooLong("source.publisher", source.Publisher, maxSourcePublisher)
+
+	if maxYear := time.Now().Year(); source.Year != nil && (*source.Year < minSourceYear || *source.Year > maxYear) {
+		errs = append(errs, NewErrInvalidField("source.year", "source.year_out_of_range", minSourceYear, maxYear))
+	}
+
+	if source.Pages != "" && !validPages(source.Pages) {
+		errs = append(errs, NewErrInvalidField("source.pages", "source.pages_invalid", maxSourcePages))
+	}
+
+	if source.URL != "" {
+		if u, err := url.Parse(source.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, NewErrInvalidField("source.url", "source.url_invalid"))
+		}
+	} else if source.Type == models.SourceWeb {
+		errs = append(errs, NewErrInvalidField("source.url", "source.url_required"))
+	}
+	return errs
+}
+
+// validPages - Страница или диапазон, в котором первая страница не больше последней. Нумерация с 1.
+func validPages(pages string) bool {
+	m := sourcePages.FindStringSubmatch(pages)
+	if len(pages) > maxSourcePages || m == nil {
+		return false
+	}
+	first, _ := strconv.Atoi(m[1])
+	if m[2] == "" {
+		return first > 0
+	}
+	last, _ := strconv.Atoi(m[2])
+	return first > 0 && first <= last
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-offline-test/internal/shared"
+	"go-offline-test/internal/shared/dto"
+	"go-offline-test/internal/shared/dto/config"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+)
+
+type IConfigService interface {
+	// Reload - Перечитывает настройки и применяет те, что меняются без перезапуска.
+	Reload(ctx context.Context) (*dto.ConfigReload, error)
+}
+
+// ConfigService - Действующие настройки и их перезагрузка по SIGHUP или через API.
+type ConfigService struct {
+	current atomic.Pointer[config.Config]
+	// load - Собирает настройки заново теми же слоями, что и при запуске.
+	load func() (*config.Config, error)
+	// preparers - Готовят компоненты, которые читают настройки на лету, к новым значениям.
+	preparers []Preparer
+	// mu - Не даёт двум перезагрузкам перемешать изменения.
+	mu sync.Mutex
+}
+
+func NewConfigService(conf *config.Config, load func() (*config.Config, error)) *ConfigService {
+	cs := &ConfigService{load: load}
+	cs.current.Store(conf)
+	return cs
+}
+
+// Preparer - Собирает и проверяет всё, что нужно компоненту для новых настроек, ничего не меняя.
+// Возвращённая apply только подменяет готовые объекты и не может завершиться ошибкой.
+type Preparer func(conf *config.Config) (apply func(), err error)
+
+// OnReload - Регистрирует подготовку компонента к новым настройкам. Если хоть одна подготовка
+// вернула ошибку, новые настройки не получает ни один компонент.
+func (cs *ConfigService) OnReload(prepare Preparer) {
+	cs.preparers = append(cs.preparers, prepare)
+}
+
+// Current - Действующие настройки.
+func (cs *ConfigService) Current() *config.Config {
+	return cs.current.Load()
+}
+
+func (cs *ConfigService) Reload(ctx context.Context) (*dto.ConfigReload, error) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	next, err := cs.load()
+	if err != nil {
+		slog.ErrorContext(ctx, "новые настройки отклонены, действуют прежние", "error", err)
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+
+	merged, report := shared.ReloadConfig(cs.current.Load(), next)
+	// Подготовка вызывается и без изменённых ключей: она может перечитывать файлы, на которые
+	// ссылаются настройки, например validation.rules_file. Новые объекты подменяются только после того,
+	// как подготовились все компоненты, - иначе часть из них осталась бы с новыми настройками, часть со старыми.
+	applies := make([]func(), 0, len(cs.preparers))
+	var errs []error
+	for _, prepare := range cs.preparers {
+		apply, err := prepare(merged)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		applies = append(applies, apply)
+	}
+	if len(errs) > 0 {
+		err := errors.Join(errs...)
+		slog.ErrorContext(ctx, "новые настройки отклонены, действуют прежние", "error", err)
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+	for _, apply := range applies {
+		apply()
+	}
+	cs.current.Store(merged)
+
+	slog.InfoContext(ctx, "настройки перезагружены", "applied", changeKeys(report.Applied))
+	if len(report.RestartRequired) > 0 {
+		slog.WarnContext(ctx, "часть изменений вступит в силу только после перезапуска", "keys", changeKeys(report.RestartRequired))
+	}
+	return report, nil
+}
+
+func changeKeys(changes []*dto.ConfigChange) []string {
+	keys := make([]string, 0, len(changes))
+	for _, c := range changes {
+		keys = append(keys, c.Key)
+	}
+	return keys
+}
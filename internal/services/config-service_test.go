@@ -0,0 +1,66 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"go-offline-test/internal/services"
+	"go-offline-test/internal/shared"
+	"go-offline-test/internal/shared/dto/config"
+	"reflect"
+	"testing"
+)
+
+// TestConfigServiceReloadAtomic - Новые настройки получают все компоненты разом или ни один.
+func TestConfigServiceReloadAtomic(t *testing.T) {
+	current := shared.DefaultConfig()
+	next := shared.DefaultConfig()
+	next.Validation.QuoteMaxLength = 300
+
+	var events []string
+	prepare := func(name string, err error) services.Preparer {
+		return func(*config.Config) (func(), error) {
+			events = append(events, "prepare "+name)
+			if err != nil {
+				return nil, err
+			}
+			return func() { events = append(events, "apply "+name) }, nil
+		}
+	}
+
+	t.Run("ошибка подготовки", func(t *testing.T) {
+		events = nil
+		errRules := errors.New("файл правил испорчен")
+		cs := services.NewConfigService(current, func() (*config.Config, error) { return next, nil })
+		cs.OnReload(prepare("log", nil))
+		cs.OnReload(prepare("rules", errRules))
+		cs.OnReload(prepare("filters", nil))
+
+		_, err := cs.Reload(context.Background())
+		if !errors.Is(err, services.ErrInvalidConfig) || !errors.Is(err, errRules) {
+			t.Errorf("Reload() = %v, ожидалась %v с причиной", err, services.ErrInvalidConfig)
+		}
+		if want := []string{"prepare log", "prepare rules", "prepare filters"}; !reflect.DeepEqual(events, want) {
+			t.Errorf("вызовы %v, ожидалось %v", events, want)
+		}
+		if cs.Current() != current {
+			t.Error("после отклонённой перезагрузки настройки изменились")
+		}
+	})
+
+	t.Run("успех", func(t *testing.T) {
+		events = nil
+		cs := services.NewConfigService(current, func() (*config.Config, error) { return next, nil })
+		cs.OnReload(prepare("log", nil))
+		cs.OnReload(prepare("rules", nil))
+
+		if _, err := cs.Reload(context.Background()); err != nil {
+			t.Fatalf("Reload(): %v", err)
+		}
+		if want := []string{"prepare log", "prepare rules", "apply log", "apply rules"}; !reflect.DeepEqual(events, want) {
+			t.Errorf("вызовы %v, ожидалось %v", events, want)
+		}
+		if got := cs.Current().Validation.QuoteMaxLength; got != 300 {
+			t.Errorf("validation.quote_max_length = %d, ожидалось 300", got)
+		}
+	})
+}
@@ -1,28 +1,99 @@
 package services
 
-import "errors"
+import (
+	"go-offline-test/internal/i18n"
+	"strings"
+)
 
 var (
-	ErrNoQuotesAvailable    = errors.New("в хранилище нет доступных цитат")
-	ErrAuthorNotFound       = errors.New("автор не найден")
-	ErrQuoteNotFound        = errors.New("цитата не найдена")
-	ErrNoQuotesByThisAuthor = errors.New("цитаты этого автора не найдены")
-	ErrQuoteAlreadyExist    = errors.New("цитата уже существует")
-	ErrAddQuote             = errors.New("ошибка создания цитаты")
-	ErrGetQuotes            = errors.New("ошибка получения списка цитат")
-	ErrGetQuote             = errors.New("ошибка получения цитаты")
-	ErrGetQuoteByAuthor     = errors.New("ошибка получения цитаты по автору")
+	ErrNoQuotesAvailable    = i18n.NewError("quotes.none")
+	ErrAuthorNotFound       = i18n.NewError("author.not_found")
+	ErrQuoteNotFound        = i18n.NewError("quote.not_found")
+	ErrNoQuotesByThisAuthor = i18n.NewError("author.no_quotes")
+	ErrQuoteAlreadyExist    = i18n.NewError("quote.exists")
+	ErrQuoteNotPending      = i18n.NewError("quote.not_pending")
+	ErrAuthorAmbiguous      = i18n.NewError("author.ambiguous")
+	ErrAliasTaken           = i18n.NewError("author.alias_taken")
+	ErrMergeTargetNotFound  = i18n.NewError("author.merge_target_not_found")
+	ErrAddQuote             = i18n.NewError("quote.add_failed")
+	ErrGetQuotes            = i18n.NewError("quotes.list_failed")
+	ErrGetQuote             = i18n.NewError("quote.get_failed")
+	ErrGetQuoteByAuthor     = i18n.NewError("quotes.by_author_failed")
+	ErrTenantNotFound       = i18n.NewError("tenant.not_found")
+	ErrTenantAlreadyExist   = i18n.NewError("tenant.exists")
+	ErrDefaultTenantDelete  = i18n.NewError("tenant.default_delete")
+	ErrQuotaExceeded        = i18n.NewError("quota.exceeded")
+	ErrInvalidConfig        = i18n.NewError("config.rejected")
 )
 
+// ErrInvalidName - Данные запроса не прошли проверку. Отдаётся клиенту с кодом VALIDATION_FAILED.
 type ErrInvalidName struct {
-	Code    int
-	Message string
+	// Message - Сообщение из каталога, переводится на язык клиента.
+	Message *i18n.Error
+	// Field - Поле запроса, которое не прошло проверку. Пусто, если ошибка не относится к одному полю.
+	Field string
 }
 
 func (ri *ErrInvalidName) Error() string {
-	return ri.Message
+	return ri.Message.Error()
+}
+
+// Localize - Сообщение на языке клиента.
+func (ri *ErrInvalidName) Localize(langs []i18n.Lang) string {
+	return ri.Message.Localize(langs)
+}
+
+// NewErrInvalidData - Ошибка валидации с сообщением по ключу каталога.
+func NewErrInvalidData(key string, args ...any) *ErrInvalidName {
+	return &ErrInvalidName{Message: i18n.Errorf(key, args...)}
+}
+
+// NewErrInvalidField - Ошибка валидации конкретного поля запроса.
+func NewErrInvalidField(field, key string, args ...any) *ErrInvalidName {
+	return &ErrInvalidName{Message: i18n.Errorf(key, args...), Field: field}
+}
+
+// ErrContentRejected - Цитату отклонил фильтр содержимого. Отдаётся клиенту с кодом CONTENT_REJECTED.
+type ErrContentRejected struct {
+	// Message - Сообщение из каталога, переводится на язык клиента.
+	Message *i18n.Error
+	// Field - Поле, в котором сработал фильтр.
+	Field string
+	// Filter - Имя фильтра.
+	Filter string
+}
+
+func (rc *ErrContentRejected) Error() string {
+	return rc.Message.Error()
+}
+
+// Localize - Сообщение на языке клиента.
+func (rc *ErrContentRejected) Localize(langs []i18n.Lang) string {
+	return rc.Message.Localize(langs)
+}
+
+// ErrValidation - Все нарушения правил в данных запроса. Клиент получает их одним ответом
+// с кодом VALIDATION_FAILED, по одному элементу на нарушение.
+type ErrValidation []*ErrInvalidName
+
+func (ev ErrValidation) Error() string {
+	return ev.Localize(nil)
+}
+
+// Localize - Сообщения всех нарушений на языке клиента через "; ".
+func (ev ErrValidation) Localize(langs []i18n.Lang) string {
+	msgs := make([]string, 0, len(ev))
+	for _, e := range ev {
+		msgs = append(msgs, e.Localize(langs))
+	}
+	return strings.Join(msgs, "; ")
 }
 
-func NewErrInvalidData(code int, message string) *ErrInvalidName {
-	return &ErrInvalidName{Code: code, Message: message}
+// Unwrap - Нарушения по отдельности, чтобы errors.As находил ErrInvalidName.
+func (ev ErrValidation) Unwrap() []error {
+	errs := make([]error, 0, len(ev))
+	for _, e := range ev {
+		errs = append(errs, e)
+	}
+	return errs
 }
@@ -2,13 +2,23 @@ package services
 
 import (
 	"context"
-	"go-offline-test/internal/shared/dto"
+	"go-offline-test/internal/models"
+	"go-offline-test/internal/repository"
 )
 
 type IQuoteRepository interface {
-	AddQuote(ctx context.Context, quote *dto.Quote) error
-	Quotes(ctx context.Context) ([]*dto.Quote, error)
-	RandomQuote(ctx context.Context) (*dto.Quote, error)
-	QuotesByAuthor(ctx context.Context, authorName string) ([]*dto.Quote, error)
-	DeleteQuote(ctx context.Context, idQuote int) error
+	AddQuote(ctx context.Context, quote *models.Quote) error
+	Quotes(ctx context.Context) ([]*models.Quote, error)
+	RandomQuote(ctx context.Context) (*models.Quote, error)
+	QuoteByID(ctx context.Context, idQuote int) (*models.Quote, error)
+	QuotesByAuthor(ctx context.Context, authorName string) ([]*models.Quote, error)
+	DeleteQuote(ctx context.Context, idQuote int) (*models.Quote, error)
+	PendingQuotes(ctx context.Context) ([]*models.Quote, error)
+	Approve(ctx context.Context, idQuote int, moderator string) (before, after *models.Quote, err error)
+	Reject(ctx context.Context, idQuote int, moderator, reason string) (before, after *models.Quote, err error)
+	Author(ctx context.Context, ref models.AuthorRef) (*models.Author, error)
+	Authors(ctx context.Context, ids []int) (map[int]*models.Author, error)
+	CreateAuthor(ctx context.Context, name string, meta models.AuthorMeta) (*models.Author, error)
+	UpdateAuthor(ctx context.Context, ref models.AuthorRef, meta models.AuthorMeta) (before, after *models.Author, err error)
+	MergeAuthors(ctx context.Context, from, into models.AuthorRef) (*repository.Merge, error)
 }
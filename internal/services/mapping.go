@@ -0,0 +1,86 @@
+package services
+
+import (
+	"go-offline-test/internal/models"
+	"go-offline-test/internal/shared/dto"
+	"slices"
+)
+
+// quoteFromDTO - Модель новой цитаты из запроса. Берутся только поля, которые задаёт клиент,
+// остальные проставляет сервис.
+func quoteFromDTO(quote *dto.Quote) *models.Quote {
+	model := &models.Quote{Text: quote.Text, AuthorID: quote.AuthorID, AuthorName: quote.AuthorName}
+	if src := quote.Source; src != nil {
+		model.Source = &models.Source{Type: src.Type, Title: src.Title, Publisher: src.Publisher, Year: src.Year, Pages: src.Pages, URL: src.URL}
+	}
+	return model
+}
+
+// quoteToDTO - Цитата для ответа. nil остаётся nil: так удобнее писать в журнал аудита.
+func quoteToDTO(quote *models.Quote) *dto.Quote {
+	if quote == nil {
+		return nil
+	}
+	out := &dto.Quote{
+		ID:           quote.ID,
+		Text:         quote.Text,
+		AuthorName:   quote.AuthorName,
+		AuthorID:     quote.AuthorID,
+		CreatedBy:    quote.CreatedBy,
+		Status:       quote.Status,
+		CreatedAt:    quote.CreatedAt,
+		ModeratedBy:  quote.ModeratedBy,
+		ModeratedAt:  quote.ModeratedAt,
+		RejectReason: quote.RejectReason,
+		Flags:        slices.Clone(quote.Flags),
+	}
+	if src := quote.Source; src != nil {
+		out.Source = &dto.Source{Type: src.Type, Title: src.Title, Publisher: src.Publisher, Year: src.Year, Pages: src.Pages, URL: src.URL}
+	}
+	return out
+}
+
+func quotesToDTO(quotes []*models.Quote) []*dto.Quote {
+	out := make([]*dto.Quote, 0, len(quotes))
+	for _, quote := range quotes {
+		out = append(out, quoteToDTO(quote))
+	}
+	return out
+}
+
+func authorToDTO(author *models.Author) *dto.Author {
+	out := &dto.Author{
+		ID:          author.ID,
+		Name:        author.Name,
+		FullName:    author.FullName,
+		BirthYear:   author.BirthYear,
+		DeathYear:   author.DeathYear,
+		Aliases:     slices.Clone(author.Aliases),
+		Nationality: author.Nationality,
+		Bio:         author.Bio,
+		Quotes:      author.Published(),
+	}
+	for _, link := range author.Links {
+		out.Links = append(out.Links, dto.AuthorLink{Title: link.Title, URL: link.URL})
+	}
+	if !author.UpdatedAt.IsZero() {
+		updated := author.UpdatedAt
+		out.UpdatedAt = &updated
+	}
+	return out
+}
+
+func authorMetaFromDTO(update *dto.UpdateAuthor) models.AuthorMeta {
+	meta := models.AuthorMeta{
+		FullName:    update.FullName,
+		BirthYear:   update.BirthYear,
+		DeathYear:   update.DeathYear,
+		Aliases:     update.Aliases,
+		Nationality: update.Nationality,
+		Bio:         update.Bio,
+	}
+	for _, link := range update.Links {
+		meta.Links = append(meta.Links, models.Link{Title: link.Title, URL: link.URL})
+	}
+	return meta
+}
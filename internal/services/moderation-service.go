@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"go-offline-test/internal/audit"
+	"go-offline-test/internal/auth"
+	"go-offline-test/internal/repository"
+	"go-offline-test/internal/shared/dto"
+	"go-offline-test/internal/tracing"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxRejectReason - Предельная длина причины отклонения в символах.
+const maxRejectReason = 500
+
+type IModerationService interface {
+	// ModerationQueue - Получает цитаты тенанта, ожидающие модерации, и время их ожидания.
+	ModerationQueue(ctx context.Context) (*dto.ModerationQueue, error)
+	// ApproveQuote - Публикует цитату с модерации.
+	ApproveQuote(ctx context.Context, quoteID int) (*dto.Quote, error)
+	// RejectQuote - Отклоняет цитату с модерации и удаляет её из коллекции.
+	RejectQuote(ctx context.Context, quoteID int, reason string) (*dto.Quote, error)
+}
+
+func (qs *QuoteService) ModerationQueue(ctx context.Context) (*dto.ModerationQueue, error) {
+	ctx, span := tracing.Start(ctx, "QuoteService.ModerationQueue")
+	defer span.End()
+
+	repo, err := qs.repo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	quotes, err := repo.PendingQuotes(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "не удалось получить очередь модерации", "error", err)
+		return nil, err
+	}
+
+	now := time.Now()
+	queue := &dto.ModerationQueue{Pending: len(quotes), Items: make([]*dto.PendingQuote, 0, len(quotes))}
+	for _, quote := range quotes {
+		wait := int64(now.Sub(quote.CreatedAt).Seconds())
+		queue.Items = append(queue.Items, &dto.PendingQuote{Quote: *quoteToDTO(quote), WaitSeconds: wait})
+		queue.OldestWaitSeconds = max(queue.OldestWaitSeconds, wait)
+	}
+	return queue, nil
+}
+
+func (qs *QuoteService) ApproveQuote(ctx context.Context, quoteID int) (*dto.Quote, error) {
+	ctx, span := tracing.Start(ctx, "QuoteService.ApproveQuote")
+	defer span.End()
+
+	repo, err := qs.repo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	before, after, err := repo.Approve(ctx, quoteID, moderator(ctx))
+	if err != nil {
+		return nil, moderationError(ctx, "не удалось одобрить цитату", quoteID, err)
+	}
+	slog.InfoContext(ctx, "цитата одобрена", "quote_id", quoteID, "wait", after.ModeratedAt.Sub(after.CreatedAt))
+	qs.record(ctx, audit.OpQuoteApprove, quoteToDTO(before), quoteToDTO(after))
+
+	return quoteToDTO(after), nil
+}
+
+func (qs *QuoteService) RejectQuote(ctx context.Context, quoteID int, reason string) (*dto.Quote, error) {
+	ctx, span := tracing.Start(ctx, "QuoteService.RejectQuote")
+	defer span.End()
+
+	reason = strings.TrimSpace(reason)
+	switch {
+	case reason == "":
+		err := NewErrInvalidField("reason", "moderation.reason_empty")
+		slog.WarnContext(ctx, "ошибка валидации причины отклонения", "error", err)
+		return nil, err
+	case utf8.RuneCountInString(reason) > maxRejectReason:
+		err := NewErrInvalidField("reason", "moderation.reason_too_long", maxRejectReason)
+		slog.WarnContext(ctx, "ошибка валидации причины отклонения", "error", err)
+		return nil, err
+	}
+
+	repo, err := qs.repo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	before, after, err := repo.Reject(ctx, quoteID, moderator(ctx), reason)
+	if err != nil {
+		return nil, moderationError(ctx, "не удалось отклонить цитату", quoteID, err)
+	}
+	slog.InfoContext(ctx, "цитата отклонена", "quote_id", quoteID, "reason", reason)
+	qs.record(ctx, audit.OpQuoteReject, quoteToDTO(before), quoteToDTO(after))
+
+	return quoteToDTO(after), nil
+}
+
+// moderator - Кто принимает решение: subject из токена. Без авторизации - пусто.
+func moderator(ctx context.Context) string {
+	if principal, ok := auth.PrincipalFromContext(ctx); ok {
+		return principal.Subject
+	}
+	return ""
+}
+
+func moderationError(ctx context.Context, msg string, quoteID int, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrQuoteNotFound):
+		slog.WarnContext(ctx, msg, "quote_id", quoteID, "error", err)
+		return ErrQuoteNotFound
+	case errors.Is(err, repository.ErrQuoteNotPending):
+		slog.WarnContext(ctx, msg, "quote_id", quoteID, "error", err)
+		return ErrQuoteNotPending
+	default:
+		slog.ErrorContext(ctx, msg, "quote_id", quoteID, "error", err)
+		return err
+	}
+}
@@ -4,11 +4,22 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"go-offline-test/internal/audit"
+	"go-offline-test/internal/auth"
+	"go-offline-test/internal/filter"
+	"go-offline-test/internal/i18n"
+	"go-offline-test/internal/logging"
+	"go-offline-test/internal/models"
 	"go-offline-test/internal/repository"
+	"go-offline-test/internal/shared"
 	"go-offline-test/internal/shared/dto"
-	"log"
-	"strings"
-	"unicode"
+	"go-offline-test/internal/shared/dto/config"
+	"go-offline-test/internal/tracing"
+	"go-offline-test/internal/validation"
+	"log/slog"
+	"slices"
+	"sync/atomic"
+	"time"
 )
 
 type IQuoteService interface {
@@ -23,162 +34,289 @@ type IQuoteService interface {
 	// DeleteQuote - Удаляет цитату.
 	DeleteQuote(ctx context.Context, quoteID int) error
 	// ValidateData - Валидирует данные.
-	ValidateData(text, authorName, mode string) error
+	ValidateData(ctx context.Context, text, authorName, mode string) error
 }
 
 type QuoteService struct {
-	repo *repository.QuoteRepository
+	tenants *repository.TenantRegistry
+	audit   *audit.Log
+	// rules - Наборы правил проверки, меняются при перезагрузке настроек.
+	rules atomic.Pointer[validation.Rules]
+	// filters - Цепочка фильтров содержимого, меняется при перезагрузке настроек.
+	filters atomic.Pointer[filter.Pipeline]
+	// moderation - Для каких тенантов новые цитаты ждут модерации.
+	moderation atomic.Pointer[config.ModerationConfig]
 }
 
-func NewQuoteService(repo *repository.QuoteRepository) *QuoteService {
-	return &QuoteService{repo: repo}
+func NewQuoteService(tenants *repository.TenantRegistry, auditLog *audit.Log, rules *validation.Rules, filters *filter.Pipeline, moderation config.ModerationConfig) *QuoteService {
+	qs := &QuoteService{tenants: tenants, audit: auditLog}
+	qs.SetRules(rules)
+	qs.SetFilters(filters)
+	qs.SetModeration(moderation)
+	return qs
+}
+
+// SetRules - Меняет наборы правил для следующих проверок.
+func (qs *QuoteService) SetRules(rules *validation.Rules) {
+	qs.rules.Store(rules)
+}
+
+// SetFilters - Меняет цепочку фильтров для следующих цитат.
+func (qs *QuoteService) SetFilters(filters *filter.Pipeline) {
+	qs.filters.Store(filters)
+}
+
+// SetModeration - Меняет список тенантов с модерацией. Цитаты, уже ждущие решения, остаются в очереди.
+func (qs *QuoteService) SetModeration(moderation config.ModerationConfig) {
+	qs.moderation.Store(&moderation)
+}
+
+// moderated - Ждут ли новые цитаты тенанта модерации.
+func (qs *QuoteService) moderated(tenant string) bool {
+	m := qs.moderation.Load()
+	return m.Enabled || slices.Contains(m.Tenants, tenant)
+}
+
+// record - Пишет операцию в журнал аудита. Сбой журнала не отменяет уже выполненную операцию.
+func (qs *QuoteService) record(ctx context.Context, op string, before, after *dto.Quote) {
+	qs.recordChange(ctx, op, audit.Change{Before: before, After: after})
+}
+
+// recordAuthor - Пишет в журнал аудита операцию с автором.
+func (qs *QuoteService) recordAuthor(ctx context.Context, op string, before, after *dto.Author) {
+	qs.recordChange(ctx, op, audit.Change{AuthorBefore: before, AuthorAfter: after})
+}
+
+func (qs *QuoteService) recordChange(ctx context.Context, op string, change audit.Change) {
+	if err := qs.audit.Record(ctx, op, change); err != nil {
+		slog.ErrorContext(ctx, "не удалось записать операцию в журнал аудита", "operation", op, "error", err)
+	}
+}
+
+// repo - Возвращает репозиторий тенанта, указанного в контексте запроса.
+func (qs *QuoteService) repo(ctx context.Context) (*repository.QuoteRepository, error) {
+	name := shared.TenantFromContext(ctx)
+	tenant, err := qs.tenants.Get(ctx, name)
+	if err != nil {
+		if errors.Is(err, repository.ErrTenantNotFound) {
+			slog.WarnContext(ctx, "тенант не найден", "tenant", name)
+			return nil, ErrTenantNotFound
+		}
+		return nil, err
+	}
+	return tenant.Repo, nil
 }
 
 func (qs *QuoteService) AddQuote(ctx context.Context, quote *dto.Quote) error {
-	if err := qs.repo.AddQuote(ctx, quote); err != nil {
+	ctx, span := tracing.Start(ctx, "QuoteService.AddQuote")
+	defer span.End()
+
+	// Источник необязателен, но если указан - проверяется целиком.
+	if quote.Source != nil {
+		trimSource(quote.Source)
+		if errs := validateSource(quote.Source); len(errs) > 0 {
+			slog.WarnContext(ctx, "ошибка валидации источника цитаты", "error", errs)
+			return errs
+		}
+	}
+
+	// Автора записи, время и состояние проставляем сами, из тела запроса берутся текст, автор и источник.
+	model := quoteFromDTO(quote)
+	if principal, ok := auth.PrincipalFromContext(ctx); ok {
+		model.CreatedBy = principal.Subject
+	}
+	model.CreatedAt = time.Now().UTC()
+	model.Status = models.StatusApproved
+	if qs.moderated(shared.TenantFromContext(ctx)) {
+		model.Status = models.StatusPending
+	}
+
+	if err := qs.filter(ctx, model); err != nil {
+		return err
+	}
+
+	repo, err := qs.repo(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := repo.AddQuote(ctx, model); err != nil {
 		if errors.Is(err, repository.ErrQuoteAlreadyExist) {
-			log.Printf("WARN: не удалось создать цитату. Ошибка: %v", err)
+			slog.WarnContext(ctx, "не удалось создать цитату", "error", err)
 			return ErrQuoteAlreadyExist
 		}
-		log.Printf("ERROR: не удалось создать цитату. Ошибка: %v", err)
-		return fmt.Errorf(ErrAddQuote.Error(), ": %s", err)
+		// Неоднозначное имя и неизвестный author_id - ошибки ссылки на автора, как в запросах к /authors.
+		var ambiguous *repository.AmbiguousAuthorError
+		if errors.As(err, &ambiguous) || errors.Is(err, repository.ErrAuthorNotFound) || errors.Is(err, repository.ErrQuotaExceeded) {
+			return qs.authorError(ctx, "не удалось создать цитату", model.AuthorRef().String(), err)
+		}
+		slog.ErrorContext(ctx, "не удалось создать цитату", "error", err)
+		return fmt.Errorf("%w: %w", ErrAddQuote, err)
+	}
+	*quote = *quoteToDTO(model)
+	slog.InfoContext(ctx, "цитата создана", "quote_id", quote.ID, logging.Quote("quote", quote.Text), "author", quote.AuthorName, "status", quote.Status)
+	qs.record(ctx, audit.OpQuoteAdd, nil, quote)
+
+	return nil
+}
+
+// filter - Пропускает проверенную цитату через фильтры содержимого. Исправленный текст проверяется
+// заново: после удаления ссылок цитата может оказаться короче допустимого.
+func (qs *QuoteService) filter(ctx context.Context, quote *models.Quote) error {
+	res := qs.filters.Load().Run(quote.Text, quote.AuthorName)
+	if hit := res.Rejected; hit != nil {
+		err := &ErrContentRejected{Message: i18n.Errorf(hit.Key), Field: hit.Field, Filter: hit.Filter}
+		slog.WarnContext(ctx, "цитата отклонена фильтром", "filter", hit.Filter, "field", hit.Field, logging.Quote("quote", quote.Text), "author", quote.AuthorName)
+		return err
+	}
+
+	if len(res.Rewritten) > 0 {
+		for _, hit := range res.Rewritten {
+			slog.InfoContext(ctx, "цитата исправлена фильтром", "filter", hit.Filter, "field", hit.Field)
+		}
+		quote.Text, quote.AuthorName = res.Text, res.Author
+		mode := "quote"
+		if quote.AuthorName == "" {
+			mode = "text"
+		}
+		if err := qs.ValidateData(ctx, quote.Text, quote.AuthorName, mode); err != nil {
+			return err
+		}
 	}
 
+	if flags := res.Flags(); len(flags) > 0 {
+		slog.InfoContext(ctx, "цитата отправлена на модерацию фильтром", "filters", flags)
+		quote.Status = models.StatusPending
+		quote.Flags = flags
+	}
 	return nil
 }
 
 func (qs *QuoteService) ListQuotes(ctx context.Context) ([]*dto.Quote, error) {
-	quotes, err := qs.repo.Quotes(ctx)
+	ctx, span := tracing.Start(ctx, "QuoteService.ListQuotes")
+	defer span.End()
+
+	repo, err := qs.repo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	quotes, err := repo.Quotes(ctx)
 	if err != nil {
 		if errors.Is(err, repository.ErrQuotesNotFound) {
-			log.Printf("WARN: не удалось получить список цитат из памяти. Ошибка: %v", err)
+			slog.WarnContext(ctx, "не удалось получить список цитат", "error", err)
 			return nil, ErrNoQuotesAvailable
 		}
-		log.Printf("ERROR: не удалось получить список цитат из памяти. Ошибка: %v", err)
-		return nil, fmt.Errorf(ErrGetQuotes.Error(), ": %s", err)
+		slog.ErrorContext(ctx, "не удалось получить список цитат", "error", err)
+		return nil, fmt.Errorf("%w: %w", ErrGetQuotes, err)
 	}
 
-	return quotes, nil
+	return quotesToDTO(quotes), nil
 }
 
 func (qs *QuoteService) RandomQuote(ctx context.Context) (*dto.Quote, error) {
-	quote, err := qs.repo.RandomQuote(ctx)
+	ctx, span := tracing.Start(ctx, "QuoteService.RandomQuote")
+	defer span.End()
+
+	repo, err := qs.repo(ctx)
 	if err != nil {
+		return nil, err
+	}
 
-		if errors.Is(err, repository.ErrQuoteNotFound) {
-			log.Printf("WARN: не удалось получить рандомную цитату из памяти. Ошибка: %v", err)
+	quote, err := repo.RandomQuote(ctx)
+	if err != nil {
+		if errors.Is(err, repository.ErrQuotesNotFound) {
+			slog.WarnContext(ctx, "не удалось получить случайную цитату", "error", err)
 			return nil, ErrNoQuotesAvailable
 		}
-		log.Printf("ERROR: не удалось получить рандомную цитату из памяти. Ошибка: %v", err)
-		return nil, fmt.Errorf(ErrGetQuote.Error(), ": %s", err)
+		slog.ErrorContext(ctx, "не удалось получить случайную цитату", "error", err)
+		return nil, fmt.Errorf("%w: %w", ErrGetQuote, err)
 	}
 
-	return quote, nil
+	return quoteToDTO(quote), nil
 }
 
 func (qs *QuoteService) QuotesByAuthor(ctx context.Context, authorName string) ([]*dto.Quote, error) {
-	quote, err := qs.repo.QuotesByAuthor(ctx, authorName)
+	ctx, span := tracing.Start(ctx, "QuoteService.QuotesByAuthor")
+	defer span.End()
+
+	repo, err := qs.repo(ctx)
 	if err != nil {
-		switch {
+		return nil, err
+	}
 
+	quotes, err := repo.QuotesByAuthor(ctx, authorName)
+	if err != nil {
+		var ambiguous *repository.AmbiguousAuthorError
+		switch {
+		case errors.As(err, &ambiguous):
+			return nil, qs.authorError(ctx, "не удалось получить цитаты автора", authorName, err)
 		case errors.Is(err, repository.ErrAuthorNotFound):
-			log.Printf("WARN: не удалось получить список цитат по автору из памяти. Ошибка: %v", err)
+			slog.WarnContext(ctx, "не удалось получить цитаты автора", "author", authorName, "error", err)
 			return nil, ErrAuthorNotFound
 		case errors.Is(err, repository.ErrAuthorQuotesNotFound):
-			log.Printf("WARN: не удалось получить список цитат по автору из памяти. Ошибка: %v", err)
+			slog.WarnContext(ctx, "не удалось получить цитаты автора", "author", authorName, "error", err)
 			return nil, ErrNoQuotesByThisAuthor
 		default:
-			log.Printf("ERROR: не удалось получить список цитат по автору из памяти. Ошибка: %v", err)
-			return nil, fmt.Errorf(ErrGetQuoteByAuthor.Error(), ": %s", err)
+			slog.ErrorContext(ctx, "не удалось получить цитаты автора", "author", authorName, "error", err)
+			return nil, fmt.Errorf("%w: %w", ErrGetQuoteByAuthor, err)
 		}
 	}
 
-	return quote, nil
+	return quotesToDTO(quotes), nil
 }
 
 func (qs *QuoteService) DeleteQuote(ctx context.Context, quoteID int) error {
-	if err := qs.repo.DeleteQuote(ctx, quoteID); err != nil {
-		if errors.Is(err, repository.ErrQuotesNotFound) {
-			log.Printf("WARN: не удалось удалить цитату по id=%d из памяти. Ошибка: %v", err, quoteID)
+	ctx, span := tracing.Start(ctx, "QuoteService.DeleteQuote")
+	defer span.End()
+
+	repo, err := qs.repo(ctx)
+	if err != nil {
+		return err
+	}
+
+	before, err := repo.DeleteQuote(ctx, quoteID)
+	if err != nil {
+		if errors.Is(err, repository.ErrQuoteNotFound) {
+			slog.WarnContext(ctx, "не удалось удалить цитату", "quote_id", quoteID, "error", err)
 			return ErrQuoteNotFound
 		}
-		log.Printf("WARN: не удалось удалить цитату по id=%d из памяти. Ошибка: %v", err, quoteID)
+		slog.ErrorContext(ctx, "не удалось удалить цитату", "quote_id", quoteID, "error", err)
 		return err
 	}
+	slog.InfoContext(ctx, "цитата удалена", "quote_id", quoteID)
+	qs.record(ctx, audit.OpQuoteDelete, quoteToDTO(before), nil)
+
 	return nil
 }
 
-func (qs *QuoteService) ValidateData(text, authorName, mode string) error {
+// ValidateData - Проверяет данные по набору правил тенанта и маршрута из контекста. Возвращает
+// ErrValidation со всеми нарушениями сразу.
+func (qs *QuoteService) ValidateData(ctx context.Context, text, authorName, mode string) error {
+	var fields []string
 	switch mode {
 	case "quote":
-		text = strings.TrimSpace(text)
-
-		if text == "" {
-			err := NewErrInvalidData(400, "цитата не может быть пустой")
-			log.Printf("WARN: ошибка валидации цитаты: %v", err)
-			return err
-		}
-
-		minLength, maxLength := 1, 500
-		if len(text) < minLength {
-			err := NewErrInvalidData(400, fmt.Sprintf("цитата слишком короткая (минимум %d символов)", minLength))
-			log.Printf("WARN: ошибка валидации цитаты: %v", err)
-			return err
-		}
-		if len(text) > maxLength {
-			err := NewErrInvalidData(400, fmt.Sprintf("цитата слишком длинная (максимум %d символов)", minLength))
-			log.Printf("WARN: ошибка валидации цитаты: %v", err)
-			return err
-		}
-
-		if err := validateAuthor(authorName); err != nil {
-			return err
-		}
+		fields = []string{validation.FieldQuote, validation.FieldAuthor}
 	case "author":
-		if err := validateAuthor(authorName); err != nil {
-			return err
-		}
+		fields = []string{validation.FieldAuthor}
+	case "text":
+		// Автор указан по author_id, проверять нечего.
+		fields = []string{validation.FieldQuote}
 	default:
-		log.Printf("ERROR: не удалось валидировать переданные данные, text: %s, authorName: %s. Ошибка: указан не существующий метод валидации данных,", text, authorName)
+		slog.ErrorContext(ctx, "указан не существующий метод валидации данных", "mode", mode, logging.Quote("quote", text), "author", authorName)
 		return fmt.Errorf("не существующий метод проверки")
 	}
-	return nil
-}
-
-func validateAuthor(authorName string) error {
-	authorName = strings.TrimSpace(authorName)
-
-	if authorName == "" {
-		err := NewErrInvalidData(400, "имя автора не может быть пустым")
-		log.Printf("WARN: ошибка валидации имени автора: %v", err)
-		return err
-	}
 
-	minLength, maxLength := 2, 100
-	if len(authorName) < minLength {
-		err := fmt.Sprintf("имя автора слишком короткое (минимум %d символов)", minLength)
-		log.Printf("WARN: ошибка валидации имени автора: %v", err)
-		return NewErrInvalidData(400, err)
-	}
-	if len(authorName) > maxLength {
-		err := fmt.Sprintf("имя автора слишком длинное (максимум %d символов)", maxLength)
-		log.Printf("WARN: ошибка валидации имени автора: %v", err)
-		return NewErrInvalidData(400, err)
-	}
-
-	if strings.HasPrefix(authorName, "-") || strings.HasSuffix(authorName, "-") {
-		err := "имя автора не может начинаться или заканчиваться дефисом"
-		log.Printf("WARN: ошибка валидации имени автора: %v", err)
-		return NewErrInvalidData(400, err)
+	set := qs.rules.Load().Select(shared.TenantFromContext(ctx), shared.RequestMetaFromContext(ctx).Route)
+	violations := set.Check(text, authorName, fields...)
+	if len(violations) == 0 {
+		return nil
 	}
 
-	for _, r := range authorName {
-		if !(unicode.IsLetter(r) || unicode.IsSpace(r) || r == '-') {
-			err := fmt.Sprintf("имя автора содержит недопустимые символы: '%s' (символ '%c')", authorName, r)
-			log.Printf("WARN: ошибка валидации имени автора: %v", err)
-			return NewErrInvalidData(400, err)
-		}
+	errs := make(ErrValidation, 0, len(violations))
+	for _, v := range violations {
+		errs = append(errs, NewErrInvalidField(v.Field, v.Key, v.Args...))
 	}
-
-	return nil
+	slog.WarnContext(ctx, "ошибка валидации данных", "rules", set.Name, "error", errs)
+	return errs
 }
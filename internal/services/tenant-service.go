@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"go-offline-test/internal/repository"
+	"go-offline-test/internal/shared/dto"
+	"log/slog"
+)
+
+type ITenantService interface {
+	// CreateTenant - Создаёт тенанта с пустым набором цитат.
+	CreateTenant(ctx context.Context, req *dto.CreateTenant) (*dto.Tenant, error)
+	// ListTenants - Получает всех тенантов с размерами их коллекций.
+	ListTenants(ctx context.Context) ([]*dto.Tenant, error)
+	// UpdateQuota - Меняет квоту тенанта.
+	UpdateQuota(ctx context.Context, name string, quota *dto.Quota) (*dto.Tenant, error)
+	// DeleteTenant - Удаляет тенанта вместе со всеми его цитатами.
+	DeleteTenant(ctx context.Context, name string) error
+}
+
+type TenantService struct {
+	tenants *repository.TenantRegistry
+}
+
+func NewTenantService(tenants *repository.TenantRegistry) *TenantService {
+	return &TenantService{tenants: tenants}
+}
+
+func (ts *TenantService) CreateTenant(ctx context.Context, req *dto.CreateTenant) (*dto.Tenant, error) {
+	var quota *repository.Quota
+	if req.Quota != nil {
+		if err := validateQuota(ctx, req.Quota); err != nil {
+			return nil, err
+		}
+		quota = &repository.Quota{MaxQuotes: req.Quota.MaxQuotes, MaxAuthors: req.Quota.MaxAuthors}
+	}
+
+	tenant, err := ts.tenants.Create(ctx, req.Name, quota)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrInvalidTenantName):
+			slog.WarnContext(ctx, "не удалось создать тенанта", "tenant", req.Name, "error", err)
+			return nil, NewErrInvalidField("name", "tenant.invalid_name")
+		case errors.Is(err, repository.ErrTenantAlreadyExist):
+			slog.WarnContext(ctx, "не удалось создать тенанта", "tenant", req.Name, "error", err)
+			return nil, ErrTenantAlreadyExist
+		default:
+			slog.ErrorContext(ctx, "не удалось создать тенанта", "tenant", req.Name, "error", err)
+			return nil, err
+		}
+	}
+	slog.InfoContext(ctx, "тенант создан", "tenant", tenant.Name)
+
+	return tenantToDTO(tenant), nil
+}
+
+func (ts *TenantService) ListTenants(ctx context.Context) ([]*dto.Tenant, error) {
+	tenants, err := ts.tenants.List(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "не удалось получить список тенантов", "error", err)
+		return nil, err
+	}
+
+	resp := make([]*dto.Tenant, 0, len(tenants))
+	for _, tenant := range tenants {
+		resp = append(resp, tenantToDTO(tenant))
+	}
+	return resp, nil
+}
+
+func (ts *TenantService) UpdateQuota(ctx context.Context, name string, quota *dto.Quota) (*dto.Tenant, error) {
+	if err := validateQuota(ctx, quota); err != nil {
+		return nil, err
+	}
+
+	tenant, err := ts.tenants.Get(ctx, name)
+	if err != nil {
+		if errors.Is(err, repository.ErrTenantNotFound) {
+			slog.WarnContext(ctx, "не удалось изменить квоту тенанта", "tenant", name, "error", err)
+			return nil, ErrTenantNotFound
+		}
+		slog.ErrorContext(ctx, "не удалось изменить квоту тенанта", "tenant", name, "error", err)
+		return nil, err
+	}
+
+	tenant.Repo.SetQuota(repository.Quota{MaxQuotes: quota.MaxQuotes, MaxAuthors: quota.MaxAuthors})
+	slog.InfoContext(ctx, "квота тенанта изменена", "tenant", name, "max_quotes", quota.MaxQuotes, "max_authors", quota.MaxAuthors)
+
+	return tenantToDTO(tenant), nil
+}
+
+func (ts *TenantService) DeleteTenant(ctx context.Context, name string) error {
+	if err := ts.tenants.Delete(ctx, name); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrTenantNotFound):
+			slog.WarnContext(ctx, "не удалось удалить тенанта", "tenant", name, "error", err)
+			return ErrTenantNotFound
+		case errors.Is(err, repository.ErrDefaultTenantDelete):
+			slog.WarnContext(ctx, "не удалось удалить тенанта", "tenant", name, "error", err)
+			return ErrDefaultTenantDelete
+		default:
+			slog.ErrorContext(ctx, "не удалось удалить тенанта", "tenant", name, "error", err)
+			return err
+		}
+	}
+	slog.InfoContext(ctx, "тенант удалён", "tenant", name)
+
+	return nil
+}
+
+func validateQuota(ctx context.Context, quota *dto.Quota) error {
+	if quota.MaxQuotes < 0 || quota.MaxAuthors < 0 {
+		err := NewErrInvalidField("quota", "tenant.negative_quota", *quota)
+		slog.WarnContext(ctx, "ошибка валидации квоты", "error", err)
+		return err
+	}
+	return nil
+}
+
+func tenantToDTO(tenant *repository.Tenant) *dto.Tenant {
+	stats := tenant.Repo.Stats()
+	quota := tenant.Repo.Quota()
+	return &dto.Tenant{
+		Name:      tenant.Name,
+		Quota:     dto.Quota{MaxQuotes: quota.MaxQuotes, MaxAuthors: quota.MaxAuthors},
+		Quotes:    stats.Quotes,
+		Authors:   stats.Authors,
+		Pending:   stats.Pending,
+		CreatedAt: tenant.CreatedAt,
+	}
+}
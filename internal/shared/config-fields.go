@@ -0,0 +1,292 @@
+package shared
+
+import (
+	"encoding"
+	"fmt"
+	"go-offline-test/internal/shared/dto/config"
+	"go-offline-test/internal/shared/encoding/yaml"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// configField - Одна настройка: ключ section.key, переменная окружения и поле структуры, куда пишется значение.
+type configField struct {
+	path   string
+	env    string
+	secret bool
+	reload bool
+	value  reflect.Value
+}
+
+// configFields - Все настройки по тегам config/env в порядке объявления.
+func configFields(conf *config.Config) []*configField {
+	var fields []*configField
+	root := reflect.ValueOf(conf).Elem()
+	for i := 0; i < root.NumField(); i++ {
+		section := root.Type().Field(i).Tag.Get("config")
+		sv := root.Field(i)
+		for j := 0; j < sv.NumField(); j++ {
+			sf := sv.Type().Field(j)
+			key := sf.Tag.Get("config")
+			if key == "" {
+				continue
+			}
+			fields = append(fields, &configField{
+				path:   section + "." + key,
+				env:    sf.Tag.Get("env"),
+				secret: sf.Tag.Get("secret") == "true",
+				reload: sf.Tag.Get("reload") == "true",
+				value:  sv.Field(j),
+			})
+		}
+	}
+	return fields
+}
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// setString - Значение из переменной окружения или флага. Списки пишутся через запятую,
+// словари - как ключ=значение через запятую, списки в словаре - через +.
+func (f *configField) setString(raw string) error {
+	v := f.value
+	if v.Addr().Type().Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw))
+	}
+
+	switch {
+	case v.Type() == durationType:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("ожидается длительность, например 30s, получено %q", raw)
+		}
+		v.SetInt(int64(d))
+	case v.Kind() == reflect.String:
+		v.SetString(raw)
+	case v.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("ожидается true или false, получено %q", raw)
+		}
+		v.SetBool(b)
+	case v.Kind() == reflect.Int || v.Kind() == reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return fmt.Errorf("ожидается целое число, получено %q", raw)
+		}
+		v.SetInt(n)
+	case v.Kind() == reflect.Float64:
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("ожидается число, получено %q", raw)
+		}
+		v.SetFloat(n)
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String:
+		v.Set(reflect.ValueOf(splitList(raw)))
+	case v.Kind() == reflect.Map:
+		m := reflect.MakeMap(v.Type())
+		for _, pair := range splitList(raw) {
+			key, value, found := strings.Cut(pair, "=")
+			if !found || key == "" {
+				return fmt.Errorf("некорректная запись %q, ожидается ключ=значение", pair)
+			}
+			item, err := mapItem(v.Type().Elem(), value)
+			if err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+			m.SetMapIndex(reflect.ValueOf(key), item)
+		}
+		v.Set(m)
+	default:
+		return fmt.Errorf("тип %s не поддерживается", v.Type())
+	}
+	return nil
+}
+
+func mapItem(t reflect.Type, raw string) (reflect.Value, error) {
+	switch {
+	case t.Kind() == reflect.String:
+		return reflect.ValueOf(raw), nil
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.String:
+		if raw == "" {
+			return reflect.Value{}, fmt.Errorf("пустое значение")
+		}
+		return reflect.ValueOf(strings.Split(raw, "+")), nil
+	default:
+		return reflect.Value{}, fmt.Errorf("тип %s не поддерживается", t)
+	}
+}
+
+// setValue - Значение из файла настроек: скаляр, список или объект.
+func (f *configField) setValue(raw any) error {
+	v := f.value
+	switch val := raw.(type) {
+	case nil:
+		return nil
+	case []any:
+		if v.Kind() != reflect.Slice {
+			return fmt.Errorf("ожидается одно значение, а не список")
+		}
+		items, err := stringList(val)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(items))
+		return nil
+	case map[string]any:
+		if v.Kind() != reflect.Map {
+			return fmt.Errorf("ожидается одно значение, а не объект")
+		}
+		m := reflect.MakeMap(v.Type())
+		for key, item := range val {
+			var iv reflect.Value
+			if list, ok := item.([]any); ok && v.Type().Elem().Kind() == reflect.Slice {
+				items, err := stringList(list)
+				if err != nil {
+					return fmt.Errorf("%s: %w", key, err)
+				}
+				iv = reflect.ValueOf(items)
+			} else {
+				s, err := scalarString(item)
+				if err != nil {
+					return fmt.Errorf("%s: %w", key, err)
+				}
+				if iv, err = mapItem(v.Type().Elem(), s); err != nil {
+					return fmt.Errorf("%s: %w", key, err)
+				}
+			}
+			m.SetMapIndex(reflect.ValueOf(key), iv)
+		}
+		v.Set(m)
+		return nil
+	default:
+		s, err := scalarString(val)
+		if err != nil {
+			return err
+		}
+		return f.setString(s)
+	}
+}
+
+func stringList(items []any) ([]string, error) {
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		s, err := scalarString(item)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+func scalarString(v any) (string, error) {
+	switch val := v.(type) {
+	case string:
+		return val, nil
+	case bool:
+		return strconv.FormatBool(val), nil
+	case int64:
+		return strconv.FormatInt(val, 10), nil
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64), nil
+	default:
+		return "", fmt.Errorf("ожидается строка, число или логическое значение")
+	}
+}
+
+// flagValue - Значение флага, отложенное до применения файла и окружения.
+type flagValue struct {
+	field *configField
+	value string
+}
+
+type flagSetter struct {
+	field  *configField
+	values *[]flagValue
+}
+
+func (fs *flagSetter) String() string {
+	return ""
+}
+
+func (fs *flagSetter) Set(raw string) error {
+	*fs.values = append(*fs.values, flagValue{field: fs.field, value: raw})
+	return nil
+}
+
+// IsBoolFlag - Позволяет писать --auth.enabled без =true.
+func (fs *flagSetter) IsBoolFlag() bool {
+	return fs.field != nil && fs.field.value.Kind() == reflect.Bool
+}
+
+// configTree - Настройки в виде дерева для вывода, секреты заменены на ***.
+func configTree(conf *config.Config) yaml.MapSlice {
+	var tree yaml.MapSlice
+	for _, f := range configFields(conf) {
+		section, key, _ := strings.Cut(f.path, ".")
+		if len(tree) == 0 || tree[len(tree)-1].Key != section {
+			tree = append(tree, yaml.MapItem{Key: section, Value: yaml.MapSlice{}})
+		}
+		last := &tree[len(tree)-1]
+		last.Value = append(last.Value.(yaml.MapSlice), yaml.MapItem{Key: key, Value: displayValue(f)})
+	}
+	return tree
+}
+
+func displayValue(f *configField) any {
+	v := f.value
+	if !f.secret {
+		return v.Interface()
+	}
+
+	switch v.Kind() {
+	case reflect.Map:
+		// Ключи (имена заголовков) оставляем, прячем только значения.
+		masked := make(yaml.MapSlice, 0, v.Len())
+		for _, key := range v.MapKeys() {
+			masked = append(masked, yaml.MapItem{Key: key.String(), Value: "***"})
+		}
+		sort.Slice(masked, func(i, j int) bool { return masked[i].Key < masked[j].Key })
+		return masked
+	default:
+		if v.IsZero() {
+			return v.Interface()
+		}
+		return "***"
+	}
+}
+
+// sameValue - Пустой список из файла и незаданный список по умолчанию считаются одинаковыми.
+func sameValue(a, b reflect.Value) bool {
+	if (a.Kind() == reflect.Slice || a.Kind() == reflect.Map) && a.Len() == 0 && b.Len() == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
+
+// changeValue - Значение для отчёта о перезагрузке: длительности и уровни строкой, секреты скрыты.
+func changeValue(f *configField) any {
+	if f.secret && !sameValue(f.value, reflect.Zero(f.value.Type())) {
+		return "***"
+	}
+	if s, ok := f.value.Interface().(fmt.Stringer); ok {
+		return s.String()
+	}
+	return f.value.Interface()
+}
+
+func splitList(raw string) []string {
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
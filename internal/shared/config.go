@@ -1,17 +1,392 @@
 package shared
 
 import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"go-offline-test/internal/filter"
+	"go-offline-test/internal/listen"
+	"go-offline-test/internal/shared/dto"
 	"go-offline-test/internal/shared/dto/config"
-	"log"
+	"go-offline-test/internal/shared/encoding/toml"
+	"go-offline-test/internal/shared/encoding/yaml"
+	"go-offline-test/internal/validation"
+	"io"
+	"log/slog"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 )
 
-func GetAddr() *config.AddrConfig {
-	addr := os.Getenv("ADDR_CONFIG")
-	port := os.Getenv("PORT_CONFIG")
-	if addr == "" || port == "" {
-		log.Fatal("addr || port не найден(ы) в файле .env")
+// DefaultConfig - Значения, с которых начинается сборка настроек.
+func DefaultConfig() *config.Config {
+	return &config.Config{
+		Server: config.ServerConfig{
+			Listen:            ":8080",
+			ReadTimeout:       15 * time.Second,
+			ReadHeaderTimeout: 5 * time.Second,
+			WriteTimeout:      30 * time.Second,
+			IdleTimeout:       120 * time.Second,
+			MaxHeaderBytes:    64 << 10,
+			MaxBodyBytes:      1 << 20,
+			ShutdownTimeout:   30 * time.Second,
+		},
+		TLS: config.TLSConfig{
+			ReloadInterval: time.Minute,
+			MinVersion:     "1.2",
+			CipherPolicy:   "default",
+			ClientAuth:     "require",
+			ClientScopes:   map[string][]string{},
+		},
+		Auth: config.AuthConfig{
+			Enabled:  true,
+			APIKeys:  true,
+			KeysFile: "api-keys.json",
+		},
+		JWT: config.JWTConfig{
+			JWKSRefresh: 10 * time.Minute,
+			Leeway:      30 * time.Second,
+			ScopeClaim:  "scope",
+			ScopeMap:    map[string][]string{},
+		},
+		Validation: config.ValidationConfig{
+			QuoteMinLength:  1,
+			QuoteMaxLength:  500,
+			AuthorMinLength: 2,
+			AuthorMaxLength: 100,
+			QuoteChars:      []string{},
+			AuthorChars:     []string{"letter", "space", "hyphen"},
+			// Дефис в начале или конце имени почти всегда опечатка.
+			AuthorForbiddenEdges: []string{"hyphen"},
+			ForbiddenWords:       []string{},
+			TenantRules:          map[string]string{},
+			RouteRules:           map[string]string{},
+		},
+		Moderation: config.ModerationConfig{Tenants: []string{}},
+		Filter: config.FilterConfig{
+			Chain:          []string{},
+			Actions:        map[string]string{},
+			ProfanityWords: []string{},
+			MaxRepeats:     3,
+			RegexRules:     map[string][]string{},
+		},
+		Audit: config.AuditConfig{MaxEntries: 10000},
+		Log: config.LogConfig{
+			Format:       "text",
+			Level:        slog.LevelInfo,
+			RedactQuotes: true,
+		},
+		Trace: config.TraceConfig{
+			Exporter:    "none",
+			File:        "traces.jsonl",
+			Headers:     map[string]string{},
+			ServiceName: "quotes",
+			SampleRatio: 1,
+		},
+		Health: config.HealthConfig{
+			DiskMinFreeMB: 100,
+			TraceQueueMax: 3072,
+		},
 	}
+}
+
+// ConfigOptions - Флаги, которые управляют загрузкой, а не самими настройками.
+type ConfigOptions struct {
+	// File - Файл настроек из --config или CONFIG_FILE.
+	File string
+	// Print - Вывести итоговые настройки и выйти.
+	Print bool
+}
+
+// LoadConfig - Собирает настройки: умолчания, файл (JSON, TOML или YAML по расширению), переменные
+// окружения, флаги командной строки. Каждый следующий слой перекрывает предыдущий. Ошибки всех слоёв
+// и проверки значений собираются вместе, чтобы их можно было исправить за один раз.
+func LoadConfig(name string, args []string, output io.Writer) (*config.Config, *ConfigOptions, error) {
+	conf := DefaultConfig()
+	fields := configFields(conf)
+	opts := &ConfigOptions{File: os.Getenv("CONFIG_FILE")}
+
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(output)
+	fs.StringVar(&opts.File, "config", opts.File, "файл настроек .json, .toml, .yaml или .yml (CONFIG_FILE)")
+	fs.BoolVar(&opts.Print, "print-config", false, "вывести итоговые настройки со скрытыми секретами и выйти")
+
+	// Флаги разбираются первыми, чтобы узнать --config, но применяются последними.
+	var flagValues []flagValue
+	for _, f := range fields {
+		fs.Var(&flagSetter{field: f, values: &flagValues}, f.path, "переменная "+f.env)
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, nil, fmt.Errorf("лишние аргументы: %s", strings.Join(fs.Args(), " "))
+	}
+
+	var errs []error
+	if opts.File != "" {
+		errs = append(errs, applyFile(fields, opts.File)...)
+	}
+	errs = append(errs, applyEnv(fields)...)
+	for _, fv := range flagValues {
+		if err := fv.field.setString(fv.value); err != nil {
+			errs = append(errs, fmt.Errorf("--%s: %w", fv.field.path, err))
+		}
+	}
+	errs = append(errs, ValidateConfig(conf)...)
+
+	if len(errs) > 0 {
+		return conf, opts, &ConfigError{Errs: errs}
+	}
+	return conf, opts, nil
+}
+
+// ConfigError - Все найденные ошибки настроек.
+type ConfigError struct {
+	Errs []error
+}
+
+func (e *ConfigError) Error() string {
+	var b strings.Builder
+	b.WriteString("некорректные настройки:")
+	for _, err := range e.Errs {
+		b.WriteString("\n  - ")
+		b.WriteString(err.Error())
+	}
+	return b.String()
+}
+
+func (e *ConfigError) Unwrap() []error {
+	return e.Errs
+}
+
+func applyFile(fields []*configField, path string) []error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return []error{fmt.Errorf("файл настроек: %w", err)}
+	}
+
+	var doc any
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		err = json.Unmarshal(data, &doc)
+	case ".toml":
+		doc, err = toml.Unmarshal(data)
+	case ".yaml", ".yml":
+		doc, err = yaml.Unmarshal(data)
+	default:
+		return []error{fmt.Errorf("файл настроек %s: неизвестный формат %q, ожидается .json, .toml, .yaml или .yml", path, ext)}
+	}
+	if err != nil {
+		return []error{fmt.Errorf("файл настроек %s: %w", path, err)}
+	}
+	if doc == nil {
+		return nil
+	}
+
+	root, ok := doc.(map[string]any)
+	if !ok {
+		return []error{fmt.Errorf("файл настроек %s: на верхнем уровне ожидается объект с секциями", path)}
+	}
+
+	var errs []error
+	known := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		known[f.path] = true
+		section, key, _ := strings.Cut(f.path, ".")
+
+		values, ok := root[section].(map[string]any)
+		if !ok {
+			continue
+		}
+		v, ok := values[key]
+		if !ok {
+			continue
+		}
+		if err := f.setValue(v); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %s: %w", path, f.path, err))
+		}
+	}
+
+	// Опечатка в ключе иначе молча оставила бы значение по умолчанию.
+	for section, raw := range root {
+		values, ok := raw.(map[string]any)
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: %s: ожидается секция с ключами", path, section))
+			continue
+		}
+		for key := range values {
+			if !known[section+"."+key] {
+				errs = append(errs, fmt.Errorf("%s: неизвестный ключ %s.%s", path, section, key))
+			}
+		}
+	}
+	return errs
+}
+
+func applyEnv(fields []*configField) []error {
+	var errs []error
+	for _, f := range fields {
+		raw, ok := os.LookupEnv(f.env)
+		if !ok || raw == "" {
+			continue
+		}
+		if err := f.setString(raw); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", f.env, err))
+		}
+	}
+
+	// PORT_CONFIG и ADDR_CONFIG - прежний способ задать адрес, оставлен для совместимости.
+	if _, ok := os.LookupEnv("LISTEN_ADDR"); !ok {
+		if port := os.Getenv("PORT_CONFIG"); port != "" {
+			for _, f := range fields {
+				if f.path == "server.listen" {
+					f.value.SetString(legacyListen(os.Getenv("ADDR_CONFIG"), port))
+				}
+			}
+		}
+	}
+	return errs
+}
+
+// legacyListen - ADDR_CONFIG=0.0.0.0 и PORT_CONFIG=:8080 дают 0.0.0.0:8080.
+func legacyListen(addr, port string) string {
+	if addr == "" || !strings.HasPrefix(port, ":") || strings.Contains(addr, ":") {
+		return port
+	}
+	return addr + port
+}
+
+// ValidateConfig - Проверяет значения, которые по отдельности разобрались, но вместе не имеют смысла.
+func ValidateConfig(conf *config.Config) []error {
+	var errs []error
+	check := func(ok bool, key, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+		}
+	}
+
+	s := conf.Server
+	if len(s.Listeners) == 0 {
+		check(s.Listen != "", "server.listen", "адрес не может быть пустым")
+	}
+	_, listenErrs := listen.ParseAll(s.Listeners)
+	for _, err := range listenErrs {
+		check(false, "server.listeners", "%s", err)
+	}
+	check(s.ReadTimeout >= 0, "server.read_timeout", "не может быть отрицательным")
+	check(s.ReadHeaderTimeout >= 0, "server.read_header_timeout", "не может быть отрицательным")
+	check(s.WriteTimeout >= 0, "server.write_timeout", "не может быть отрицательным")
+	check(s.IdleTimeout >= 0, "server.idle_timeout", "не может быть отрицательным")
+	check(s.MaxHeaderBytes > 0, "server.max_header_bytes", "должен быть больше нуля")
+	check(s.MaxBodyBytes > 0, "server.max_body_bytes", "должен быть больше нуля")
+	check(s.ShutdownTimeout > 0, "server.shutdown_timeout", "должен быть больше нуля")
+	check(s.DrainDelay >= 0, "server.drain_delay", "не может быть отрицательным")
+
+	if t := conf.TLS; t.Enabled {
+		check(t.SelfSigned || (t.CertFile != "" && t.KeyFile != ""), "tls.cert_file", "нужны cert_file и key_file или self_signed")
+		check(!t.SelfSigned || (t.CertFile == "" && t.KeyFile == ""), "tls.self_signed", "нельзя совмещать с cert_file и key_file")
+		check(t.ReloadInterval >= 0, "tls.reload_interval", "не может быть отрицательным")
+		check(t.MinVersion == "1.2" || t.MinVersion == "1.3", "tls.min_version", "должна быть 1.2 или 1.3, получено %q", t.MinVersion)
+		check(t.CipherPolicy == "default" || t.CipherPolicy == "modern", "tls.cipher_policy", "должна быть default или modern, получено %q", t.CipherPolicy)
+		check(t.ClientAuth == "require" || t.ClientAuth == "verify_if_given", "tls.client_auth", "должен быть require или verify_if_given, получено %q", t.ClientAuth)
+		for subject, scopes := range t.ClientScopes {
+			check(len(scopes) > 0, "tls.client_scopes", "для %q не указаны права", subject)
+		}
+	} else {
+		check(t.ClientCAFile == "", "tls.client_ca_file", "mTLS требует tls.enabled")
+	}
+
+	check(!conf.Auth.APIKeys || conf.Auth.KeysFile != "", "auth.keys_file", "нужен файл ключей, если включены API-ключи")
+
+	if conf.JWT.JWKS != "" {
+		check(conf.JWT.ScopeClaim != "", "jwt.scope_claim", "не может быть пустым")
+		check(conf.JWT.Leeway >= 0, "jwt.leeway", "не может быть отрицательным")
+		check(conf.JWT.JWKSRefresh >= 0, "jwt.jwks_refresh", "не может быть отрицательным")
+		for value, scopes := range conf.JWT.ScopeMap {
+			check(len(scopes) > 0, "jwt.scope_map", "для значения %q не указаны права", value)
+		}
+	}
+
+	check(conf.Tenants.DefaultMaxQuotes >= 0, "tenants.max_quotes", "не может быть отрицательной")
+	check(conf.Tenants.DefaultMaxAuthors >= 0, "tenants.max_authors", "не может быть отрицательной")
+
+	v := conf.Validation
+	check(v.QuoteMinLength >= 1, "validation.quote_min_length", "должна быть не меньше 1")
+	check(v.QuoteMaxLength >= v.QuoteMinLength, "validation.quote_max_length", "должна быть не меньше quote_min_length (%d)", v.QuoteMinLength)
+	check(v.AuthorMinLength >= 1, "validation.author_min_length", "должна быть не меньше 1")
+	check(v.AuthorMaxLength >= v.AuthorMinLength, "validation.author_max_length", "должна быть не меньше author_min_length (%d)", v.AuthorMinLength)
+	// Классы символов, шаблоны, файл правил и привязки наборов проверяет тот же код, что их применяет.
+	_, ruleErrs := validation.Load(v)
+	errs = append(errs, ruleErrs...)
+
+	for _, tenant := range conf.Moderation.Tenants {
+		check(ValidTenantName(tenant), "moderation.tenants", "некорректное имя тенанта %q", tenant)
+	}
+
+	_, filterErrs := filter.Load(conf.Filter)
+	errs = append(errs, filterErrs...)
+
+	check(conf.Audit.MaxEntries >= 0, "audit.max_entries", "не может быть отрицательным")
+
+	check(conf.Log.Format == "text" || conf.Log.Format == "json", "log.format", "должен быть text или json, получено %q", conf.Log.Format)
+
+	t := conf.Trace
+	switch t.Exporter {
+	case "none":
+	case "file":
+		check(t.File != "", "trace.file", "нужен файл для экспортёра file")
+	case "otlp":
+		check(t.Endpoint != "", "trace.otlp_endpoint", "нужен адрес коллектора для экспортёра otlp")
+	default:
+		check(false, "trace.exporter", "должен быть none, file или otlp, получено %q", t.Exporter)
+	}
+	check(t.SampleRatio >= 0 && t.SampleRatio <= 1, "trace.sample_ratio", "должна быть от 0 до 1")
+	check(t.ServiceName != "", "trace.service_name", "не может быть пустым")
+
+	check(conf.Health.DiskMinFreeMB >= 0, "health.disk_min_free_mb", "не может быть отрицательным")
+	check(conf.Health.TraceQueueMax >= 0, "health.trace_queue_max", "не может быть отрицательной")
+
+	return errs
+}
+
+// PrintConfig - Выводит итоговые настройки в YAML. Такой вывод можно сохранить и использовать как файл настроек.
+func PrintConfig(w io.Writer, conf *config.Config) error {
+	out, err := yaml.Marshal(configTree(conf))
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+// ReloadConfig - Сравнивает действующие настройки с перечитанными. Возвращает копию действующих,
+// в которую перенесены только изменения с тегом reload, и отчёт обо всех изменениях.
+func ReloadConfig(current, next *config.Config) (*config.Config, *dto.ConfigReload) {
+	merged := *current
+	report := &dto.ConfigReload{Applied: []*dto.ConfigChange{}, RestartRequired: []*dto.ConfigChange{}}
+
+	nextFields := configFields(next)
+	for i, f := range configFields(&merged) {
+		nf := nextFields[i]
+		if sameValue(f.value, nf.value) {
+			continue
+		}
+		change := &dto.ConfigChange{Key: f.path, Old: changeValue(f), New: changeValue(nf)}
+		if !f.reload {
+			report.RestartRequired = append(report.RestartRequired, change)
+			continue
+		}
+		f.value.Set(nf.value)
+		report.Applied = append(report.Applied, change)
+	}
+	return &merged, report
+}
 
-	return &config.AddrConfig{Addr: addr, Port: port}
+// IsConfigError - Ошибка в самих настройках, а не в разборе флагов (например, -h).
+func IsConfigError(err error) bool {
+	var confErr *ConfigError
+	return errors.As(err, &confErr)
 }
@@ -1,6 +0,0 @@
-package config
-
-type AddrConfig struct {
-	Addr string
-	Port string
-}
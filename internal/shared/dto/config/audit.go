@@ -0,0 +1,8 @@
+package config
+
+type AuditConfig struct {
+	// File - Файл журнала со сцепкой хешей. Пустая строка - журнал только в памяти.
+	File string `config:"file" env:"AUDIT_FILE"`
+	// MaxEntries - Сколько последних записей держать в памяти для GET /audit, 0 - все.
+	MaxEntries int `config:"max_entries" env:"AUDIT_MAX_ENTRIES"`
+}
@@ -0,0 +1,21 @@
+package config
+
+import "time"
+
+type AuthConfig struct {
+	Enabled  bool   `config:"enabled" env:"AUTH_ENABLED"`
+	APIKeys  bool   `config:"api_keys" env:"API_KEYS_ENABLED"`
+	KeysFile string `config:"keys_file" env:"API_KEYS_FILE"`
+}
+
+type JWTConfig struct {
+	// JWKS - Путь к файлу или URL набора ключей. Пусто - проверка JWT выключена.
+	JWKS        string        `config:"jwks" env:"JWT_JWKS"`
+	JWKSRefresh time.Duration `config:"jwks_refresh" env:"JWT_JWKS_REFRESH"`
+	Issuer      string        `config:"issuer" env:"JWT_ISSUER"`
+	Audience    []string      `config:"audience" env:"JWT_AUDIENCE"`
+	Leeway      time.Duration `config:"leeway" env:"JWT_LEEWAY"`
+	ScopeClaim  string        `config:"scope_claim" env:"JWT_SCOPE_CLAIM"`
+	// ScopeMap - Значение claim -> права сервиса.
+	ScopeMap map[string][]string `config:"scope_map" env:"JWT_SCOPE_MAP"`
+}
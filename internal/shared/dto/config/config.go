@@ -0,0 +1,20 @@
+package config
+
+// Config - Все настройки сервиса. Собираются по слоям: умолчания, файл, переменные окружения, флаги.
+// Тег config - ключ в файле и имя флага (--секция.ключ), env - переменная окружения,
+// secret - значение маскируется в --print-config, reload - значение применяется без перезапуска.
+type Config struct {
+	Server     ServerConfig     `config:"server"`
+	TLS        TLSConfig        `config:"tls"`
+	Request    RequestConfig    `config:"request"`
+	Auth       AuthConfig       `config:"auth"`
+	JWT        JWTConfig        `config:"jwt"`
+	Tenants    TenantConfig     `config:"tenants"`
+	Validation ValidationConfig `config:"validation"`
+	Moderation ModerationConfig `config:"moderation"`
+	Filter     FilterConfig     `config:"filter"`
+	Audit      AuditConfig      `config:"audit"`
+	Log        LogConfig        `config:"log"`
+	Trace      TraceConfig      `config:"trace"`
+	Health     HealthConfig     `config:"health"`
+}
@@ -0,0 +1,15 @@
+package config
+
+type FilterConfig struct {
+	// Chain - Фильтры в порядке применения: profanity, spam, repeats, regex. Пусто - фильтры выключены.
+	Chain []string `config:"chain" env:"FILTER_CHAIN" reload:"true"`
+	// Actions - Действие фильтра при находке: reject, flag или rewrite. Не указанные фильтры
+	// действуют по умолчанию: profanity - reject, spam - flag, repeats - rewrite.
+	Actions map[string]string `config:"actions" env:"FILTER_ACTIONS" reload:"true"`
+	// ProfanityWords - Слова в дополнение к встроенному словарю. "*" на конце - совпадение по началу слова.
+	ProfanityWords []string `config:"profanity_words" env:"FILTER_PROFANITY_WORDS" reload:"true"`
+	// MaxRepeats - Сколько раз подряд может повторяться один символ.
+	MaxRepeats int `config:"max_repeats" env:"FILTER_MAX_REPEATS" reload:"true"`
+	// RegexRules - Собственные правила фильтра regex: действие -> регулярные выражения.
+	RegexRules map[string][]string `config:"regex_rules" env:"FILTER_REGEX_RULES" reload:"true"`
+}
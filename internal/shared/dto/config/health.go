@@ -0,0 +1,10 @@
+package config
+
+type HealthConfig struct {
+	// DiskPath - Каталог с данными, свободное место на разделе которого проверяет /readyz. Пусто - проверка выключена.
+	DiskPath string `config:"disk_path" env:"HEALTH_DISK_PATH"`
+	// DiskMinFreeMB - Минимум свободного места в мегабайтах.
+	DiskMinFreeMB int `config:"disk_min_free_mb" env:"HEALTH_DISK_MIN_FREE_MB"`
+	// TraceQueueMax - Предельная длина очереди экспорта трасс.
+	TraceQueueMax int `config:"trace_queue_max" env:"HEALTH_TRACE_QUEUE_MAX"`
+}
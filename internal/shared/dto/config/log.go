@@ -0,0 +1,11 @@
+package config
+
+import "log/slog"
+
+type LogConfig struct {
+	// Format - json или text.
+	Format string     `config:"format" env:"LOG_FORMAT"`
+	Level  slog.Level `config:"level" env:"LOG_LEVEL" reload:"true"`
+	// RedactQuotes - Скрывать тексты цитат в логах.
+	RedactQuotes bool `config:"redact_quotes" env:"LOG_REDACT_QUOTES" reload:"true"`
+}
@@ -0,0 +1,8 @@
+package config
+
+type ModerationConfig struct {
+	// Enabled - Новые цитаты всех тенантов попадают в выдачу только после одобрения модератором.
+	Enabled bool `config:"enabled" env:"MODERATION_ENABLED" reload:"true"`
+	// Tenants - Тенанты с модерацией, если она не включена для всех.
+	Tenants []string `config:"tenants" env:"MODERATION_TENANTS" reload:"true"`
+}
@@ -0,0 +1,6 @@
+package config
+
+type RequestConfig struct {
+	// TrustForwardedFor - Брать IP клиента из X-Forwarded-For (только за доверенным прокси).
+	TrustForwardedFor bool `config:"trust_forwarded_for" env:"TRUST_FORWARDED_FOR"`
+}
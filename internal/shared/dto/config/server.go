@@ -0,0 +1,26 @@
+package config
+
+import "time"
+
+type ServerConfig struct {
+	// Listen - Адрес, на котором сервер принимает соединения, например :8080 или 127.0.0.1:8080.
+	Listen string `config:"listen" env:"LISTEN_ADDR"`
+	// Listeners - Несколько слушателей со своими наборами маршрутов, например
+	// tcp://:8080?routes=public или unix:///run/quotes.sock?mode=0660&routes=admin. Если задан, Listen не используется.
+	Listeners []string `config:"listeners" env:"LISTENERS"`
+	// ReadTimeout - Время на чтение всего запроса вместе с телом.
+	ReadTimeout time.Duration `config:"read_timeout" env:"SERVER_READ_TIMEOUT"`
+	// ReadHeaderTimeout - Время на чтение заголовков. Защищает от клиентов, которые шлют их по байту.
+	ReadHeaderTimeout time.Duration `config:"read_header_timeout" env:"SERVER_READ_HEADER_TIMEOUT"`
+	WriteTimeout      time.Duration `config:"write_timeout" env:"SERVER_WRITE_TIMEOUT"`
+	// IdleTimeout - Сколько держать keep-alive соединение без запросов.
+	IdleTimeout    time.Duration `config:"idle_timeout" env:"SERVER_IDLE_TIMEOUT"`
+	MaxHeaderBytes int           `config:"max_header_bytes" env:"SERVER_MAX_HEADER_BYTES"`
+	// MaxBodyBytes - Предельный размер тела запроса, больше - 413.
+	MaxBodyBytes int64 `config:"max_body_bytes" env:"SERVER_MAX_BODY_BYTES"`
+	// ShutdownTimeout - Сколько ждать завершения активных запросов после сигнала остановки.
+	ShutdownTimeout time.Duration `config:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT"`
+	// DrainDelay - Пауза между переходом /readyz в not_ready и закрытием listener,
+	// чтобы балансировщик успел убрать экземпляр из ротации.
+	DrainDelay time.Duration `config:"drain_delay" env:"SHUTDOWN_DRAIN_DELAY"`
+}
@@ -0,0 +1,7 @@
+package config
+
+type TenantConfig struct {
+	// DefaultMaxQuotes, DefaultMaxAuthors - Квота для новых тенантов, 0 - без ограничений.
+	DefaultMaxQuotes  int `config:"max_quotes" env:"TENANT_MAX_QUOTES" reload:"true"`
+	DefaultMaxAuthors int `config:"max_authors" env:"TENANT_MAX_AUTHORS" reload:"true"`
+}
@@ -0,0 +1,24 @@
+package config
+
+import "time"
+
+type TLSConfig struct {
+	// Enabled - Принимать HTTPS вместо HTTP.
+	Enabled  bool   `config:"enabled" env:"TLS_ENABLED"`
+	CertFile string `config:"cert_file" env:"TLS_CERT_FILE"`
+	KeyFile  string `config:"key_file" env:"TLS_KEY_FILE"`
+	// SelfSigned - Выпустить самоподписанный сертификат при запуске. Только для разработки.
+	SelfSigned bool `config:"self_signed" env:"TLS_SELF_SIGNED"`
+	// ReloadInterval - Как часто проверять, не сменились ли файлы сертификата и ключа.
+	ReloadInterval time.Duration `config:"reload_interval" env:"TLS_RELOAD_INTERVAL"`
+	// MinVersion - 1.2 или 1.3.
+	MinVersion string `config:"min_version" env:"TLS_MIN_VERSION"`
+	// CipherPolicy - default (набор Go по умолчанию) или modern (только ECDHE с AEAD для TLS 1.2).
+	CipherPolicy string `config:"cipher_policy" env:"TLS_CIPHER_POLICY"`
+	// ClientCAFile - CA для клиентских сертификатов. Пусто - mTLS выключен.
+	ClientCAFile string `config:"client_ca_file" env:"TLS_CLIENT_CA_FILE"`
+	// ClientAuth - require (без сертификата соединение не принимается) или verify_if_given.
+	ClientAuth string `config:"client_auth" env:"TLS_CLIENT_AUTH"`
+	// ClientScopes - CN или полный subject сертификата -> права сервиса.
+	ClientScopes map[string][]string `config:"client_scopes" env:"TLS_CLIENT_SCOPES"`
+}
@@ -0,0 +1,15 @@
+package config
+
+type TraceConfig struct {
+	// Exporter - none, file или otlp.
+	Exporter string `config:"exporter" env:"TRACE_EXPORTER"`
+	// File - Файл для экспортёра file.
+	File string `config:"file" env:"TRACE_FILE"`
+	// Endpoint - Адрес коллектора для экспортёра otlp, например http://collector:4318/v1/traces.
+	Endpoint string `config:"otlp_endpoint" env:"TRACE_OTLP_ENDPOINT"`
+	// Headers - Дополнительные заголовки запросов к коллектору, обычно с токеном.
+	Headers     map[string]string `config:"otlp_headers" env:"TRACE_OTLP_HEADERS" secret:"true"`
+	ServiceName string            `config:"service_name" env:"TRACE_SERVICE_NAME"`
+	// SampleRatio - Доля новых трасс, которые экспортируются, от 0 до 1.
+	SampleRatio float64 `config:"sample_ratio" env:"TRACE_SAMPLE_RATIO" reload:"true"`
+}
@@ -0,0 +1,27 @@
+package config
+
+type ValidationConfig struct {
+	// QuoteMinLength, QuoteMaxLength - Допустимая длина текста цитаты в символах.
+	QuoteMinLength int `config:"quote_min_length" env:"VALIDATION_QUOTE_MIN_LENGTH" reload:"true"`
+	QuoteMaxLength int `config:"quote_max_length" env:"VALIDATION_QUOTE_MAX_LENGTH" reload:"true"`
+	// AuthorMinLength, AuthorMaxLength - Допустимая длина имени автора в символах.
+	AuthorMinLength int `config:"author_min_length" env:"VALIDATION_AUTHOR_MIN_LENGTH" reload:"true"`
+	AuthorMaxLength int `config:"author_max_length" env:"VALIDATION_AUTHOR_MAX_LENGTH" reload:"true"`
+	// QuoteChars, AuthorChars - Допустимые классы символов: letter, digit, space, hyphen, apostrophe,
+	// punct, symbol. Пустой список - любые символы.
+	QuoteChars  []string `config:"quote_chars" env:"VALIDATION_QUOTE_CHARS" reload:"true"`
+	AuthorChars []string `config:"author_chars" env:"VALIDATION_AUTHOR_CHARS" reload:"true"`
+	// AuthorForbiddenEdges - Классы символов, с которых имя автора не может начинаться и которыми не может заканчиваться.
+	AuthorForbiddenEdges []string `config:"author_forbidden_edges" env:"VALIDATION_AUTHOR_FORBIDDEN_EDGES" reload:"true"`
+	// QuotePattern, AuthorPattern - Регулярные выражения, которым должны соответствовать поля. Пусто - не проверяется.
+	QuotePattern  string `config:"quote_pattern" env:"VALIDATION_QUOTE_PATTERN" reload:"true"`
+	AuthorPattern string `config:"author_pattern" env:"VALIDATION_AUTHOR_PATTERN" reload:"true"`
+	// ForbiddenWords - Слова и фразы, запрещённые в цитате и имени автора.
+	ForbiddenWords []string `config:"forbidden_words" env:"VALIDATION_FORBIDDEN_WORDS" reload:"true"`
+	// RulesFile - Файл с именованными наборами правил (.json, .toml, .yaml). Перечитывается при перезагрузке настроек.
+	RulesFile string `config:"rules_file" env:"VALIDATION_RULES_FILE" reload:"true"`
+	// TenantRules - Набор правил для тенанта: тенант -> имя набора из RulesFile.
+	TenantRules map[string]string `config:"tenant_rules" env:"VALIDATION_TENANT_RULES" reload:"true"`
+	// RouteRules - Набор правил для маршрута вида "POST /quotes". Важнее привязки к тенанту.
+	RouteRules map[string]string `config:"route_rules" env:"VALIDATION_ROUTE_RULES" reload:"true"`
+}
@@ -0,0 +1,39 @@
+package dto
+
+import "time"
+
+type AuditEntry struct {
+	Seq       int64     `json:"seq"`
+	Time      time.Time `json:"time"`
+	Operation string    `json:"operation"`
+	Tenant    string    `json:"tenant"`
+	Actor     string    `json:"actor,omitempty"`
+	ClientIP  string    `json:"client_ip,omitempty"`
+	RequestID string    `json:"request_id,omitempty"`
+	QuoteID   int       `json:"quote_id,omitempty"`
+	Before    *Quote    `json:"before,omitempty"`
+	After     *Quote    `json:"after,omitempty"`
+	// AuthorID, AuthorBefore, AuthorAfter - Автор в операциях с авторами.
+	AuthorID     int     `json:"author_id,omitempty"`
+	AuthorBefore *Author `json:"author_before,omitempty"`
+	AuthorAfter  *Author `json:"author_after,omitempty"`
+	// MergedFrom, MovedAliases - При объединении авторов: удалённый автор и его имена,
+	// ставшие псевдонимами автора AuthorID.
+	MergedFrom   *Author  `json:"merged_from,omitempty"`
+	MovedAliases []string `json:"moved_aliases,omitempty"`
+	// PrevHash, Hash - Цепочка хешей. Заполняются только при записи журнала в файл.
+	PrevHash string `json:"prev_hash,omitempty"`
+	Hash     string `json:"hash,omitempty"`
+}
+
+type AuditFilter struct {
+	Actor     string
+	Operation string
+	Tenant    string
+	RequestID string
+	QuoteID   int
+	AuthorID  int
+	Since     time.Time
+	Until     time.Time
+	Limit     int
+}
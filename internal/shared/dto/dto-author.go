@@ -1,7 +1,81 @@
 package dto
 
+import "time"
+
+// Author - Автор и сведения о нём.
 type Author struct {
-	ID         int      `json:"id"`
-	AuthorName string   `json:"author"`
-	Quotes     []*Quote `json:"quotes"`
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	// FullName - Полное имя, если автор известен под псевдонимом или сокращением.
+	FullName string `json:"full_name,omitempty"`
+	// BirthYear, DeathYear - Годы жизни. Отрицательные - до нашей эры.
+	BirthYear *int `json:"birth_year,omitempty"`
+	DeathYear *int `json:"death_year,omitempty"`
+	// Aliases - Другие имена, по которым автор находится при поиске и добавлении цитат.
+	Aliases     []string     `json:"aliases,omitempty"`
+	Nationality string       `json:"nationality,omitempty"`
+	Bio         string       `json:"bio,omitempty"`
+	Links       []AuthorLink `json:"links,omitempty"`
+	// Quotes - Число опубликованных цитат автора.
+	Quotes int `json:"quotes"`
+	// UpdatedAt - Когда сведения менялись в последний раз. Нет - не заполнялись.
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// AuthorLink - Ссылка на внешний источник об авторе.
+type AuthorLink struct {
+	Title string `json:"title,omitempty"`
+	URL   string `json:"url"`
+}
+
+// UpdateAuthor - Новые сведения об авторе. Заменяют прежние целиком: не переданное поле очищается.
+type UpdateAuthor struct {
+	FullName    string       `json:"full_name"`
+	BirthYear   *int         `json:"birth_year"`
+	DeathYear   *int         `json:"death_year"`
+	Aliases     []string     `json:"aliases"`
+	Nationality string       `json:"nationality"`
+	Bio         string       `json:"bio"`
+	Links       []AuthorLink `json:"links"`
+}
+
+// CreateAuthor - Новый автор без цитат. Имя может совпадать с именем другого автора: так заводят тёзок,
+// цитаты к ним добавляются по author_id.
+type CreateAuthor struct {
+	Name        string       `json:"name"`
+	FullName    string       `json:"full_name"`
+	BirthYear   *int         `json:"birth_year"`
+	DeathYear   *int         `json:"death_year"`
+	Aliases     []string     `json:"aliases"`
+	Nationality string       `json:"nationality"`
+	Bio         string       `json:"bio"`
+	Links       []AuthorLink `json:"links"`
+}
+
+// Update - Сведения нового автора в том виде, в каком их проверяет и сохраняет UpdateAuthor.
+func (ca *CreateAuthor) Update() *UpdateAuthor {
+	return &UpdateAuthor{
+		FullName:    ca.FullName,
+		BirthYear:   ca.BirthYear,
+		DeathYear:   ca.DeathYear,
+		Aliases:     ca.Aliases,
+		Nationality: ca.Nationality,
+		Bio:         ca.Bio,
+		Links:       ca.Links,
+	}
+}
+
+// MergeAuthors - Запрос на объединение: цитаты автора из пути переносятся к автору Into.
+type MergeAuthors struct {
+	Into int `json:"into"`
+}
+
+// AuthorMerge - Итог объединения авторов.
+type AuthorMerge struct {
+	// Author - Автор, которому достались цитаты.
+	Author *Author `json:"author"`
+	// Moved - id перенесённых цитат.
+	Moved []int `json:"moved"`
+	// Dropped - id цитат, которые у автора уже были и удалены как дубликаты.
+	Dropped []int `json:"dropped"`
 }
@@ -0,0 +1,13 @@
+package dto
+
+// Citation - Ссылка на цитату в одном из стилей: apa, mla, chicago, bibtex.
+type Citation struct {
+	QuoteID int    `json:"quote_id"`
+	Style   string `json:"style"`
+	Text    string `json:"citation"`
+}
+
+// String - Ссылка в text/plain как есть, без полей.
+func (c Citation) String() string {
+	return c.Text
+}
@@ -0,0 +1,16 @@
+package dto
+
+// ConfigChange - Изменённая настройка. Значения секретов заменены на ***.
+type ConfigChange struct {
+	Key string `json:"key"`
+	Old any    `json:"old"`
+	New any    `json:"new"`
+}
+
+// ConfigReload - Итог перезагрузки настроек.
+type ConfigReload struct {
+	// Applied - Изменения, которые уже действуют.
+	Applied []*ConfigChange `json:"applied"`
+	// RestartRequired - Изменения, которые вступят в силу только после перезапуска.
+	RestartRequired []*ConfigChange `json:"restart_required"`
+}
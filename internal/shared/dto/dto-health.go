@@ -0,0 +1,16 @@
+package dto
+
+type HealthCheck struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+type Health struct {
+	Status string `json:"status"`
+	// Reason - Почему сервис не готов: draining, replaying или failed_checks.
+	Reason    string         `json:"reason,omitempty"`
+	Replaying []string       `json:"replaying,omitempty"`
+	Checks    []*HealthCheck `json:"checks,omitempty"`
+}
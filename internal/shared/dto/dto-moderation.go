@@ -0,0 +1,18 @@
+package dto
+
+// ModerationQueue - Цитаты тенанта, ожидающие решения модератора, от старых к новым.
+type ModerationQueue struct {
+	Pending int `json:"pending"`
+	// OldestWaitSeconds - Сколько ждёт самая старая цитата. 0 - очередь пуста.
+	OldestWaitSeconds int64           `json:"oldest_wait_seconds"`
+	Items             []*PendingQuote `json:"items"`
+}
+
+type PendingQuote struct {
+	Quote
+	WaitSeconds int64 `json:"wait_seconds"`
+}
+
+type RejectQuote struct {
+	Reason string `json:"reason"`
+}
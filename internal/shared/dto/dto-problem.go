@@ -0,0 +1,87 @@
+package dto
+
+// ErrorCode - Стабильный машиночитаемый код ошибки. В отличие от текста ошибки не меняется между версиями.
+type ErrorCode string
+
+const (
+	CodeValidationFailed     ErrorCode = "VALIDATION_FAILED"
+	CodeMalformedBody        ErrorCode = "MALFORMED_BODY"
+	CodeUnauthorized         ErrorCode = "UNAUTHORIZED"
+	CodeForbidden            ErrorCode = "FORBIDDEN"
+	CodeQuotaExceeded        ErrorCode = "QUOTA_EXCEEDED"
+	CodeQuoteNotFound        ErrorCode = "QUOTE_NOT_FOUND"
+	CodeNoQuotes             ErrorCode = "NO_QUOTES"
+	CodeAuthorNotFound       ErrorCode = "AUTHOR_NOT_FOUND"
+	CodeAuthorHasNoQuotes    ErrorCode = "AUTHOR_HAS_NO_QUOTES"
+	CodeTenantNotFound       ErrorCode = "TENANT_NOT_FOUND"
+	CodeKeyNotFound          ErrorCode = "KEY_NOT_FOUND"
+	CodeNotAcceptable        ErrorCode = "NOT_ACCEPTABLE"
+	CodeQuoteAlreadyExists   ErrorCode = "QUOTE_ALREADY_EXISTS"
+	CodeTenantAlreadyExists  ErrorCode = "TENANT_ALREADY_EXISTS"
+	CodeDefaultTenant        ErrorCode = "DEFAULT_TENANT_PROTECTED"
+	CodeKeyRevoked           ErrorCode = "KEY_REVOKED"
+	CodeQuoteNotPending      ErrorCode = "QUOTE_NOT_PENDING"
+	CodeAuthorAmbiguous      ErrorCode = "AUTHOR_AMBIGUOUS"
+	CodeAliasTaken           ErrorCode = "AUTHOR_ALIAS_TAKEN"
+	CodePayloadTooLarge      ErrorCode = "PAYLOAD_TOO_LARGE"
+	CodeUnsupportedMediaType ErrorCode = "UNSUPPORTED_MEDIA_TYPE"
+	CodeContentRejected      ErrorCode = "CONTENT_REJECTED"
+	CodeConfigRejected       ErrorCode = "CONFIG_REJECTED"
+	CodeInternal             ErrorCode = "INTERNAL_ERROR"
+)
+
+// ErrorCodes - Все коды ошибок. Новый код добавляется и сюда, по этому списку проверяются переводы.
+func ErrorCodes() []ErrorCode {
+	return []ErrorCode{
+		CodeValidationFailed,
+		CodeMalformedBody,
+		CodeUnauthorized,
+		CodeForbidden,
+		CodeQuotaExceeded,
+		CodeQuoteNotFound,
+		CodeNoQuotes,
+		CodeAuthorNotFound,
+		CodeAuthorHasNoQuotes,
+		CodeTenantNotFound,
+		CodeKeyNotFound,
+		CodeNotAcceptable,
+		CodeQuoteAlreadyExists,
+		CodeTenantAlreadyExists,
+		CodeDefaultTenant,
+		CodeKeyRevoked,
+		CodeQuoteNotPending,
+		CodeAuthorAmbiguous,
+		CodeAliasTaken,
+		CodePayloadTooLarge,
+		CodeUnsupportedMediaType,
+		CodeContentRejected,
+		CodeConfigRejected,
+		CodeInternal,
+	}
+}
+
+// Problem - Ответ с ошибкой в формате RFC 7807 (application/problem+json).
+type Problem struct {
+	// Type - Ссылка на описание типа ошибки. about:blank - тип определяется статусом и кодом.
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail"`
+	// Instance - Путь запроса, на который пришла ошибка.
+	Instance  string    `json:"instance,omitempty"`
+	Code      ErrorCode `json:"code"`
+	RequestID string    `json:"request_id,omitempty"`
+	// Errors - Поля запроса, которые не прошли проверку.
+	Errors []FieldError `json:"errors,omitempty"`
+}
+
+// String - В text/plain ошибка отдаётся одной строкой.
+func (p Problem) String() string {
+	return p.Detail
+}
+
+// FieldError - Ошибка проверки одного поля запроса.
+type FieldError struct {
+	Field  string `json:"field"`
+	Detail string `json:"detail"`
+}
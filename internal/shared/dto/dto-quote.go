@@ -1,7 +1,74 @@
 package dto
 
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// quoteSeparator - Разделитель текста и автора в text/plain.
+const quoteSeparator = "—"
+
+// Состояния модерации цитаты.
+const (
+	// QuotePending - Ждёт решения модератора, в выдаче не участвует.
+	QuotePending  = "pending"
+	QuoteApproved = "approved"
+	// QuoteRejected - Отклонена и удалена из коллекции. Встречается только в ответе на отклонение и в журнале аудита.
+	QuoteRejected = "rejected"
+)
+
 type Quote struct {
 	ID         int    `json:"id"`
 	Text       string `json:"quote"`
 	AuthorName string `json:"author"`
+	// AuthorID - Автор по id. В запросе заменяет author: так цитату добавляют одному из тёзок.
+	AuthorID int `json:"author_id,omitempty"`
+	// CreatedBy - Кто добавил цитату (subject из токена). Проставляется сервисом.
+	CreatedBy string `json:"created_by,omitempty"`
+	// Status - Состояние модерации. Проставляется сервисом.
+	Status string `json:"status,omitempty"`
+	// CreatedAt - Когда цитата добавлена. Проставляется сервисом.
+	CreatedAt time.Time `json:"created_at"`
+	// ModeratedBy, ModeratedAt - Кто и когда принял решение по цитате.
+	ModeratedBy string     `json:"moderated_by,omitempty"`
+	ModeratedAt *time.Time `json:"moderated_at,omitempty"`
+	// RejectReason - Причина отклонения.
+	RejectReason string `json:"reject_reason,omitempty"`
+	// Flags - Фильтры содержимого, из-за которых цитата ушла на модерацию. Проставляется сервисом.
+	Flags []string `json:"flags,omitempty"`
+	// Source - Источник цитаты, необязателен.
+	Source *Source `json:"source,omitempty"`
+	// AuthorDetails - Сведения об авторе, только при ?expand=author.
+	AuthorDetails *Author `json:"author_details,omitempty"`
+}
+
+// String - Цитата в text/plain: "текст — автор".
+func (q Quote) String() string {
+	return q.Text + " " + quoteSeparator + " " + q.AuthorName
+}
+
+// ParseText - Разбирает строку "текст — автор". Разделителем считается последнее тире,
+// так что тире внутри текста цитаты допустимо.
+func (q *Quote) ParseText(line string) error {
+	i := strings.LastIndex(line, quoteSeparator)
+	if i < 0 {
+		return errors.New("ожидается строка вида «текст — автор»")
+	}
+	q.Text = strings.TrimSpace(line[:i])
+	q.AuthorName = strings.TrimSpace(line[i+len(quoteSeparator):])
+	return nil
+}
+
+// Source - Источник цитаты: книга, статья, речь. Обязательно только название.
+type Source struct {
+	// Type - Вид источника: book, article, speech, interview, letter, web, other.
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	// Publisher - Издательство, журнал или событие, на котором прозвучала речь.
+	Publisher string `json:"publisher,omitempty"`
+	Year      *int   `json:"year,omitempty"`
+	// Pages - Страница или диапазон: "42", "42-45".
+	Pages string `json:"pages,omitempty"`
+	URL   string `json:"url,omitempty"`
 }
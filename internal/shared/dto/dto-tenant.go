@@ -0,0 +1,24 @@
+package dto
+
+import "time"
+
+type Quota struct {
+	MaxQuotes  int `json:"max_quotes"`
+	MaxAuthors int `json:"max_authors"`
+}
+
+type Tenant struct {
+	Name    string `json:"name"`
+	Quota   Quota  `json:"quota"`
+	Quotes  int    `json:"quotes"`
+	Authors int    `json:"authors"`
+	// Pending - Цитаты на модерации, они входят и в Quotes.
+	Pending   int       `json:"pending"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CreateTenant struct {
+	Name string `json:"name"`
+	// Quota - Если не указана, применяется квота по умолчанию.
+	Quota *Quota `json:"quota,omitempty"`
+}
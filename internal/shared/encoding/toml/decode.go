@@ -0,0 +1,580 @@
+// Package toml - Разбор TOML 1.0 для файлов настроек: таблицы и массивы таблиц, составные ключи,
+// все виды строк, целые и дробные числа, логические значения, массивы и inline-таблицы.
+// Даты и время возвращаются строкой в исходном виде.
+package toml
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Unmarshal - Разбирает документ в map[string]any. Значения: string, int64, float64, bool, []any, map[string]any.
+func Unmarshal(data []byte) (map[string]any, error) {
+	if !utf8.Valid(data) {
+		return nil, &SyntaxError{Line: 1, Msg: "файл не в UTF-8"}
+	}
+	p := &parser{src: strings.ReplaceAll(string(data), "\r\n", "\n"), line: 1}
+	p.root = make(map[string]any)
+	p.current = p.root
+	p.defined = make(map[string]bool)
+	if err := p.document(); err != nil {
+		return nil, err
+	}
+	return p.root, nil
+}
+
+type SyntaxError struct {
+	Line int
+	Msg  string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("toml: строка %d: %s", e.Line, e.Msg)
+}
+
+type parser struct {
+	src     string
+	pos     int
+	line    int
+	root    map[string]any
+	current map[string]any
+	// defined - Таблицы, объявленные заголовком [a.b]. Повторное объявление - ошибка.
+	defined map[string]bool
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return &SyntaxError{Line: p.line, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) eof() bool { return p.pos >= len(p.src) }
+
+func (p *parser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *parser) advance() byte {
+	c := p.src[p.pos]
+	p.pos++
+	if c == '\n' {
+		p.line++
+	}
+	return c
+}
+
+// skipSpace - Пробелы и табуляция внутри строки.
+func (p *parser) skipSpace() {
+	for !p.eof() && (p.peek() == ' ' || p.peek() == '\t') {
+		p.pos++
+	}
+}
+
+func (p *parser) skipComment() {
+	if p.peek() == '#' {
+		for !p.eof() && p.peek() != '\n' {
+			p.pos++
+		}
+	}
+}
+
+// skipWhitespace - Пробелы, переводы строк и комментарии (внутри массивов).
+func (p *parser) skipWhitespace() {
+	for !p.eof() {
+		switch p.peek() {
+		case ' ', '\t', '\n':
+			p.advance()
+		case '#':
+			p.skipComment()
+		default:
+			return
+		}
+	}
+}
+
+// endOfLine - После выражения допустимы только пробелы и комментарий.
+func (p *parser) endOfLine() error {
+	p.skipSpace()
+	p.skipComment()
+	if p.eof() {
+		return nil
+	}
+	if p.peek() != '\n' {
+		return p.errorf("ожидается конец строки, получено %q", p.peek())
+	}
+	p.advance()
+	return nil
+}
+
+func (p *parser) document() error {
+	for {
+		p.skipWhitespace()
+		if p.eof() {
+			return nil
+		}
+
+		var err error
+		if p.peek() == '[' {
+			err = p.tableHeader()
+		} else {
+			err = p.keyValue(p.current)
+		}
+		if err != nil {
+			return err
+		}
+		if err := p.endOfLine(); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *parser) tableHeader() error {
+	p.advance()
+	array := p.peek() == '['
+	if array {
+		p.advance()
+	}
+
+	p.skipSpace()
+	keys, err := p.key()
+	if err != nil {
+		return err
+	}
+	p.skipSpace()
+	// Скобки проверяются до сдвига, чтобы ошибка указывала на строку заголовка, а не на следующую.
+	if p.peek() != ']' || (array && !strings.HasPrefix(p.src[p.pos:], "]]")) {
+		return p.errorf("не закрыт заголовок таблицы")
+	}
+	p.pos++
+	if array {
+		p.pos++
+	}
+
+	parent, err := p.descend(p.root, keys[:len(keys)-1], true)
+	if err != nil {
+		return err
+	}
+	last := keys[len(keys)-1]
+	path := strings.Join(keys, "\x00")
+
+	if array {
+		existing, ok := parent[last]
+		if !ok {
+			existing = []any{}
+		}
+		list, ok := existing.([]any)
+		if !ok {
+			return p.errorf("ключ %q уже задан не массивом таблиц", strings.Join(keys, "."))
+		}
+		table := make(map[string]any)
+		parent[last] = append(list, table)
+		p.current = table
+		return nil
+	}
+
+	if p.defined[path] {
+		return p.errorf("таблица [%s] объявлена повторно", strings.Join(keys, "."))
+	}
+	p.defined[path] = true
+
+	table, err := p.descend(parent, []string{last}, true)
+	if err != nil {
+		return err
+	}
+	p.current = table
+	return nil
+}
+
+// descend - Спускается по ключам, создавая промежуточные таблицы. Для массива таблиц берёт последний элемент.
+func (p *parser) descend(table map[string]any, keys []string, create bool) (map[string]any, error) {
+	for _, k := range keys {
+		v, ok := table[k]
+		if !ok {
+			if !create {
+				return nil, p.errorf("таблица %q не найдена", k)
+			}
+			next := make(map[string]any)
+			table[k] = next
+			table = next
+			continue
+		}
+		switch t := v.(type) {
+		case map[string]any:
+			table = t
+		case []any:
+			if len(t) == 0 {
+				return nil, p.errorf("ключ %q - пустой массив", k)
+			}
+			last, ok := t[len(t)-1].(map[string]any)
+			if !ok {
+				return nil, p.errorf("ключ %q уже задан значением", k)
+			}
+			table = last
+		default:
+			return nil, p.errorf("ключ %q уже задан значением", k)
+		}
+	}
+	return table, nil
+}
+
+func (p *parser) keyValue(table map[string]any) error {
+	keys, err := p.key()
+	if err != nil {
+		return err
+	}
+	p.skipSpace()
+	if p.eof() || p.advance() != '=' {
+		return p.errorf("ожидается = после ключа %q", strings.Join(keys, "."))
+	}
+	p.skipSpace()
+
+	value, err := p.value()
+	if err != nil {
+		return err
+	}
+
+	parent, err := p.descend(table, keys[:len(keys)-1], true)
+	if err != nil {
+		return err
+	}
+	last := keys[len(keys)-1]
+	if _, exists := parent[last]; exists {
+		return p.errorf("ключ %q задан повторно", strings.Join(keys, "."))
+	}
+	parent[last] = value
+	return nil
+}
+
+// key - Простой, в кавычках или составной ключ через точку.
+func (p *parser) key() ([]string, error) {
+	var keys []string
+	for {
+		p.skipSpace()
+		var k string
+		switch c := p.peek(); {
+		case c == '"':
+			s, err := p.basicString()
+			if err != nil {
+				return nil, err
+			}
+			k = s
+		case c == '\'':
+			s, err := p.literalString()
+			if err != nil {
+				return nil, err
+			}
+			k = s
+		default:
+			start := p.pos
+			for !p.eof() && isBareKeyChar(p.peek()) {
+				p.pos++
+			}
+			if start == p.pos {
+				return nil, p.errorf("ожидается ключ")
+			}
+			k = p.src[start:p.pos]
+		}
+		keys = append(keys, k)
+
+		p.skipSpace()
+		if p.peek() != '.' {
+			return keys, nil
+		}
+		p.advance()
+	}
+}
+
+func isBareKeyChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+func (p *parser) value() (any, error) {
+	if p.eof() {
+		return nil, p.errorf("ожидается значение")
+	}
+
+	switch c := p.peek(); {
+	case c == '"':
+		if strings.HasPrefix(p.src[p.pos:], `"""`) {
+			return p.multilineBasicString()
+		}
+		return p.basicString()
+	case c == '\'':
+		if strings.HasPrefix(p.src[p.pos:], `'''`) {
+			return p.multilineLiteralString()
+		}
+		return p.literalString()
+	case c == '[':
+		return p.array()
+	case c == '{':
+		return p.inlineTable()
+	case strings.HasPrefix(p.src[p.pos:], "true"):
+		p.pos += 4
+		return true, nil
+	case strings.HasPrefix(p.src[p.pos:], "false"):
+		p.pos += 5
+		return false, nil
+	default:
+		return p.number()
+	}
+}
+
+func (p *parser) basicString() (string, error) {
+	p.advance()
+	var b strings.Builder
+	for {
+		if p.eof() || p.peek() == '\n' {
+			return "", p.errorf("не закрыта строка")
+		}
+		c := p.advance()
+		switch c {
+		case '"':
+			return b.String(), nil
+		case '\\':
+			if err := p.escape(&b); err != nil {
+				return "", err
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+}
+
+func (p *parser) multilineBasicString() (string, error) {
+	p.pos += 3
+	// Перевод строки сразу после открывающих кавычек не входит в значение.
+	if p.peek() == '\n' {
+		p.advance()
+	}
+	var b strings.Builder
+	for {
+		if p.eof() {
+			return "", p.errorf("не закрыта многострочная строка")
+		}
+		if strings.HasPrefix(p.src[p.pos:], `"""`) {
+			p.pos += 3
+			// До двух кавычек прямо перед закрывающими входят в значение.
+			for i := 0; i < 2 && p.peek() == '"'; i++ {
+				b.WriteByte(p.advance())
+			}
+			return b.String(), nil
+		}
+		c := p.advance()
+		if c != '\\' {
+			b.WriteByte(c)
+			continue
+		}
+		// Обратная косая черта в конце строки склеивает строки и съедает пробелы.
+		rest := p.pos
+		for rest < len(p.src) && (p.src[rest] == ' ' || p.src[rest] == '\t') {
+			rest++
+		}
+		if rest < len(p.src) && p.src[rest] == '\n' {
+			p.pos = rest
+			for !p.eof() && strings.ContainsRune(" \t\n", rune(p.peek())) {
+				p.advance()
+			}
+			continue
+		}
+		if err := p.escape(&b); err != nil {
+			return "", err
+		}
+	}
+}
+
+func (p *parser) escape(b *strings.Builder) error {
+	if p.eof() {
+		return p.errorf("незавершённая escape-последовательность")
+	}
+	c := p.advance()
+	switch c {
+	case 'b':
+		b.WriteByte('\b')
+	case 't':
+		b.WriteByte('\t')
+	case 'n':
+		b.WriteByte('\n')
+	case 'f':
+		b.WriteByte('\f')
+	case 'r':
+		b.WriteByte('\r')
+	case 'e':
+		b.WriteByte(0x1b)
+	case '"':
+		b.WriteByte('"')
+	case '\\':
+		b.WriteByte('\\')
+	case 'u', 'U':
+		n := 4
+		if c == 'U' {
+			n = 8
+		}
+		if p.pos+n > len(p.src) {
+			return p.errorf("некорректная escape-последовательность \\%c", c)
+		}
+		code, err := strconv.ParseUint(p.src[p.pos:p.pos+n], 16, 32)
+		if err != nil || !utf8.ValidRune(rune(code)) {
+			return p.errorf("некорректная escape-последовательность \\%c%s", c, p.src[p.pos:p.pos+n])
+		}
+		p.pos += n
+		b.WriteRune(rune(code))
+	default:
+		return p.errorf("неизвестная escape-последовательность \\%c", c)
+	}
+	return nil
+}
+
+func (p *parser) literalString() (string, error) {
+	p.advance()
+	start := p.pos
+	for {
+		if p.eof() || p.peek() == '\n' {
+			return "", p.errorf("не закрыта строка")
+		}
+		if p.advance() == '\'' {
+			return p.src[start : p.pos-1], nil
+		}
+	}
+}
+
+func (p *parser) multilineLiteralString() (string, error) {
+	p.pos += 3
+	if p.peek() == '\n' {
+		p.advance()
+	}
+	start := p.pos
+	for {
+		if p.eof() {
+			return "", p.errorf("не закрыта многострочная строка")
+		}
+		if strings.HasPrefix(p.src[p.pos:], "'''") {
+			end := p.pos
+			p.pos += 3
+			for i := 0; i < 2 && p.peek() == '\''; i++ {
+				p.pos++
+				end++
+			}
+			return p.src[start:end], nil
+		}
+		p.advance()
+	}
+}
+
+func (p *parser) array() (any, error) {
+	p.advance()
+	items := []any{}
+	for {
+		p.skipWhitespace()
+		if p.eof() {
+			return nil, p.errorf("не закрыт массив")
+		}
+		if p.peek() == ']' {
+			p.advance()
+			return items, nil
+		}
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, v)
+		p.skipWhitespace()
+		if p.peek() == ',' {
+			p.advance()
+		} else if p.peek() != ']' {
+			return nil, p.errorf("ожидается , или ] в массиве")
+		}
+	}
+}
+
+func (p *parser) inlineTable() (any, error) {
+	p.advance()
+	table := make(map[string]any)
+	p.skipSpace()
+	if p.peek() == '}' {
+		p.advance()
+		return table, nil
+	}
+	for {
+		p.skipSpace()
+		if err := p.keyValue(table); err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		switch {
+		case p.peek() == ',':
+			p.advance()
+		case p.peek() == '}':
+			p.advance()
+			return table, nil
+		default:
+			return nil, p.errorf("ожидается , или } в inline-таблице")
+		}
+	}
+}
+
+// number - Целое, дробное, inf/nan. Даты и время возвращаются строкой.
+func (p *parser) number() (any, error) {
+	start := p.pos
+	for !p.eof() && !strings.ContainsRune(" \t\n,]}#", rune(p.peek())) {
+		p.pos++
+	}
+	raw := p.src[start:p.pos]
+	// Время вида 07:32:00 и даты с пробелом между датой и временем.
+	if isDateTime(raw) {
+		if p.peek() == ' ' && p.pos+1 < len(p.src) && p.src[p.pos+1] >= '0' && p.src[p.pos+1] <= '9' {
+			p.pos++
+			for !p.eof() && !strings.ContainsRune(" \t\n,]}#", rune(p.peek())) {
+				p.pos++
+			}
+		}
+		return p.src[start:p.pos], nil
+	}
+
+	switch raw {
+	case "inf", "+inf":
+		return math.Inf(1), nil
+	case "-inf":
+		return math.Inf(-1), nil
+	case "nan", "+nan", "-nan":
+		return math.NaN(), nil
+	}
+
+	if raw == "" {
+		return nil, p.errorf("ожидается значение")
+	}
+	clean := raw
+	if strings.Contains(raw, "_") {
+		if strings.Contains(raw, "__") || strings.HasPrefix(raw, "_") || strings.HasSuffix(raw, "_") {
+			return nil, p.errorf("некорректное число %q", raw)
+		}
+		clean = strings.ReplaceAll(raw, "_", "")
+	}
+
+	if strings.HasPrefix(clean, "0x") || strings.HasPrefix(clean, "0o") || strings.HasPrefix(clean, "0b") {
+		v, err := strconv.ParseInt(clean, 0, 64)
+		if err != nil {
+			return nil, p.errorf("некорректное число %q", raw)
+		}
+		return v, nil
+	}
+	digits := strings.TrimLeft(clean, "+-")
+	if len(digits) > 1 && digits[0] == '0' && digits[1] != '.' && digits[1] != 'e' && digits[1] != 'E' {
+		return nil, p.errorf("ведущие нули в числе %q недопустимы", raw)
+	}
+	if v, err := strconv.ParseInt(clean, 10, 64); err == nil {
+		return v, nil
+	}
+	v, err := strconv.ParseFloat(clean, 64)
+	if err != nil || strings.ContainsAny(clean, "xXpP") || strings.HasPrefix(digits, ".") || strings.HasSuffix(clean, ".") {
+		return nil, p.errorf("некорректное значение %q", raw)
+	}
+	return v, nil
+}
+
+func isDateTime(s string) bool {
+	return len(s) >= 8 && (len(s) >= 10 && s[4] == '-' && s[7] == '-' || s[2] == ':' && s[5] == ':')
+}
@@ -0,0 +1,123 @@
+package toml_test
+
+import (
+	"errors"
+	"go-offline-test/internal/shared/encoding/toml"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestUnmarshal(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want map[string]any
+	}{
+		{"пустой документ", "", map[string]any{}},
+		{"скаляры", "i = 42\nf = 1.5\nb = true\nn = 0x1F\nu = 1_000\ne = 1e3\n", map[string]any{
+			"i": int64(42), "f": 1.5, "b": true, "n": int64(31), "u": int64(1000), "e": 1000.0,
+		}},
+		{"строки", `basic = "a\tb \u0416"` + "\nliteral = 'C:\\path'\n", map[string]any{"basic": "a\tb Ж", "literal": `C:\path`}},
+		{"многострочные строки", "a = \"\"\"\nстрока 1\\\n   строка 2\"\"\"\nb = '''\nx\n'y'\n'''\n", map[string]any{
+			"a": "строка 1строка 2", "b": "x\n'y'\n",
+		}},
+		{"комментарии", "# заголовок\na = 1 # после значения\ns = \"# не комментарий\"\n", map[string]any{
+			"a": int64(1), "s": "# не комментарий",
+		}},
+		{"таблицы и составные ключи", "[server]\nport = 8080\ntls.enabled = false\n[server.limits]\n\"max body\" = 1024\n", map[string]any{
+			"server": map[string]any{
+				"port":   int64(8080),
+				"tls":    map[string]any{"enabled": false},
+				"limits": map[string]any{"max body": int64(1024)},
+			},
+		}},
+		{"массивы таблиц", "[[rule]]\nname = \"a\"\n[[rule]]\nname = \"b\"\n[rule.limit]\nmax = 5\n", map[string]any{
+			"rule": []any{
+				map[string]any{"name": "a"},
+				map[string]any{"name": "b", "limit": map[string]any{"max": int64(5)}},
+			},
+		}},
+		{"массивы и inline-таблицы", "a = [1, \"два\", [3],\n  # комментарий\n  {k = \"v\"},\n]\nt = {x = 1, y.z = 2}\n", map[string]any{
+			"a": []any{int64(1), "два", []any{int64(3)}, map[string]any{"k": "v"}},
+			"t": map[string]any{"x": int64(1), "y": map[string]any{"z": int64(2)}},
+		}},
+		{"даты строкой", "d = 1979-05-27\ndt = 1979-05-27 07:32:00Z\nt = 07:32:00\n", map[string]any{
+			"d": "1979-05-27", "dt": "1979-05-27 07:32:00Z", "t": "07:32:00",
+		}},
+		{"CRLF и кириллица в ключе в кавычках", "\"автор\" = \"Толстой\"\r\n", map[string]any{"автор": "Толстой"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := toml.Unmarshal([]byte(tt.in))
+			if err != nil {
+				t.Fatalf("Unmarshal(%q): %v", tt.in, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Unmarshal(%q) = %#v, ожидалось %#v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshalSpecialFloats(t *testing.T) {
+	got, err := toml.Unmarshal([]byte("a = inf\nb = -inf\nc = nan\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !math.IsInf(got["a"].(float64), 1) || !math.IsInf(got["b"].(float64), -1) || !math.IsNaN(got["c"].(float64)) {
+		t.Errorf("получено %v", got)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		line int
+		msg  string
+	}{
+		{"не UTF-8", "a = \"\xff\"", 1, "UTF-8"},
+		{"нет =", "a 1", 1, "ожидается ="},
+		{"нет значения", "a =", 1, "ожидается значение"},
+		{"повтор ключа", "a = 1\na = 2\n", 2, "задан повторно"},
+		{"повтор таблицы", "[a]\n[a]\n", 2, "объявлена повторно"},
+		{"ключ уже значение", "a = 1\n[a.b]\n", 2, "уже задан значением"},
+		{"не закрыт заголовок", "[a\n", 1, "не закрыт заголовок"},
+		{"не закрыта строка", "a = \"x\nb = 1\n", 1, "не закрыта строка"},
+		{"неизвестная escape", `a = "\q"`, 1, "неизвестная escape"},
+		{"скобка } в массиве", "a = [}", 1, "ожидается значение"},
+		{"элементы без запятой", "a = [1 2]", 1, "ожидается , или ]"},
+		{"не закрыт массив", "a = [1,\n", 2, "не закрыт массив"},
+		{"скобка ] в inline-таблице", "a = {b = 1]", 1, "ожидается , или }"},
+		{"ведущие нули", "a = 007", 1, "ведущие нули"},
+		{"двойное подчёркивание", "a = 1__0", 1, "некорректное число"},
+		{"мусор после значения", "a = 1 b", 1, "ожидается конец строки"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := toml.Unmarshal([]byte(tt.in))
+			var syntax *toml.SyntaxError
+			if !errors.As(err, &syntax) {
+				t.Fatalf("Unmarshal(%q): ожидалась SyntaxError, получено %v", tt.in, err)
+			}
+			if syntax.Line != tt.line || !strings.Contains(syntax.Msg, tt.msg) {
+				t.Errorf("Unmarshal(%q) = %v, ожидалась строка %d и %q", tt.in, err, tt.line, tt.msg)
+			}
+		})
+	}
+}
+
+// FuzzUnmarshal - Разбор любого ввода завершается: значением или ошибкой, без паники и зацикливания.
+// Начальный корпус - в testdata/fuzz/FuzzUnmarshal.
+func FuzzUnmarshal(f *testing.F) {
+	f.Add([]byte("[server]\nport = 8080\n"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		v, err := toml.Unmarshal(data)
+		if err != nil && v != nil {
+			t.Errorf("Unmarshal(%q) вернул и значение, и ошибку %v", data, err)
+		}
+	})
+}
@@ -0,0 +1,621 @@
+// Package yaml - Разбор и вывод подмножества YAML 1.2, которого хватает для файлов настроек и ответов API:
+// блочные отображения и последовательности, flow-коллекции [a, b] и {a: b}, строки в кавычках,
+// блочные скаляры | и >, комментарии. Якоря, теги и несколько документов в одном файле не поддерживаются.
+package yaml
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Limits - Ограничения разбора для ввода из недоверенных источников. Нулевое поле - без ограничения.
+type Limits struct {
+	// MaxBytes - Размер документа.
+	MaxBytes int
+	// MaxDepth - Вложенность коллекций, блочных и flow вместе.
+	MaxDepth int
+	// MaxNodes - Число значений в документе: коллекций и скаляров.
+	MaxNodes int
+}
+
+// DefaultLimits - Ограничения Unmarshal, с запасом для файлов настроек.
+var DefaultLimits = Limits{MaxBytes: 16 << 20, MaxDepth: 100, MaxNodes: 1 << 20}
+
+// Unmarshal - Разбирает документ в map[string]any, []any, string, int64, float64, bool или nil.
+func Unmarshal(data []byte) (any, error) {
+	return UnmarshalLimits(data, DefaultLimits)
+}
+
+// UnmarshalLimits - Unmarshal с ограничениями limits. Превышение - SyntaxError.
+func UnmarshalLimits(data []byte, limits Limits) (any, error) {
+	if limits.MaxBytes > 0 && len(data) > limits.MaxBytes {
+		return nil, &SyntaxError{Line: 1, Msg: fmt.Sprintf("документ больше %d байт", limits.MaxBytes)}
+	}
+	p := &parser{budget: &budget{limits: limits}}
+	for i, raw := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		if strings.Contains(raw, "\t") && strings.TrimLeft(raw, " ") != strings.TrimLeft(raw, " \t") {
+			return nil, &SyntaxError{Line: i + 1, Msg: "табуляция в отступе недопустима"}
+		}
+		p.lines = append(p.lines, line{num: i + 1, indent: len(raw) - len(strings.TrimLeft(raw, " ")), raw: raw})
+	}
+	p.skipBlank()
+	if p.pos < len(p.lines) && strings.TrimSpace(p.lines[p.pos].raw) == "---" {
+		p.pos++
+		p.skipBlank()
+	}
+	if p.pos >= len(p.lines) {
+		return nil, nil
+	}
+
+	v, err := p.parseNode(p.lines[p.pos].indent)
+	if err != nil {
+		return nil, err
+	}
+	p.skipBlank()
+	if p.pos < len(p.lines) {
+		return nil, p.errorf("лишнее содержимое на уровне отступа %d", p.lines[p.pos].indent)
+	}
+	return v, nil
+}
+
+// SyntaxError - Ошибка разбора с номером строки.
+type SyntaxError struct {
+	Line int
+	Msg  string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("yaml: строка %d: %s", e.Line, e.Msg)
+}
+
+type line struct {
+	num    int
+	indent int
+	raw    string
+	// text - Содержимое строки без отступа и комментария. Заполняется лениво, может быть переписано
+	// при разборе элементов последовательности вида "- key: value".
+	text string
+	set  bool
+}
+
+type parser struct {
+	lines  []line
+	pos    int
+	budget *budget
+}
+
+// budget - Расход ограничений Limits, общий для блочного и flow-разбора документа.
+type budget struct {
+	limits Limits
+	depth  int
+	nodes  int
+}
+
+// node - Учитывает очередное значение.
+func (b *budget) node() error {
+	b.nodes++
+	if b.limits.MaxNodes > 0 && b.nodes > b.limits.MaxNodes {
+		return fmt.Errorf("больше %d значений в документе", b.limits.MaxNodes)
+	}
+	return nil
+}
+
+// enter - Учитывает вход в коллекцию. После успешного enter нужен leave.
+func (b *budget) enter() error {
+	if err := b.node(); err != nil {
+		return err
+	}
+	if b.limits.MaxDepth > 0 && b.depth >= b.limits.MaxDepth {
+		return fmt.Errorf("вложенность больше %d", b.limits.MaxDepth)
+	}
+	b.depth++
+	return nil
+}
+
+func (b *budget) leave() {
+	b.depth--
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	num := 0
+	if p.pos < len(p.lines) {
+		num = p.lines[p.pos].num
+	} else if len(p.lines) > 0 {
+		num = p.lines[len(p.lines)-1].num
+	}
+	return &SyntaxError{Line: num, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) text(i int) string {
+	l := &p.lines[i]
+	if !l.set {
+		l.text = strings.TrimRight(stripComment(l.raw[l.indent:]), " ")
+		l.set = true
+	}
+	return l.text
+}
+
+// skipBlank - Пропускает пустые строки и строки из одного комментария.
+func (p *parser) skipBlank() {
+	for p.pos < len(p.lines) && p.text(p.pos) == "" {
+		p.pos++
+	}
+}
+
+// parseNode - Разбирает узел, который начинается на текущей строке с отступом indent.
+func (p *parser) parseNode(indent int) (any, error) {
+	text := p.text(p.pos)
+	switch {
+	case text == "-" || strings.HasPrefix(text, "- "):
+		return p.parseSequence(indent)
+	case isMappingLine(text):
+		return p.parseMapping(indent)
+	default:
+		p.pos++
+		v, err := p.parseInline(text)
+		if err != nil {
+			p.pos--
+			return nil, p.errorf("%v", err)
+		}
+		return v, nil
+	}
+}
+
+func (p *parser) parseMapping(indent int) (any, error) {
+	if err := p.budget.enter(); err != nil {
+		return nil, p.errorf("%v", err)
+	}
+	defer p.budget.leave()
+	m := make(map[string]any)
+	for {
+		p.skipBlank()
+		if p.pos >= len(p.lines) || p.lines[p.pos].indent < indent {
+			return m, nil
+		}
+		if p.lines[p.pos].indent > indent {
+			return nil, p.errorf("неожиданный отступ")
+		}
+
+		text := p.text(p.pos)
+		key, rest, ok := splitKey(text)
+		if !ok {
+			return nil, p.errorf("ожидается ключ: значение, получено %q", text)
+		}
+		if _, dup := m[key]; dup {
+			return nil, p.errorf("ключ %q повторяется", key)
+		}
+
+		v, err := p.parseValue(indent, rest, true)
+		if err != nil {
+			return nil, err
+		}
+		m[key] = v
+	}
+}
+
+func (p *parser) parseSequence(indent int) (any, error) {
+	if err := p.budget.enter(); err != nil {
+		return nil, p.errorf("%v", err)
+	}
+	defer p.budget.leave()
+	items := []any{}
+	for {
+		p.skipBlank()
+		if p.pos >= len(p.lines) || p.lines[p.pos].indent < indent {
+			return items, nil
+		}
+		if p.lines[p.pos].indent > indent {
+			return nil, p.errorf("неожиданный отступ")
+		}
+
+		text := p.text(p.pos)
+		if text != "-" && !strings.HasPrefix(text, "- ") {
+			// Отображение на том же уровне после списка - конец списка, его разберёт вызывающий.
+			return items, nil
+		}
+
+		rest := strings.TrimLeft(strings.TrimPrefix(text, "-"), " ")
+		if rest == "" {
+			p.pos++
+			v, err := p.parseNested(indent, false)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, v)
+			continue
+		}
+
+		// "- key: value" и "- - x": содержимое после дефиса разбираем как узел с отступом до его начала.
+		if isMappingLine(rest) || rest == "-" || strings.HasPrefix(rest, "- ") {
+			l := &p.lines[p.pos]
+			l.indent += len(text) - len(rest)
+			l.text = rest
+			v, err := p.parseNode(l.indent)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, v)
+			continue
+		}
+
+		v, err := p.parseValue(indent, rest, false)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, v)
+	}
+}
+
+// parseValue - Разбирает значение после "key:" или "- ". Текущая строка - строка с ключом.
+func (p *parser) parseValue(indent int, rest string, inMapping bool) (any, error) {
+	if rest == "" {
+		p.pos++
+		return p.parseNested(indent, inMapping)
+	}
+	if rest == "|" || rest == ">" || strings.HasPrefix(rest, "|-") || strings.HasPrefix(rest, ">-") ||
+		strings.HasPrefix(rest, "|+") || strings.HasPrefix(rest, ">+") {
+		p.pos++
+		return p.parseBlockScalar(indent, rest)
+	}
+
+	v, err := p.parseInline(rest)
+	if err != nil {
+		return nil, p.errorf("%v", err)
+	}
+	p.pos++
+	return v, nil
+}
+
+// parseNested - Значение на следующих строках. В отображении список может стоять на том же отступе, что и ключ.
+func (p *parser) parseNested(indent int, inMapping bool) (any, error) {
+	p.skipBlank()
+	if p.pos >= len(p.lines) {
+		return nil, nil
+	}
+	next := p.lines[p.pos]
+	text := p.text(p.pos)
+	isSeq := text == "-" || strings.HasPrefix(text, "- ")
+	if next.indent > indent || (inMapping && next.indent == indent && isSeq) {
+		return p.parseNode(next.indent)
+	}
+	return nil, nil
+}
+
+func (p *parser) parseBlockScalar(indent int, header string) (any, error) {
+	if err := p.budget.node(); err != nil {
+		return nil, p.errorf("%v", err)
+	}
+	folded := header[0] == '>'
+	chomp := byte(0)
+	if len(header) > 1 {
+		chomp = header[1]
+	}
+
+	var lines []string
+	blockIndent := -1
+	for p.pos < len(p.lines) {
+		l := p.lines[p.pos]
+		if strings.TrimSpace(l.raw) == "" {
+			lines = append(lines, "")
+			p.pos++
+			continue
+		}
+		if l.indent <= indent {
+			break
+		}
+		if blockIndent < 0 {
+			blockIndent = l.indent
+		}
+		if l.indent < blockIndent {
+			break
+		}
+		lines = append(lines, l.raw[blockIndent:])
+		p.pos++
+	}
+
+	// Хвостовые пустые строки относятся к разделителю, а не к значению.
+	trailing := 0
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+		trailing++
+	}
+
+	var s string
+	if folded {
+		var b strings.Builder
+		for i, l := range lines {
+			// Пустая строка даёт перевод строки, соседние с ней строки не склеиваются пробелом.
+			switch {
+			case i == 0:
+			case l == "":
+				b.WriteByte('\n')
+			case lines[i-1] == "":
+			default:
+				b.WriteByte(' ')
+			}
+			b.WriteString(l)
+		}
+		s = b.String()
+	} else {
+		s = strings.Join(lines, "\n")
+	}
+
+	switch chomp {
+	case '-':
+	case '+':
+		s += strings.Repeat("\n", trailing+1)
+	default:
+		if len(lines) > 0 {
+			s += "\n"
+		}
+	}
+	return s, nil
+}
+
+// stripComment - Отрезает комментарий, не трогая # внутри кавычек и в середине слова.
+func stripComment(s string) string {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == '\'' && quote == '\'' && i+1 < len(s) && s[i+1] == '\'' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			if i == 0 || strings.ContainsRune(" \t[{,:-", rune(s[i-1])) {
+				quote = c
+			}
+		case c == '#' && (i == 0 || s[i-1] == ' ' || s[i-1] == '\t'):
+			return s[:i]
+		}
+	}
+	return s
+}
+
+func isMappingLine(text string) bool {
+	_, _, ok := splitKey(text)
+	return ok
+}
+
+// splitKey - Делит "key: value" на ключ и значение. Ключ может быть в кавычках.
+func splitKey(text string) (string, string, bool) {
+	if text == "" || text[0] == '[' || text[0] == '{' {
+		return "", "", false
+	}
+
+	if text[0] == '"' || text[0] == '\'' {
+		end := closingQuote(text)
+		if end < 0 || end+1 >= len(text) || text[end+1] != ':' {
+			return "", "", false
+		}
+		rest := text[end+2:]
+		if rest != "" && rest[0] != ' ' {
+			return "", "", false
+		}
+		key, err := unquote(text[:end+1])
+		if err != nil {
+			return "", "", false
+		}
+		return key, strings.TrimSpace(rest), true
+	}
+
+	for i := 0; i < len(text); i++ {
+		if text[i] == ':' && (i+1 == len(text) || text[i+1] == ' ') {
+			key := strings.TrimSpace(text[:i])
+			if key == "" {
+				return "", "", false
+			}
+			return key, strings.TrimSpace(text[i+1:]), true
+		}
+	}
+	return "", "", false
+}
+
+func closingQuote(s string) int {
+	q := s[0]
+	for i := 1; i < len(s); i++ {
+		switch {
+		case q == '"' && s[i] == '\\':
+			i++
+		case q == '\'' && s[i] == '\'' && i+1 < len(s) && s[i+1] == '\'':
+			i++
+		case s[i] == q:
+			return i
+		}
+	}
+	return -1
+}
+
+// parseInline - Значение в одной строке: скаляр или flow-коллекция.
+func (p *parser) parseInline(s string) (any, error) {
+	f := &flow{s: s, budget: p.budget}
+	v, err := f.value()
+	if err != nil {
+		return nil, err
+	}
+	f.space()
+	if f.pos < len(f.s) {
+		return nil, fmt.Errorf("лишние символы после значения: %q", f.s[f.pos:])
+	}
+	return v, nil
+}
+
+// flow - Разбор flow-коллекций и скаляров внутри одной строки.
+type flow struct {
+	s   string
+	pos int
+	// depth - Вложенность открытых flow-коллекций: внутри них запятые и скобки служебные.
+	depth  int
+	budget *budget
+}
+
+func (f *flow) space() {
+	for f.pos < len(f.s) && f.s[f.pos] == ' ' {
+		f.pos++
+	}
+}
+
+func (f *flow) value() (any, error) {
+	f.space()
+	if f.pos >= len(f.s) {
+		return nil, nil
+	}
+	if c := f.s[f.pos]; c != '[' && c != '{' {
+		// Коллекции учитываются в sequence и mapping вместе с вложенностью.
+		if err := f.budget.node(); err != nil {
+			return nil, err
+		}
+	}
+	switch f.s[f.pos] {
+	case '[':
+		return f.sequence()
+	case '{':
+		return f.mapping()
+	case '"', '\'':
+		end := closingQuote(f.s[f.pos:])
+		if end < 0 {
+			return nil, fmt.Errorf("не закрыта кавычка")
+		}
+		raw := f.s[f.pos : f.pos+end+1]
+		f.pos += end + 1
+		return unquote(raw)
+	}
+
+	// Простой скаляр. Внутри flow-коллекций он заканчивается на , ] }.
+	start := f.pos
+	for f.pos < len(f.s) {
+		c := f.s[f.pos]
+		if c == ',' || c == ']' || c == '}' {
+			break
+		}
+		if c == ':' && (f.pos+1 == len(f.s) || f.s[f.pos+1] == ' ') && f.depth > 0 {
+			break
+		}
+		f.pos++
+	}
+	return resolve(strings.TrimSpace(f.s[start:f.pos])), nil
+}
+
+// next - После элемента коллекции: запятая или закрывающая скобка close, которую разберёт цикл.
+// Иное - ошибка, иначе элемент, не сдвинувший позицию, зациклил бы разбор.
+func (f *flow) next(open, close byte) error {
+	f.space()
+	switch {
+	case f.pos >= len(f.s):
+		return fmt.Errorf("не закрыта скобка %c", open)
+	case f.s[f.pos] == ',':
+		f.pos++
+		return nil
+	case f.s[f.pos] == close:
+		return nil
+	default:
+		return fmt.Errorf("ожидается , или %c, получено %q", close, f.s[f.pos])
+	}
+}
+
+func (f *flow) sequence() (any, error) {
+	if err := f.budget.enter(); err != nil {
+		return nil, err
+	}
+	defer f.budget.leave()
+	f.pos++
+	f.depth++
+	defer func() { f.depth-- }()
+	items := []any{}
+	for {
+		f.space()
+		if f.pos >= len(f.s) {
+			return nil, fmt.Errorf("не закрыта скобка [")
+		}
+		if f.s[f.pos] == ']' {
+			f.pos++
+			return items, nil
+		}
+		v, err := f.value()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, v)
+		if err := f.next('[', ']'); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (f *flow) mapping() (any, error) {
+	if err := f.budget.enter(); err != nil {
+		return nil, err
+	}
+	defer f.budget.leave()
+	f.pos++
+	f.depth++
+	defer func() { f.depth-- }()
+	m := make(map[string]any)
+	for {
+		f.space()
+		if f.pos >= len(f.s) {
+			return nil, fmt.Errorf("не закрыта скобка {")
+		}
+		if f.s[f.pos] == '}' {
+			f.pos++
+			return m, nil
+		}
+		k, err := f.value()
+		if err != nil {
+			return nil, err
+		}
+		f.space()
+		if f.pos >= len(f.s) || f.s[f.pos] != ':' {
+			return nil, fmt.Errorf("ожидается : после ключа")
+		}
+		f.pos++
+		v, err := f.value()
+		if err != nil {
+			return nil, err
+		}
+		m[fmt.Sprint(k)] = v
+		if err := f.next('{', '}'); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func unquote(s string) (string, error) {
+	if s[0] == '\'' {
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'"), nil
+	}
+	v, err := strconv.Unquote(s)
+	if err != nil {
+		return "", fmt.Errorf("некорректная строка %s", s)
+	}
+	return v, nil
+}
+
+// resolve - Определяет тип простого скаляра по правилам core schema YAML 1.2.
+func resolve(s string) any {
+	switch s {
+	case "", "~", "null", "Null", "NULL":
+		return nil
+	case "true", "True", "TRUE":
+		return true
+	case "false", "False", "FALSE":
+		return false
+	}
+	base := 10
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0o") {
+		base = 0
+	}
+	if i, err := strconv.ParseInt(s, base, 64); err == nil {
+		return i
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil && strings.ContainsAny(s, "0123456789") {
+		return f
+	}
+	return s
+}
@@ -0,0 +1,180 @@
+package yaml_test
+
+import (
+	"errors"
+	"go-offline-test/internal/shared/encoding/yaml"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestUnmarshal(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want any
+	}{
+		{"пустой документ", "", nil},
+		{"только комментарий", "# пусто\n", nil},
+		{"маркер документа", "---\na: 1\n", map[string]any{"a": int64(1)}},
+		{"скаляры", "i: 42\nf: 1.5\nb: true\nn: ~\ns: текст\nhex: 0x1F\n", map[string]any{
+			"i": int64(42), "f": 1.5, "b": true, "n": nil, "s": "текст", "hex": int64(31),
+		}},
+		{"строки в кавычках", `a: "x: \"y\"\n"` + "\nb: 'it''s'\n", map[string]any{"a": "x: \"y\"\n", "b": "it's"}},
+		{"комментарии", "a: b # комментарий\nc: d#e\nq: \"# не комментарий\"\n", map[string]any{
+			"a": "b", "c": "d#e", "q": "# не комментарий",
+		}},
+		{"вложенное отображение", "server:\n  port: 8080\n  tls:\n    enabled: false\n", map[string]any{
+			"server": map[string]any{"port": int64(8080), "tls": map[string]any{"enabled": false}},
+		}},
+		{"последовательность на уровне ключа", "tags:\n- a\n- b\n", map[string]any{"tags": []any{"a", "b"}}},
+		{"последовательность отображений", "rules:\n  - name: a\n    max: 5\n  - name: b\n", map[string]any{
+			"rules": []any{map[string]any{"name": "a", "max": int64(5)}, map[string]any{"name": "b"}},
+		}},
+		{"вложенные последовательности", "- - 1\n  - 2\n- 3\n", []any{[]any{int64(1), int64(2)}, int64(3)}},
+		{"flow-коллекции", "a: [1, 'два', {k: v}]\nb: {x: [], y: {}}\n", map[string]any{
+			"a": []any{int64(1), "два", map[string]any{"k": "v"}},
+			"b": map[string]any{"x": []any{}, "y": map[string]any{}},
+		}},
+		{"запятая в конце flow", "a: [1, 2,]\n", map[string]any{"a": []any{int64(1), int64(2)}}},
+		{"двоеточие в скаляре вне flow", "url: http://example.com:8080/a\n", map[string]any{"url": "http://example.com:8080/a"}},
+		{"литеральный блок", "a: |\n  строка 1\n  строка 2\nb: 1\n", map[string]any{"a": "строка 1\nстрока 2\n", "b": int64(1)}},
+		{"свёрнутый блок без перевода строки", "a: >-\n  один\n  два\n\n  три\n", map[string]any{"a": "один два\nтри"}},
+		{"свёрнутый блок с двумя пустыми строками", "a: >\n  один\n\n\n  два\n", map[string]any{"a": "один\n\nдва\n"}},
+		{"CRLF", "a: 1\r\nb: 2\r\n", map[string]any{"a": int64(1), "b": int64(2)}},
+		{"кириллица в ключах", "автор: Толстой\n", map[string]any{"автор": "Толстой"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := yaml.Unmarshal([]byte(tt.in))
+			if err != nil {
+				t.Fatalf("Unmarshal(%q): %v", tt.in, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Unmarshal(%q) = %#v, ожидалось %#v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		line int
+		msg  string
+	}{
+		{"скобка } в последовательности", "[}", 1, "ожидается , или ]"},
+		{"скобка } в значении", "a: [}", 1, "ожидается , или ]"},
+		{"скобка } во вложенной последовательности", "a: {b: [}", 1, "ожидается , или ]"},
+		{"скобка ] в отображении", "a: {b: c]", 1, "ожидается , или }"},
+		{"элементы без запятой", "a: [[1] [2]]", 1, "ожидается , или ]"},
+		{"не закрыта [", "a: [1, 2", 1, "не закрыта скобка ["},
+		{"не закрыта {", "a: {b: 1", 1, "не закрыта скобка {"},
+		{"нет двоеточия в flow", "a: {b}", 1, "ожидается :"},
+		{"не закрыта кавычка", "a: \"x", 1, "не закрыта кавычка"},
+		{"лишнее после значения", "a: [1] x", 1, "лишние символы"},
+		{"повтор ключа", "a: 1\na: 2\n", 2, "повторяется"},
+		{"табуляция в отступе", "a:\n\tb: 1\n", 2, "табуляция"},
+		{"неожиданный отступ", "a: 1\n  b: 2\n", 2, "отступ"},
+		{"не ключ", "a: 1\nb\n", 2, "ожидается ключ"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := yaml.Unmarshal([]byte(tt.in))
+			var syntax *yaml.SyntaxError
+			if !errors.As(err, &syntax) {
+				t.Fatalf("Unmarshal(%q): ожидалась SyntaxError, получено %v", tt.in, err)
+			}
+			if syntax.Line != tt.line || !strings.Contains(syntax.Msg, tt.msg) {
+				t.Errorf("Unmarshal(%q) = %v, ожидалась строка %d и %q", tt.in, err, tt.line, tt.msg)
+			}
+		})
+	}
+}
+
+// TestUnmarshalLongScalar - Скаляр с множеством ": " разбирается за один проход, а не за квадратичное время.
+func TestUnmarshalLongScalar(t *testing.T) {
+	long := strings.TrimSpace(strings.Repeat("a: ", 200_000))
+	got, err := yaml.Unmarshal([]byte("k: " + long))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := got.(map[string]any)["k"]; s != long {
+		t.Errorf("ожидался скаляр длиной %d, получено %v", len(long), s)
+	}
+}
+
+func TestUnmarshalLimits(t *testing.T) {
+	limits := yaml.Limits{MaxBytes: 1 << 10, MaxDepth: 3, MaxNodes: 10}
+	tests := []struct {
+		name string
+		in   string
+		msg  string
+	}{
+		{"размер", "a: " + strings.Repeat("x", 1<<10), "больше 1024 байт"},
+		{"вложенность flow", "a: [[[1]]]", "вложенность больше 3"},
+		{"вложенность блоков", "a:\n  b:\n    c:\n      d: 1\n", "вложенность больше 3"},
+		{"вложенность последовательностей", strings.Repeat("- ", 300) + "x", "вложенность больше 3"},
+		{"смешанная вложенность", "a:\n  - {b: [1]}\n", "вложенность больше 3"},
+		{"число значений flow", "a: [1, 2, 3, 4, 5, 6, 7, 8, 9, 10]", "больше 10 значений"},
+		{"число значений блоков", strings.Repeat("- 1\n", 11), "больше 10 значений"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := yaml.UnmarshalLimits([]byte(tt.in), limits)
+			var syntax *yaml.SyntaxError
+			if !errors.As(err, &syntax) || !strings.Contains(syntax.Msg, tt.msg) {
+				t.Errorf("UnmarshalLimits(): ожидалась ошибка %q, получено %v", tt.msg, err)
+			}
+		})
+	}
+
+	// Вложенность ограничена и по умолчанию: глубокий документ отклоняется сразу, а не разбирается за квадратичное время.
+	if _, err := yaml.Unmarshal([]byte(strings.Repeat("- ", 500_000) + "x")); err == nil {
+		t.Error("Unmarshal() глубокого документа: ожидалась ошибка")
+	}
+
+	// На границе ограничений документ разбирается.
+	if _, err := yaml.UnmarshalLimits([]byte("a:\n  b: [1, 2, 3, 4, 5, 6]\n"), limits); err != nil {
+		t.Errorf("UnmarshalLimits() на границе ограничений: %v", err)
+	}
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	in := map[string]any{
+		"quote":  "Stay hungry: stay foolish",
+		"author": map[string]any{"name": "Стив Джобс", "aliases": []any{"Jobs", "# не комментарий"}},
+		"tags":   []any{},
+		"empty":  "",
+		"lines":  "первая\nвторая\n",
+		"number": int64(7),
+		"ratio":  0.25,
+		"flag":   true,
+		"null":   nil,
+		"quoted": "true",
+	}
+	data, err := yaml.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := yaml.Unmarshal(data)
+	if err != nil {
+		t.Fatalf("Unmarshal(Marshal()): %v\n%s", err, data)
+	}
+	if !reflect.DeepEqual(out, in) {
+		t.Errorf("после Marshal и Unmarshal получено %#v, ожидалось %#v\n%s", out, in, data)
+	}
+}
+
+// FuzzUnmarshal - Разбор любого ввода завершается: значением или ошибкой, без паники и зацикливания.
+// Начальный корпус - в testdata/fuzz/FuzzUnmarshal.
+func FuzzUnmarshal(f *testing.F) {
+	f.Add([]byte("a: [1, {b: c}]\n"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		v, err := yaml.Unmarshal(data)
+		if err != nil && v != nil {
+			t.Errorf("Unmarshal(%q) вернул и значение, и ошибку %v", data, err)
+		}
+	})
+}
@@ -0,0 +1,288 @@
+package yaml
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MapItem, MapSlice - Отображение с заданным порядком ключей.
+type MapItem struct {
+	Key   string
+	Value any
+}
+
+type MapSlice []MapItem
+
+// Marshal - Выводит значение в блочном стиле. Структуры выводятся по тегам json
+// (имя, omitempty, "-"), так что одни и те же DTO годятся и для JSON, и для YAML.
+func Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	e := &encoder{buf: &buf}
+	if err := e.node(reflect.ValueOf(v), 0, lineStart); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+type encoder struct {
+	buf *bytes.Buffer
+}
+
+var (
+	mapSliceType      = reflect.TypeOf(MapSlice(nil))
+	timeType          = reflect.TypeOf(time.Time{})
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	jsonNumberType    = reflect.TypeOf(json.Number(""))
+)
+
+// position - Где стоит курсор, когда начинается вывод узла.
+type position int
+
+const (
+	// lineStart - В начале строки, отступ ещё не выведен.
+	lineStart position = iota
+	// afterKey - После "key:", скаляр пишется в эту же строку, коллекция - со следующей.
+	afterKey
+	// afterDash - После "- ", первая строка коллекции пишется сразу за дефисом.
+	afterDash
+)
+
+// field - Пара ключ-значение для вывода коллекций.
+type field struct {
+	key   string
+	value reflect.Value
+}
+
+func (e *encoder) node(v reflect.Value, indent int, pos position) error {
+	v = deref(v)
+	if !v.IsValid() {
+		e.scalar("null", pos)
+		return nil
+	}
+
+	if s, ok, err := scalarText(v); ok || err != nil {
+		if err != nil {
+			return err
+		}
+		e.scalar(s, pos)
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		if v.Type() == mapSliceType {
+			return e.mapping(mapSliceFields(v.Interface().(MapSlice)), indent, pos)
+		}
+		return e.sequence(v, indent, pos)
+	case reflect.Map:
+		fields, err := mapFields(v)
+		if err != nil {
+			return err
+		}
+		return e.mapping(fields, indent, pos)
+	case reflect.Struct:
+		return e.mapping(structFields(v), indent, pos)
+	default:
+		return fmt.Errorf("yaml: тип %s не поддерживается", v.Type())
+	}
+}
+
+func (e *encoder) scalar(s string, pos position) {
+	if pos == afterKey {
+		e.buf.WriteByte(' ')
+	}
+	e.buf.WriteString(s)
+	e.buf.WriteByte('\n')
+}
+
+// lineIndent - Отступ i-й строки коллекции. После "- " первая строка уже начата.
+func (e *encoder) lineIndent(i, indent int, pos position) {
+	if i == 0 && pos == afterKey {
+		e.buf.WriteByte('\n')
+	}
+	if i > 0 || pos != afterDash {
+		e.buf.WriteString(strings.Repeat(" ", indent))
+	}
+}
+
+func (e *encoder) mapping(fields []field, indent int, pos position) error {
+	if len(fields) == 0 {
+		e.scalar("{}", pos)
+		return nil
+	}
+	for i, f := range fields {
+		e.lineIndent(i, indent, pos)
+		e.buf.WriteString(quoteIfNeeded(f.key))
+		e.buf.WriteByte(':')
+		if err := e.node(f.value, indent+2, afterKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *encoder) sequence(v reflect.Value, indent int, pos position) error {
+	if v.Len() == 0 {
+		e.scalar("[]", pos)
+		return nil
+	}
+	for i := 0; i < v.Len(); i++ {
+		e.lineIndent(i, indent, pos)
+		e.buf.WriteString("- ")
+		if err := e.node(v.Index(i), indent+2, afterDash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func deref(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// scalarText - Текст скаляра. ok=false, если значение - коллекция.
+func scalarText(v reflect.Value) (string, bool, error) {
+	if v.Type() == timeType {
+		return v.Interface().(time.Time).Format(time.RFC3339Nano), true, nil
+	}
+	if v.Type() == jsonNumberType {
+		return v.String(), true, nil
+	}
+	if v.Type().Implements(textMarshalerType) {
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return "", false, err
+		}
+		return quoteIfNeeded(string(text)), true, nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return quoteIfNeeded(v.String()), true, nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), true, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Type() == reflect.TypeOf(time.Duration(0)) {
+			return time.Duration(v.Int()).String(), true, nil
+		}
+		return strconv.FormatInt(v.Int(), 10), true, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), true, nil
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		switch {
+		case math.IsInf(f, 1):
+			return ".inf", true, nil
+		case math.IsInf(f, -1):
+			return "-.inf", true, nil
+		case math.IsNaN(f):
+			return ".nan", true, nil
+		}
+		return strconv.FormatFloat(f, 'g', -1, 64), true, nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			// []byte, как и в encoding/json, выводим строкой.
+			return quoteIfNeeded(string(v.Bytes())), true, nil
+		}
+	}
+	return "", false, nil
+}
+
+func mapSliceFields(ms MapSlice) []field {
+	fields := make([]field, 0, len(ms))
+	for _, item := range ms {
+		fields = append(fields, field{key: item.Key, value: reflect.ValueOf(item.Value)})
+	}
+	return fields
+}
+
+func mapFields(v reflect.Value) ([]field, error) {
+	fields := make([]field, 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		k := iter.Key()
+		var key string
+		switch {
+		case k.Kind() == reflect.String:
+			key = k.String()
+		case k.Type().Implements(textMarshalerType):
+			text, err := k.Interface().(encoding.TextMarshaler).MarshalText()
+			if err != nil {
+				return nil, err
+			}
+			key = string(text)
+		default:
+			key = fmt.Sprint(k.Interface())
+		}
+		fields = append(fields, field{key: key, value: iter.Value()})
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].key < fields[j].key })
+	return fields, nil
+}
+
+func structFields(v reflect.Value) []field {
+	t := v.Type()
+	fields := make([]field, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+		fv := v.Field(i)
+		if sf.Anonymous && name == "" && deref(fv).Kind() == reflect.Struct {
+			if inner := deref(fv); inner.IsValid() {
+				fields = append(fields, structFields(inner)...)
+			}
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		if strings.Contains(opts, "omitempty") && fv.IsZero() {
+			continue
+		}
+		if strings.Contains(opts, "omitempty") && (fv.Kind() == reflect.Slice || fv.Kind() == reflect.Map) && fv.Len() == 0 {
+			continue
+		}
+		fields = append(fields, field{key: name, value: fv})
+	}
+	return fields
+}
+
+// quoteIfNeeded - Берёт строку в кавычки, если без них она прочиталась бы иначе.
+func quoteIfNeeded(s string) string {
+	if s == "" || s != strings.TrimSpace(s) || strings.ContainsAny(s, "\n\r\t\"\\") {
+		return strconv.Quote(s)
+	}
+	if _, isString := resolve(s).(string); !isString {
+		return strconv.Quote(s)
+	}
+	if strings.ContainsRune("-?:,[]{}#&*!|>'%@`", rune(s[0])) ||
+		strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasSuffix(s, ":") {
+		return strconv.Quote(s)
+	}
+	for _, r := range s {
+		if r < 0x20 || r == 0x7f {
+			return strconv.Quote(s)
+		}
+	}
+	return s
+}
@@ -0,0 +1,24 @@
+package shared
+
+import "context"
+
+const requestMetaCtxKey contextKey = "requestMeta"
+
+// RequestMeta - Сведения о входящем запросе, нужные слоям ниже транспорта.
+type RequestMeta struct {
+	ID       string
+	ClientIP string
+	// Route - Шаблон маршрута вида "POST /quotes". Пусто, пока запрос не сопоставлен с маршрутом.
+	Route string
+}
+
+// WithRequestMeta - Сохраняет сведения о запросе в контекст.
+func WithRequestMeta(ctx context.Context, meta RequestMeta) context.Context {
+	return context.WithValue(ctx, requestMetaCtxKey, meta)
+}
+
+// RequestMetaFromContext - Достаёт сведения о запросе из контекста.
+func RequestMetaFromContext(ctx context.Context) RequestMeta {
+	meta, _ := ctx.Value(requestMetaCtxKey).(RequestMeta)
+	return meta
+}
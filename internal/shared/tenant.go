@@ -0,0 +1,34 @@
+package shared
+
+import (
+	"context"
+	"regexp"
+)
+
+type contextKey string
+
+const (
+	tenantCtxKey contextKey = "tenant"
+	// DefaultTenant - Тенант для запросов без заголовка X-Tenant и префикса /t/{tenant}.
+	DefaultTenant = "default"
+)
+
+var tenantNameRe = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// ValidTenantName - Имя тенанта: латинские буквы в нижнем регистре, цифры и дефисы, до 63 символов.
+func ValidTenantName(name string) bool {
+	return tenantNameRe.MatchString(name)
+}
+
+// WithTenant - Сохраняет имя тенанта в контекст.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantCtxKey, tenant)
+}
+
+// TenantFromContext - Возвращает тенанта запроса или тенанта по умолчанию.
+func TenantFromContext(ctx context.Context) string {
+	if tenant, ok := ctx.Value(tenantCtxKey).(string); ok && tenant != "" {
+		return tenant
+	}
+	return DefaultTenant
+}
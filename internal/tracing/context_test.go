@@ -0,0 +1,103 @@
+package tracing_test
+
+import (
+	"errors"
+	"go-offline-test/internal/tracing"
+	"strings"
+	"testing"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	const (
+		traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+		spanID  = "00f067aa0ba902b7"
+	)
+	tests := []struct {
+		name        string
+		traceparent string
+		sampled     bool
+		ok          bool
+	}{
+		{"версия 00 с выборкой", "00-" + traceID + "-" + spanID + "-01", true, true},
+		{"версия 00 без выборки", "00-" + traceID + "-" + spanID + "-00", false, true},
+		{"пробелы по краям", " 00-" + traceID + "-" + spanID + "-01 ", true, true},
+		{"будущая версия с лишним полем", "cc-" + traceID + "-" + spanID + "-01-what-the-future", true, true},
+		{"версия ff", "ff-" + traceID + "-" + spanID + "-01", false, false},
+		{"версия 00 с лишним полем", "00-" + traceID + "-" + spanID + "-01-extra", false, false},
+		{"верхний регистр", "00-" + strings.ToUpper(traceID) + "-" + spanID + "-01", false, false},
+		{"короткий trace id", "00-" + traceID[2:] + "-" + spanID + "-01", false, false},
+		{"длинный span id", "00-" + traceID + "-" + spanID + "ab-01", false, false},
+		{"не hex", "00-" + traceID + "-" + "zzf067aa0ba902b7" + "-01", false, false},
+		{"нулевой trace id", "00-" + strings.Repeat("0", 32) + "-" + spanID + "-01", false, false},
+		{"нулевой span id", "00-" + traceID + "-" + strings.Repeat("0", 16) + "-01", false, false},
+		{"флаги в два символа", "00-" + traceID + "-" + spanID + "-1", false, false},
+		{"мало полей", "00-" + traceID + "-" + spanID, false, false},
+		{"пустой заголовок", "", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc, err := tracing.ParseTraceparent(tt.traceparent, "")
+			if !tt.ok {
+				if !errors.Is(err, tracing.ErrInvalidTraceparent) {
+					t.Errorf("ParseTraceparent(%q) = %+v, %v, ожидалась %v", tt.traceparent, sc, err, tracing.ErrInvalidTraceparent)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseTraceparent(%q): %v", tt.traceparent, err)
+			}
+			if sc.TraceID.String() != traceID || sc.SpanID.String() != spanID {
+				t.Errorf("идентификаторы %s-%s, ожидались %s-%s", sc.TraceID, sc.SpanID, traceID, spanID)
+			}
+			if sc.Sampled() != tt.sampled {
+				t.Errorf("Sampled() = %v, ожидалось %v", sc.Sampled(), tt.sampled)
+			}
+			if !sc.Remote {
+				t.Error("контекст из заголовка должен быть Remote")
+			}
+		})
+	}
+}
+
+func TestParseTraceparentRoundTrip(t *testing.T) {
+	const header = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	sc, err := tracing.ParseTraceparent(header, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sc.Traceparent(); got != header {
+		t.Errorf("Traceparent() = %q, ожидалось %q", got, header)
+	}
+
+	// Будущая версия пересылается дальше как 00 без лишних полей.
+	sc, err = tracing.ParseTraceparent("cc"+header[2:]+"-extra", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sc.Traceparent(); got != header {
+		t.Errorf("Traceparent() будущей версии = %q, ожидалось %q", got, header)
+	}
+}
+
+func TestParseTracestate(t *testing.T) {
+	const header = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	long := "vendor=" + strings.Repeat("x", 506)
+	tests := []struct {
+		name, tracestate, want string
+	}{
+		{"сохраняется", " congo=t61rcWkgMzE,rojo=00f067aa0ba902b7 ", "congo=t61rcWkgMzE,rojo=00f067aa0ba902b7"},
+		{"ровно на пределе", long[:512], long[:512]},
+		{"длиннее предела отбрасывается", long, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc, err := tracing.ParseTraceparent(header, tt.tracestate)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sc.State != tt.want {
+				t.Errorf("State = %q, ожидалось %q", sc.State, tt.want)
+			}
+		})
+	}
+}
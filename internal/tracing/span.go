@@ -0,0 +1,114 @@
+package tracing
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type SpanKind int
+
+const (
+	KindInternal SpanKind = 1
+	KindServer   SpanKind = 2
+)
+
+type StatusCode int
+
+const (
+	StatusUnset StatusCode = 0
+	StatusOK    StatusCode = 1
+	StatusError StatusCode = 2
+)
+
+// Attr - Атрибут span. Value может быть string, bool, int, int64 или float64.
+type Attr struct {
+	Key   string
+	Value any
+}
+
+// SpanData - Завершённый span в том виде, в котором он уходит в экспортёр.
+type SpanData struct {
+	Name          string
+	Kind          SpanKind
+	Context       SpanContext
+	Parent        SpanID
+	Start         time.Time
+	End           time.Time
+	Attrs         []Attr
+	Status        StatusCode
+	StatusMessage string
+}
+
+// Span - Открытый участок трассы. Все методы безопасны для nil, поэтому код можно
+// инструментировать без проверок, настроена ли трассировка.
+type Span struct {
+	tracer *Tracer
+	data   SpanData
+	ended  bool
+	mu     sync.Mutex
+}
+
+func (s *Span) Context() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return s.data.Context
+}
+
+func (s *Span) SetAttr(key string, value any) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data.Attrs = append(s.data.Attrs, Attr{Key: key, Value: value})
+}
+
+// SetError - Помечает span как завершившийся ошибкой.
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data.Status = StatusError
+	s.data.StatusMessage = err.Error()
+}
+
+// End - Закрывает span и передаёт его экспортёру, если трасса сэмплирована. Повторный вызов ничего не делает.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.End = time.Now()
+	data := s.data
+	s.mu.Unlock()
+
+	if data.Context.Sampled() {
+		s.tracer.export(data)
+	}
+}
+
+type contextKey string
+
+const spanCtxKey contextKey = "span"
+
+// ContextWithSpan - Кладёт span в контекст, дочерние span будут ссылаться на него.
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+	return context.WithValue(ctx, spanCtxKey, span)
+}
+
+// SpanFromContext - Текущий span запроса или nil.
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanCtxKey).(*Span)
+	return span
+}
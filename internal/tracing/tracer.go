@@ -0,0 +1,212 @@
+package tracing
+
+import (
+	"context"
+	"encoding/binary"
+	"log/slog"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	batchSize     = 512
+	queueSize     = 4096
+	flushInterval = 5 * time.Second
+)
+
+// Exporter - Получатель завершённых span.
+type Exporter interface {
+	Export(ctx context.Context, spans []SpanData) error
+	Shutdown(ctx context.Context) error
+}
+
+// Tracer - Создаёт span и пачками отправляет сэмплированные в экспортёр в фоне.
+type Tracer struct {
+	exporter Exporter
+	// ratio - Доля новых трасс, которые сэмплируются (биты float64). Для входящих трасс решение берётся из флага родителя.
+	ratio   atomic.Uint64
+	queue   chan SpanData
+	done    chan struct{}
+	dropped atomic.Int64
+	closed  bool
+	mu      sync.RWMutex
+}
+
+// NewTracer - Создаёт трассировщик. Без экспортёра span всё равно создаются,
+// чтобы идентификаторы попадали в логи и заголовки ответа, но никуда не отправляются.
+func NewTracer(exporter Exporter, ratio float64) *Tracer {
+	t := &Tracer{
+		exporter: exporter,
+		queue:    make(chan SpanData, queueSize),
+		done:     make(chan struct{}),
+	}
+	t.SetSampleRatio(ratio)
+	if exporter != nil {
+		go t.run()
+	} else {
+		close(t.done)
+	}
+	return t
+}
+
+// SetSampleRatio - Меняет долю сэмплируемых трасс на лету.
+func (t *Tracer) SetSampleRatio(ratio float64) {
+	t.ratio.Store(math.Float64bits(ratio))
+}
+
+var defaultTracer atomic.Pointer[Tracer]
+
+// SetDefault - Делает трассировщик глобальным для Start.
+func SetDefault(t *Tracer) {
+	defaultTracer.Store(t)
+}
+
+// Start - Открывает дочерний span текущего span из контекста, либо корневой, если его нет.
+// Пока трассировщик не настроен, возвращает исходный контекст и nil.
+func Start(ctx context.Context, name string) (context.Context, *Span) {
+	t := defaultTracer.Load()
+	if t == nil {
+		return ctx, nil
+	}
+
+	var parent SpanContext
+	if span := SpanFromContext(ctx); span != nil {
+		parent = span.Context()
+	}
+	return t.start(ctx, name, KindInternal, parent)
+}
+
+// StartServer - Открывает span входящего запроса. remote - контекст из traceparent, может быть пустым.
+func StartServer(ctx context.Context, name string, remote SpanContext) (context.Context, *Span) {
+	t := defaultTracer.Load()
+	if t == nil {
+		return ctx, nil
+	}
+	return t.start(ctx, name, KindServer, remote)
+}
+
+func (t *Tracer) start(ctx context.Context, name string, kind SpanKind, parent SpanContext) (context.Context, *Span) {
+	sc := SpanContext{SpanID: newSpanID()}
+	if parent.IsValid() {
+		sc.TraceID = parent.TraceID
+		sc.Flags = parent.Flags
+		sc.State = parent.State
+	} else {
+		sc.TraceID = newTraceID()
+		if t.sample(sc.TraceID) {
+			sc.Flags = flagSampled
+		}
+	}
+
+	span := &Span{
+		tracer: t,
+		data: SpanData{
+			Name:    name,
+			Kind:    kind,
+			Context: sc,
+			Parent:  parent.SpanID,
+			Start:   time.Now(),
+		},
+	}
+	return ContextWithSpan(ctx, span), span
+}
+
+// sample - Детерминированное решение по младшим байтам trace id, как у TraceIDRatioBased в OpenTelemetry.
+func (t *Tracer) sample(id TraceID) bool {
+	ratio := math.Float64frombits(t.ratio.Load())
+	if ratio >= 1 {
+		return true
+	}
+	if ratio <= 0 {
+		return false
+	}
+	bound := uint64(ratio * (1 << 63))
+	return binary.BigEndian.Uint64(id[8:])>>1 < bound
+}
+
+func (t *Tracer) export(data SpanData) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.exporter == nil || t.closed {
+		return
+	}
+	select {
+	case t.queue <- data:
+	default:
+		// Экспортёр не успевает - теряем span, но не тормозим запросы.
+		t.dropped.Add(1)
+	}
+}
+
+func (t *Tracer) run() {
+	defer close(t.done)
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]SpanData, 0, batchSize)
+	send := func() {
+		if len(batch) == 0 {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := t.exporter.Export(ctx, batch); err != nil {
+			slog.Warn("не удалось экспортировать span", "count", len(batch), "error", err)
+		}
+		cancel()
+		batch = make([]SpanData, 0, batchSize)
+		if dropped := t.dropped.Swap(0); dropped > 0 {
+			slog.Warn("span отброшены из-за переполнения очереди", "count", dropped)
+		}
+	}
+
+	for {
+		select {
+		case data, ok := <-t.queue:
+			if !ok {
+				send()
+				return
+			}
+			batch = append(batch, data)
+			if len(batch) >= batchSize {
+				send()
+			}
+		case <-ticker.C:
+			send()
+		}
+	}
+}
+
+// QueueLen - Сколько span ждут отправки в экспортёр.
+func (t *Tracer) QueueLen() int {
+	return len(t.queue)
+}
+
+// Exporting - Настроен ли экспортёр.
+func (t *Tracer) Exporting() bool {
+	return t.exporter != nil
+}
+
+// Shutdown - Отправляет накопленные span и закрывает экспортёр.
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	if t.exporter == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	if !t.closed {
+		t.closed = true
+		close(t.queue)
+	}
+	t.mu.Unlock()
+
+	select {
+	case <-t.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return t.exporter.Shutdown(ctx)
+}
@@ -0,0 +1,61 @@
+package transport
+
+import (
+	"go-offline-test/internal/services"
+	"go-offline-test/internal/shared/dto"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// QueryAudit - GET /audit?actor=&operation=&tenant=&request_id=&quote_id=&author_id=&since=&until=&limit=
+func (c *Controller) QueryAudit() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		filter, err := parseAuditFilter(r)
+		if err != nil {
+			c.error(w, r, err, "")
+			return
+		}
+
+		entries, err := c.audit.QueryAudit(r.Context(), filter)
+		if err != nil {
+			c.error(w, r, err, "")
+			return
+		}
+		c.respond(w, r, entries, http.StatusOK)
+	}
+}
+
+func parseAuditFilter(r *http.Request) (dto.AuditFilter, error) {
+	q := r.URL.Query()
+	filter := dto.AuditFilter{
+		Actor:     q.Get("actor"),
+		Operation: q.Get("operation"),
+		Tenant:    q.Get("tenant"),
+		RequestID: q.Get("request_id"),
+	}
+
+	ints := map[string]*int{"quote_id": &filter.QuoteID, "author_id": &filter.AuthorID, "limit": &filter.Limit}
+	for name, dst := range ints {
+		if raw := q.Get(name); raw != "" {
+			v, err := strconv.Atoi(raw)
+			if err != nil {
+				return filter, services.NewErrInvalidField(name, "audit.invalid_int", name, raw)
+			}
+			*dst = v
+		}
+	}
+
+	times := map[string]*time.Time{"since": &filter.Since, "until": &filter.Until}
+	for name, dst := range times {
+		if raw := q.Get(name); raw != "" {
+			v, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				return filter, services.NewErrInvalidField(name, "audit.invalid_time", name, raw)
+			}
+			*dst = v
+		}
+	}
+
+	return filter, nil
+}
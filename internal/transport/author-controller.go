@@ -0,0 +1,100 @@
+package transport
+
+import (
+	"go-offline-test/internal/services"
+	"go-offline-test/internal/shared/dto"
+	"net/http"
+	"strings"
+)
+
+// expandAuthor - Значение ?expand=, при котором к цитатам добавляются сведения об авторе.
+const expandAuthor = "author"
+
+func (c *Controller) Author() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		author, err := c.authors.Author(r.Context(), r.PathValue("author"))
+		if err != nil {
+			c.error(w, r, err, "")
+			return
+		}
+		c.respond(w, r, author, http.StatusOK)
+	}
+}
+
+func (c *Controller) CreateAuthor() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req dto.CreateAuthor
+		if code, err := c.decode(r, &req); err != nil {
+			c.error(w, r, err, code)
+			return
+		}
+
+		author, err := c.authors.CreateAuthor(r.Context(), &req)
+		if err != nil {
+			c.error(w, r, err, "")
+			return
+		}
+		c.respond(w, r, author, http.StatusCreated)
+	}
+}
+
+func (c *Controller) UpdateAuthor() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req dto.UpdateAuthor
+		if code, err := c.decode(r, &req); err != nil {
+			c.error(w, r, err, code)
+			return
+		}
+
+		author, err := c.authors.UpdateAuthor(r.Context(), r.PathValue("author"), &req)
+		if err != nil {
+			c.error(w, r, err, "")
+			return
+		}
+		c.respond(w, r, author, http.StatusOK)
+	}
+}
+
+func (c *Controller) MergeAuthors() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req dto.MergeAuthors
+		if code, err := c.decode(r, &req); err != nil {
+			c.error(w, r, err, code)
+			return
+		}
+
+		merge, err := c.authors.MergeAuthors(r.Context(), r.PathValue("author"), &req)
+		if err != nil {
+			c.error(w, r, err, "")
+			return
+		}
+		c.respond(w, r, merge, http.StatusOK)
+	}
+}
+
+// expandAuthors - Разбирает ?expand= (значения через запятую) и при expand=author добавляет
+// к цитатам сведения об авторах. При ошибке ответ уже отправлен.
+func (c *Controller) expandAuthors(w http.ResponseWriter, r *http.Request, quotes ...*dto.Quote) bool {
+	expand := false
+	for _, value := range r.URL.Query()["expand"] {
+		for _, item := range strings.Split(value, ",") {
+			switch item = strings.TrimSpace(item); item {
+			case "":
+			case expandAuthor:
+				expand = true
+			default:
+				c.error(w, r, services.NewErrInvalidField("expand", "request.expand_invalid", item, expandAuthor), "")
+				return false
+			}
+		}
+	}
+	if !expand {
+		return true
+	}
+
+	if err := c.authors.ExpandAuthors(r.Context(), quotes); err != nil {
+		c.error(w, r, err, "")
+		return false
+	}
+	return true
+}
@@ -0,0 +1,45 @@
+package transport
+
+import (
+	"go-offline-test/internal/services"
+	"net/http"
+	"strconv"
+)
+
+func (c *Controller) CiteQuote() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := c.quoteID(w, r)
+		if !ok {
+			return
+		}
+
+		citation, err := c.citations.Cite(r.Context(), id, r.URL.Query().Get("style"))
+		if err != nil {
+			c.error(w, r, err, "")
+			return
+		}
+		c.respond(w, r, citation, http.StatusOK)
+	}
+}
+
+// AuthorCitations - Ссылки на все цитаты автора. С style=bibtex и Accept: text/plain - готовый .bib.
+func (c *Controller) AuthorCitations() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		citations, err := c.citations.AuthorCitations(r.Context(), r.PathValue("author"), r.URL.Query().Get("style"))
+		if err != nil {
+			c.error(w, r, err, "")
+			return
+		}
+		c.respond(w, r, citations, http.StatusOK)
+	}
+}
+
+// quoteID - id цитаты из пути. При ошибке ответ уже отправлен.
+func (c *Controller) quoteID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id <= 0 {
+		c.error(w, r, services.NewErrInvalidField("id", "request.quote_id_invalid"), "")
+		return 0, false
+	}
+	return id, true
+}
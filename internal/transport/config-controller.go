@@ -0,0 +1,16 @@
+package transport
+
+import (
+	"net/http"
+)
+
+func (c *Controller) ReloadConfig() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		report, err := c.config.Reload(r.Context())
+		if err != nil {
+			c.error(w, r, err, "")
+			return
+		}
+		c.respond(w, r, report, http.StatusOK)
+	}
+}
@@ -1,67 +1,201 @@
 package transport
 
 import (
-	"encoding/json"
+	"bytes"
 	"errors"
 	"fmt"
+	"go-offline-test/internal/auth"
+	"go-offline-test/internal/codec"
+	"go-offline-test/internal/health"
+	"go-offline-test/internal/i18n"
+	"go-offline-test/internal/metrics"
 	"go-offline-test/internal/services"
+	"go-offline-test/internal/shared"
 	"go-offline-test/internal/shared/dto"
-	"log"
+	"log/slog"
+	"mime"
 	"net/http"
+	"strings"
 )
 
 type Controller struct {
 	services.IQuoteService
+	moderation services.IModerationService
+	authors    services.IAuthorService
+	citations  services.ICitationService
+	tenants    services.ITenantService
+	audit      services.IAuditService
+	config     services.IConfigService
+	// auth - Проверка bearer-токенов. nil означает, что авторизация отключена.
+	auth auth.IAuthenticator
+	// certAuth - Аутентификация по клиентскому сертификату. nil, если mTLS выключен.
+	certAuth *auth.ClientCertAuthenticator
+	// keys - Хранилище API-ключей для админских эндпоинтов. nil, если API-ключи отключены.
+	keys *auth.KeyStore
+	// registry - Метрики, которые отдаются на /metrics.
+	registry *metrics.Registry
+	metrics  *httpMetrics
+	health   *health.Health
+	// codecs - Форматы тел запросов и ответов.
+	codecs *codec.Registry
 }
 
-func NewController(service services.IQuoteService) *Controller {
-	return &Controller{service}
+func NewController(
+	service services.IQuoteService,
+	moderation services.IModerationService,
+	authors services.IAuthorService,
+	citations services.ICitationService,
+	tenants services.ITenantService,
+	auditService services.IAuditService,
+	configService services.IConfigService,
+	authenticator auth.IAuthenticator,
+	certAuth *auth.ClientCertAuthenticator,
+	keys *auth.KeyStore,
+	registry *metrics.Registry,
+	healthState *health.Health,
+) *Controller {
+	return &Controller{
+		IQuoteService: service,
+		moderation:    moderation,
+		authors:       authors,
+		citations:     citations,
+		tenants:       tenants,
+		audit:         auditService,
+		config:        configService,
+		auth:          authenticator,
+		certAuth:      certAuth,
+		keys:          keys,
+		registry:      registry,
+		metrics:       newHTTPMetrics(registry),
+		health:        healthState,
+		codecs:        codec.Default(),
+	}
 }
 
 func (c *Controller) respond(w http.ResponseWriter, r *http.Request, data interface{}, status int) {
-	w.Header().Set("Content-Type", "application/json")
+	if data == nil {
+		w.WriteHeader(status)
+		return
+	}
+
+	contentType, body, err := c.encode(r, data)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "не удалось закодировать ответ", "error", err)
+		c.error(w, r, err, dto.CodeInternal)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
 	w.WriteHeader(status)
+	w.Write(body)
+}
 
-	if data != nil {
-		if err := json.NewEncoder(w).Encode(data); err != nil {
-			log.Printf("Failed to encode response: %v", err)
+// encode - Кодирует ответ в первый из принятых клиентом форматов, который может его представить.
+// Если ни один не подходит (например, CSV для вложенных списков), ответ отдаётся в JSON.
+func (c *Controller) encode(r *http.Request, data any) (string, []byte, error) {
+	var buf bytes.Buffer
+	for _, cd := range c.codecs.Negotiate(r.Header.Get("Accept")) {
+		buf.Reset()
+		err := cd.Encode(&buf, data)
+		if errors.Is(err, codec.ErrUnsupported) {
+			continue
 		}
+		return cd.ContentType(), buf.Bytes(), err
 	}
-	log.Printf("INFO [%d] ответ успешно отправлен\n%s", status, data)
+
+	cd := c.codecs.Default()
+	buf.Reset()
+	err := cd.Encode(&buf, data)
+	return cd.ContentType(), buf.Bytes(), err
 }
 
-func (c *Controller) error(w http.ResponseWriter, r *http.Request, err error, status int) {
-	if status == 0 {
-		switch {
-		case errors.Is(err, services.ErrNoQuotesAvailable) ||
-			errors.Is(err, services.ErrAuthorNotFound) ||
-			errors.Is(err, services.ErrQuoteNotFound) ||
-			errors.Is(err, services.ErrNoQuotesByThisAuthor):
-			status = 404
-		default:
-			status = 500
+// decode - Читает тело запроса в формате из Content-Type. Без Content-Type тело считается JSON.
+func (c *Controller) decode(r *http.Request, v any) (dto.ErrorCode, error) {
+	contentType := r.Header.Get("Content-Type")
+	if mediaType, _, _ := mime.ParseMediaType(contentType); mediaType == formContentType {
+		// curl -d без -H ставит этот тип. Раньше любое тело читалось как JSON, такие клиенты продолжают работать.
+		contentType = ""
+	}
+	cd, ok := c.codecs.ForContentType(contentType)
+	if !ok {
+		return dto.CodeUnsupportedMediaType, i18n.Errorf("request.media_type_unsupported",
+			r.Header.Get("Content-Type"), strings.Join(c.codecs.MediaTypes(), ", "))
+	}
+	if err := cd.Decode(r.Body, v); err != nil {
+		if errors.Is(err, codec.ErrUnsupported) {
+			return dto.CodeUnsupportedMediaType, i18n.Errorf("request.media_type_rejected", cd.MediaTypes()[0])
 		}
+		return dto.CodeMalformedBody, i18n.Errorf("request.body_malformed", err)
+	}
+	return "", nil
+}
+
+const formContentType = "application/x-www-form-urlencoded"
+
+// error - Отвечает ошибкой в формате RFC 7807. code пустой - код определяется по ошибке.
+func (c *Controller) error(w http.ResponseWriter, r *http.Request, err error, code dto.ErrorCode) {
+	code, field := errorCode(err, code)
+	status := codeStatus(code)
+
+	// Тело оказалось больше лимита - сообщаем лимит, а не текст ошибки чтения.
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		err = i18n.Errorf("request.body_too_large", tooLarge.Limit)
 	}
-	log.Printf("ERROR [%d]: %v", status, err.Error())
+	c.metrics.errors.Inc(errorName(err))
 
-	w.Header().Set("Content-Type", "application/json")
+	if status >= 500 {
+		slog.ErrorContext(r.Context(), "запрос завершился ошибкой", "error", err, "code", code)
+	} else {
+		slog.WarnContext(r.Context(), "запрос отклонён", "error", err, "code", code)
+	}
+
+	// Логи пишутся на языке по умолчанию, клиенту - на языке из Accept-Language.
+	langs := i18n.Negotiate(r.Header.Get("Accept-Language"))
+	detail := i18n.Localize(err, langs)
+	problem := dto.Problem{
+		Type:      "about:blank",
+		Title:     i18n.T(langs, "code."+string(code)),
+		Status:    status,
+		Detail:    detail,
+		Instance:  r.URL.Path,
+		Code:      code,
+		RequestID: shared.RequestMetaFromContext(r.Context()).ID,
+	}
+	var violations services.ErrValidation
+	if errors.As(err, &violations) {
+		for _, v := range violations {
+			problem.Errors = append(problem.Errors, dto.FieldError{Field: v.Field, Detail: v.Localize(langs)})
+		}
+	} else if field != "" {
+		problem.Errors = []dto.FieldError{{Field: field, Detail: detail}}
+	}
+	contentType, data, encErr := c.encode(r, problem)
+	if encErr != nil {
+		slog.ErrorContext(r.Context(), "не удалось закодировать ошибку", "error", encErr)
+		return
+	}
+	if mediaType, params, err := mime.ParseMediaType(contentType); err == nil {
+		if problemType, ok := problemContentTypes[mediaType]; ok {
+			contentType = mime.FormatMediaType(problemType, params)
+		}
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Language", string(langs[0]))
+	w.Header().Add("Vary", "Accept-Language")
 	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"error": err.Error(),
-		"code":  status,
-	})
+	w.Write(data)
 }
 
 func (c *Controller) AddQuote() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		quote, ok := r.Context().Value(quoteCtxKey).(*dto.Quote)
 		if !ok {
-			c.error(w, r, fmt.Errorf("quote data missing"), http.StatusBadRequest)
+			c.error(w, r, fmt.Errorf("quote data missing"), dto.CodeInternal)
 			return
 		}
 
 		if err := c.IQuoteService.AddQuote(r.Context(), quote); err != nil {
-			c.error(w, r, err, 0)
+			c.error(w, r, err, "")
 			return
 		}
 
@@ -74,12 +208,12 @@ func (c *Controller) DeleteQuote() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		id, ok := r.Context().Value(quoteIDCtxKey).(int)
 		if !ok {
-			c.error(w, r, fmt.Errorf("quote data missing"), http.StatusBadRequest)
+			c.error(w, r, fmt.Errorf("quote data missing"), dto.CodeInternal)
 			return
 		}
 
 		if err := c.IQuoteService.DeleteQuote(r.Context(), id); err != nil {
-			c.error(w, r, err, 0)
+			c.error(w, r, err, "")
 			return
 		}
 
@@ -91,7 +225,10 @@ func (c *Controller) RandomQuote() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		quote, err := c.IQuoteService.RandomQuote(r.Context())
 		if err != nil {
-			c.error(w, r, err, 0)
+			c.error(w, r, err, "")
+			return
+		}
+		if !c.expandAuthors(w, r, quote) {
 			return
 		}
 		c.respond(w, r, quote, http.StatusOK)
@@ -104,11 +241,11 @@ func (c *Controller) GetQuotesHandler() http.HandlerFunc {
 		var err error
 
 		authorHeader := r.Header.Get("author")
-		log.Printf("INFO: данные запроса успешно получены:{\nAuthor: %s\n}", authorHeader)
+		slog.DebugContext(r.Context(), "данные запроса получены", "author", authorHeader)
 		if authorHeader != "" {
 			quotes, err = c.IQuoteService.QuotesByAuthor(r.Context(), authorHeader)
-			if err := c.IQuoteService.ValidateData("", authorHeader, authorMode); err != nil {
-				c.error(w, r, err, 400)
+			if err := c.IQuoteService.ValidateData(r.Context(), "", authorHeader, authorMode); err != nil {
+				c.error(w, r, err, dto.CodeValidationFailed)
 				return
 			}
 		} else {
@@ -116,7 +253,10 @@ func (c *Controller) GetQuotesHandler() http.HandlerFunc {
 		}
 
 		if err != nil {
-			c.error(w, r, err, 0)
+			c.error(w, r, err, "")
+			return
+		}
+		if !c.expandAuthors(w, r, quotes...) {
 			return
 		}
 
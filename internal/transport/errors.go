@@ -0,0 +1,110 @@
+package transport
+
+import (
+	"errors"
+	"go-offline-test/internal/auth"
+	"go-offline-test/internal/services"
+	"go-offline-test/internal/shared/dto"
+	"net/http"
+)
+
+// codeStatuses - HTTP-статус для каждого кода ошибки. Единственное место, где коды связаны со статусами.
+var codeStatuses = map[dto.ErrorCode]int{
+	dto.CodeValidationFailed:     http.StatusBadRequest,
+	dto.CodeMalformedBody:        http.StatusBadRequest,
+	dto.CodeUnauthorized:         http.StatusUnauthorized,
+	dto.CodeForbidden:            http.StatusForbidden,
+	dto.CodeQuotaExceeded:        http.StatusForbidden,
+	dto.CodeQuoteNotFound:        http.StatusNotFound,
+	dto.CodeNoQuotes:             http.StatusNotFound,
+	dto.CodeAuthorNotFound:       http.StatusNotFound,
+	dto.CodeAuthorHasNoQuotes:    http.StatusNotFound,
+	dto.CodeTenantNotFound:       http.StatusNotFound,
+	dto.CodeKeyNotFound:          http.StatusNotFound,
+	dto.CodeNotAcceptable:        http.StatusNotAcceptable,
+	dto.CodeQuoteAlreadyExists:   http.StatusConflict,
+	dto.CodeTenantAlreadyExists:  http.StatusConflict,
+	dto.CodeDefaultTenant:        http.StatusConflict,
+	dto.CodeKeyRevoked:           http.StatusConflict,
+	dto.CodeQuoteNotPending:      http.StatusConflict,
+	dto.CodeAuthorAmbiguous:      http.StatusConflict,
+	dto.CodeAliasTaken:           http.StatusConflict,
+	dto.CodePayloadTooLarge:      http.StatusRequestEntityTooLarge,
+	dto.CodeUnsupportedMediaType: http.StatusUnsupportedMediaType,
+	dto.CodeContentRejected:      http.StatusUnprocessableEntity,
+	dto.CodeConfigRejected:       http.StatusUnprocessableEntity,
+	dto.CodeInternal:             http.StatusInternalServerError,
+}
+
+// errorCodes - Коды известных ошибок. field - поле запроса, к которому относится ошибка.
+var errorCodes = []struct {
+	err   error
+	code  dto.ErrorCode
+	field string
+}{
+	{services.ErrNoQuotesAvailable, dto.CodeNoQuotes, ""},
+	{services.ErrAuthorNotFound, dto.CodeAuthorNotFound, ""},
+	{services.ErrQuoteNotFound, dto.CodeQuoteNotFound, ""},
+	{services.ErrNoQuotesByThisAuthor, dto.CodeAuthorHasNoQuotes, ""},
+	{services.ErrQuoteAlreadyExist, dto.CodeQuoteAlreadyExists, ""},
+	{services.ErrQuoteNotPending, dto.CodeQuoteNotPending, ""},
+	{services.ErrAuthorAmbiguous, dto.CodeAuthorAmbiguous, ""},
+	{services.ErrAliasTaken, dto.CodeAliasTaken, "aliases"},
+	{services.ErrMergeTargetNotFound, dto.CodeAuthorNotFound, "into"},
+	{services.ErrTenantNotFound, dto.CodeTenantNotFound, ""},
+	{services.ErrTenantAlreadyExist, dto.CodeTenantAlreadyExists, ""},
+	{services.ErrDefaultTenantDelete, dto.CodeDefaultTenant, ""},
+	{services.ErrQuotaExceeded, dto.CodeQuotaExceeded, ""},
+	{services.ErrInvalidConfig, dto.CodeConfigRejected, ""},
+	{auth.ErrForbidden, dto.CodeForbidden, ""},
+	{auth.ErrKeyNotFound, dto.CodeKeyNotFound, ""},
+	{auth.ErrKeyRevoked, dto.CodeKeyRevoked, ""},
+	{auth.ErrEmptyKeyName, dto.CodeValidationFailed, "name"},
+	{auth.ErrNoScopes, dto.CodeValidationFailed, "scopes"},
+	{auth.ErrUnknownScope, dto.CodeValidationFailed, "scopes"},
+	{auth.ErrInvalidExpiry, dto.CodeValidationFailed, "expires_at"},
+}
+
+// errorCode - Код и поле для ошибки. Код, переданный обработчиком, важнее таблицы: один и тот же
+// отозванный ключ - это 401 при входе и 409 при повторном отзыве. Слишком большое тело и ошибки
+// валидации распознаются всегда. Неизвестные ошибки без кода - INTERNAL_ERROR.
+func errorCode(err error, code dto.ErrorCode) (dto.ErrorCode, string) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return dto.CodePayloadTooLarge, ""
+	}
+	var invalid *services.ErrInvalidName
+	if errors.As(err, &invalid) {
+		return dto.CodeValidationFailed, invalid.Field
+	}
+	var rejected *services.ErrContentRejected
+	if errors.As(err, &rejected) {
+		return dto.CodeContentRejected, rejected.Field
+	}
+	for _, e := range errorCodes {
+		if errors.Is(err, e.err) {
+			if code == "" {
+				code = e.code
+			}
+			return code, e.field
+		}
+	}
+	if code == "" {
+		code = dto.CodeInternal
+	}
+	return code, ""
+}
+
+// codeStatus - Статус для кода. Код без записи в таблице - ошибка в коде сервиса, отвечаем 500.
+func codeStatus(code dto.ErrorCode) int {
+	if status, ok := codeStatuses[code]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// problemContentTypes - Типы RFC 7807 для форматов, у которых они есть.
+var problemContentTypes = map[string]string{
+	"application/json": "application/problem+json",
+	"application/xml":  "application/problem+xml",
+}
@@ -0,0 +1,27 @@
+package transport
+
+import (
+	"go-offline-test/internal/health"
+	"go-offline-test/internal/shared/dto"
+	"net/http"
+)
+
+// Healthz - Процесс жив и обрабатывает запросы. Зависимости не проверяются.
+func (c *Controller) Healthz() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		c.respond(w, r, &dto.Health{Status: health.StatusOK}, http.StatusOK)
+	}
+}
+
+// Readyz - Готов ли сервис принимать трафик. 503, если идёт выключение, восстановление данных или упала проверка.
+func (c *Controller) Readyz() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		resp := c.health.Ready(r.Context())
+
+		status := http.StatusOK
+		if resp.Status != health.StatusReady {
+			status = http.StatusServiceUnavailable
+		}
+		c.respond(w, r, resp, status)
+	}
+}
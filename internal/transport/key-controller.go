@@ -0,0 +1,103 @@
+package transport
+
+import (
+	"encoding/json"
+	"go-offline-test/internal/auth"
+	"go-offline-test/internal/i18n"
+	"go-offline-test/internal/shared/dto"
+	"log/slog"
+	"net/http"
+)
+
+func (c *Controller) ListKeys() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		keys, err := c.keys.List(r.Context())
+		if err != nil {
+			c.error(w, r, err, "")
+			return
+		}
+
+		resp := make([]*dto.APIKey, 0, len(keys))
+		for _, key := range keys {
+			resp = append(resp, keyToDTO(key, ""))
+		}
+		c.respond(w, r, resp, http.StatusOK)
+	}
+}
+
+func (c *Controller) CreateKey() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req dto.CreateAPIKey
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			c.error(w, r, i18n.Errorf("request.body_malformed", err), dto.CodeMalformedBody)
+			return
+		}
+
+		scopes, err := auth.ParseScopes(req.Scopes)
+		if err != nil {
+			c.error(w, r, err, dto.CodeValidationFailed)
+			return
+		}
+
+		token, key, err := c.keys.Create(r.Context(), req.Name, scopes, req.ExpiresAt)
+		if err != nil {
+			c.error(w, r, err, "")
+			return
+		}
+		slog.InfoContext(r.Context(), "API-ключ создан", "key_id", key.ID, "key_name", key.Name)
+
+		c.respond(w, r, keyToDTO(key, token), http.StatusCreated)
+	}
+}
+
+func (c *Controller) RevokeKey() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		if err := c.keys.Revoke(r.Context(), id); err != nil {
+			c.error(w, r, err, "")
+			return
+		}
+		slog.InfoContext(r.Context(), "API-ключ отозван", "key_id", id)
+
+		c.respond(w, r, nil, http.StatusNoContent)
+	}
+}
+
+func (c *Controller) RotateKey() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req dto.RotateAPIKey
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				c.error(w, r, i18n.Errorf("request.body_malformed", err), dto.CodeMalformedBody)
+				return
+			}
+		}
+
+		id := r.PathValue("id")
+		token, key, err := c.keys.Rotate(r.Context(), id, req.ExpiresAt)
+		if err != nil {
+			c.error(w, r, err, "")
+			return
+		}
+		slog.InfoContext(r.Context(), "API-ключ перевыпущен", "key_id", id)
+
+		c.respond(w, r, keyToDTO(key, token), http.StatusOK)
+	}
+}
+
+func keyToDTO(key *auth.APIKey, token string) *dto.APIKey {
+	scopes := make([]string, 0, len(key.Scopes))
+	for _, s := range key.Scopes {
+		scopes = append(scopes, string(s))
+	}
+	return &dto.APIKey{
+		ID:        key.ID,
+		Name:      key.Name,
+		Scopes:    scopes,
+		CreatedAt: key.CreatedAt,
+		ExpiresAt: key.ExpiresAt,
+		RevokedAt: key.RevokedAt,
+		RotatedAt: key.RotatedAt,
+		Token:     token,
+	}
+}
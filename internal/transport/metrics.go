@@ -0,0 +1,99 @@
+package transport
+
+import (
+	"errors"
+	"go-offline-test/internal/auth"
+	"go-offline-test/internal/metrics"
+	"go-offline-test/internal/services"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// httpMetrics - Метрики HTTP-слоя: запросы и задержки по маршрутам, ошибки по типам.
+type httpMetrics struct {
+	requests *metrics.CounterVec
+	duration *metrics.HistogramVec
+	errors   *metrics.CounterVec
+}
+
+func newHTTPMetrics(registry *metrics.Registry) *httpMetrics {
+	m := &httpMetrics{
+		requests: metrics.NewCounterVec("http_requests_total", "Число обработанных HTTP-запросов.", "method", "route", "code"),
+		duration: metrics.NewHistogramVec("http_request_duration_seconds", "Время обработки HTTP-запроса.", metrics.DefaultBuckets, "method", "route"),
+		errors:   metrics.NewCounterVec("quotes_errors_total", "Число ответов с ошибкой по типу ошибки.", "error"),
+	}
+	registry.Register(m.requests)
+	registry.Register(m.duration)
+	registry.Register(m.errors)
+	return m
+}
+
+func (m *httpMetrics) observeRequest(method, route string, status int, elapsed time.Duration) {
+	m.requests.Inc(method, route, strconv.Itoa(status))
+	m.duration.Observe(elapsed.Seconds(), method, route)
+}
+
+// errorNames - Имена известных ошибок для метки error. Порядок важен: сверху более конкретные.
+var errorNames = []struct {
+	err  error
+	name string
+}{
+	{services.ErrNoQuotesAvailable, "ErrNoQuotesAvailable"},
+	{services.ErrAuthorNotFound, "ErrAuthorNotFound"},
+	{services.ErrQuoteNotFound, "ErrQuoteNotFound"},
+	{services.ErrNoQuotesByThisAuthor, "ErrNoQuotesByThisAuthor"},
+	{services.ErrQuoteAlreadyExist, "ErrQuoteAlreadyExist"},
+	{services.ErrQuoteNotPending, "ErrQuoteNotPending"},
+	{services.ErrAuthorAmbiguous, "ErrAuthorAmbiguous"},
+	{services.ErrAliasTaken, "ErrAliasTaken"},
+	{services.ErrMergeTargetNotFound, "ErrMergeTargetNotFound"},
+	{services.ErrAddQuote, "ErrAddQuote"},
+	{services.ErrGetQuotes, "ErrGetQuotes"},
+	{services.ErrGetQuote, "ErrGetQuote"},
+	{services.ErrGetQuoteByAuthor, "ErrGetQuoteByAuthor"},
+	{services.ErrTenantNotFound, "ErrTenantNotFound"},
+	{services.ErrTenantAlreadyExist, "ErrTenantAlreadyExist"},
+	{services.ErrDefaultTenantDelete, "ErrDefaultTenantDelete"},
+	{services.ErrQuotaExceeded, "ErrQuotaExceeded"},
+	{services.ErrInvalidConfig, "ErrInvalidConfig"},
+	{auth.ErrMissingToken, "ErrMissingToken"},
+	{auth.ErrForbidden, "ErrForbidden"},
+	{auth.ErrKeyNotFound, "ErrKeyNotFound"},
+	{auth.ErrKeyRevoked, "ErrKeyRevoked"},
+	{auth.ErrKeyExpired, "ErrKeyExpired"},
+	{auth.ErrTokenExpired, "ErrTokenExpired"},
+	{auth.ErrTokenNotYetValid, "ErrTokenNotYetValid"},
+	{auth.ErrInvalidIssuer, "ErrInvalidIssuer"},
+	{auth.ErrInvalidAudience, "ErrInvalidAudience"},
+	{auth.ErrInvalidToken, "ErrInvalidToken"},
+}
+
+// errorName - Имя ошибки для метрик. Ошибки без известного типа попадают в other.
+func errorName(err error) string {
+	for _, e := range errorNames {
+		if errors.Is(err, e.err) {
+			return e.name
+		}
+	}
+	var invalid *services.ErrInvalidName
+	if errors.As(err, &invalid) {
+		return "ErrInvalidName"
+	}
+	var rejected *services.ErrContentRejected
+	if errors.As(err, &rejected) {
+		return "ErrContentRejected"
+	}
+	return "other"
+}
+
+// Metrics - Отдаёт метрики в текстовом формате Prometheus.
+func (c *Controller) Metrics() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", metrics.ContentType)
+		if _, err := c.registry.WriteTo(w); err != nil {
+			slog.WarnContext(r.Context(), "не удалось отдать метрики", "error", err)
+		}
+	}
+}
@@ -2,13 +2,24 @@ package transport
 
 import (
 	"context"
-	"encoding/json"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"go-offline-test/internal/auth"
+	"go-offline-test/internal/i18n"
+	"go-offline-test/internal/logging"
+	"go-offline-test/internal/services"
+	"go-offline-test/internal/shared"
 	"go-offline-test/internal/shared/dto"
-	"log"
+	"go-offline-test/internal/tracing"
+	"log/slog"
+	"net"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type contextKey string
@@ -18,25 +29,41 @@ const (
 	quoteIDCtxKey contextKey = "quoteID"
 	quoteMode     string     = "quote"
 	authorMode    string     = "author"
+	// textMode - Автор цитаты указан по author_id, проверяется только текст.
+	textMode string = "text"
+
+	tenantHeader     = "X-Tenant"
+	tenantPathPrefix = "/t/"
+
+	requestIDHeader = "X-Request-ID"
+	maxRequestIDLen = 128
 )
 
 func (c *Controller) MiddlewareValidate(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == http.MethodPost || r.Method == http.MethodPut {
 			if r.Body == nil {
-				c.error(w, r, fmt.Errorf("request body is required"), http.StatusBadRequest)
+				c.error(w, r, i18n.Errorf("request.body_required"), dto.CodeMalformedBody)
 				return
 			}
 
 			var quote dto.Quote
-			if err := json.NewDecoder(r.Body).Decode(&quote); err != nil {
-				c.error(w, r, fmt.Errorf("invalid request body: %v", err), http.StatusBadRequest)
+			if code, err := c.decode(r, &quote); err != nil {
+				c.error(w, r, err, code)
 				return
 			}
-			log.Printf("INFO: данные запроса успешно получены:{\nID: %d\nQuote: %s\nAuthor: %s\n}", quote.ID, quote.Text, quote.AuthorName)
+			slog.DebugContext(r.Context(), "данные запроса получены", logging.Quote("quote", quote.Text), "author", quote.AuthorName)
 
-			if err := c.IQuoteService.ValidateData(quote.Text, quote.AuthorName, quoteMode); err != nil {
-				c.error(w, r, err, 400)
+			if quote.AuthorID < 0 {
+				c.error(w, r, services.NewErrInvalidField("author_id", "request.author_id_invalid"), "")
+				return
+			}
+			mode := quoteMode
+			if quote.AuthorID > 0 && quote.AuthorName == "" {
+				mode = textMode
+			}
+			if err := c.IQuoteService.ValidateData(r.Context(), quote.Text, quote.AuthorName, mode); err != nil {
+				c.error(w, r, err, dto.CodeValidationFailed)
 				return
 			}
 
@@ -48,17 +75,17 @@ func (c *Controller) MiddlewareValidate(next http.HandlerFunc) http.HandlerFunc
 		if r.Method == http.MethodDelete || strings.HasPrefix(r.URL.Path, "/quotes/") {
 			idStr := strings.TrimPrefix(r.URL.Path, "/quotes/")
 			if idStr == "" {
-				c.error(w, r, fmt.Errorf("quote ID is required"), http.StatusBadRequest)
+				c.error(w, r, services.NewErrInvalidField("id", "request.quote_id_required"), "")
 				return
 			}
 
 			id, err := strconv.Atoi(idStr)
 			if err != nil || id <= 0 {
-				c.error(w, r, fmt.Errorf("invalid quote ID"), http.StatusBadRequest)
+				c.error(w, r, services.NewErrInvalidField("id", "request.quote_id_invalid"), "")
 				return
 			}
 
-			log.Printf("INFO: данные запроса успешно получены:{\nID: %d\n}", id)
+			slog.DebugContext(r.Context(), "данные запроса получены", "quote_id", id)
 
 			// Сохраняем ID в контекст
 			ctx := context.WithValue(r.Context(), quoteIDCtxKey, id)
@@ -68,3 +95,311 @@ func (c *Controller) MiddlewareValidate(next http.HandlerFunc) http.HandlerFunc
 	}
 
 }
+
+// MiddlewareAccept - Отвечает 406 до вызова обработчика, если ни один формат из Accept не поддерживается.
+// Так запрос с неподходящим Accept не успевает ничего изменить.
+func (c *Controller) MiddlewareAccept(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if accept := r.Header.Get("Accept"); len(c.codecs.Negotiate(accept)) == 0 {
+			c.error(w, r, i18n.Errorf("request.not_acceptable", accept, strings.Join(c.codecs.MediaTypes(), ", ")), dto.CodeNotAcceptable)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// MiddlewareAuth - Проверяет bearer-токен из заголовка Authorization или клиентский сертификат
+// и наличие у клиента нужного права.
+func (c *Controller) MiddlewareAuth(scope auth.Scope, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if c.auth == nil && c.certAuth == nil {
+			next(w, r)
+			return
+		}
+
+		principal, err := c.authenticate(r)
+		if err != nil {
+			switch {
+			case errors.Is(err, auth.ErrMissingToken):
+				w.Header().Set("WWW-Authenticate", `Bearer realm="quotes"`)
+				c.error(w, r, err, dto.CodeUnauthorized)
+			case auth.IsUnauthenticated(err):
+				w.Header().Set("WWW-Authenticate", `Bearer realm="quotes", error="invalid_token"`)
+				c.error(w, r, err, dto.CodeUnauthorized)
+			default:
+				c.error(w, r, err, dto.CodeInternal)
+			}
+			return
+		}
+
+		if !principal.HasScope(scope) {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="quotes", error="insufficient_scope", scope="%s"`, scope))
+			c.error(w, r, i18n.Errorf("auth.scope_required", auth.ErrForbidden, scope), dto.CodeForbidden)
+			return
+		}
+
+		logging.AddAttrs(r.Context(), slog.String("actor", principal.Subject), slog.String("auth_method", principal.Method))
+		slog.DebugContext(r.Context(), "запрос авторизован", "actor_name", principal.Name)
+
+		ctx := auth.WithPrincipal(r.Context(), principal)
+		next(w, r.WithContext(ctx))
+	}
+}
+
+// authenticate - Bearer-токен важнее сертификата. Сертификат используется, только если он
+// проверен при рукопожатии по CA из настроек mTLS.
+func (c *Controller) authenticate(r *http.Request) (*auth.Principal, error) {
+	token, hasToken := bearerToken(r)
+	if hasToken && c.auth != nil {
+		return c.auth.Authenticate(r.Context(), token)
+	}
+	if c.certAuth != nil && r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+		return c.certAuth.AuthenticateCert(r.Context(), r.TLS.VerifiedChains[0][0])
+	}
+	if hasToken {
+		return nil, auth.ErrInvalidToken
+	}
+	return nil, auth.ErrMissingToken
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	header := r.Header.Get("Authorization")
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// MiddlewareTenant - Определяет тенанта по префиксу пути /t/{tenant}/... или заголовку X-Tenant.
+// Префикс срезается до маршрутизации, поэтому обработчики видят обычные пути вида /quotes.
+func (c *Controller) MiddlewareTenant(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenant := r.Header.Get(tenantHeader)
+
+		if rest, found := strings.CutPrefix(r.URL.Path, tenantPathPrefix); found {
+			name, path, _ := strings.Cut(rest, "/")
+			if tenant != "" && tenant != name {
+				c.error(w, r, services.NewErrInvalidField("tenant", "request.tenant_mismatch", name, tenantHeader, tenant), "")
+				return
+			}
+			tenant = name
+
+			r2 := new(http.Request)
+			*r2 = *r
+			r2.URL = new(url.URL)
+			*r2.URL = *r.URL
+			r2.URL.Path = "/" + path
+			r2.URL.RawPath = ""
+			r = r2
+		}
+
+		if tenant == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !shared.ValidTenantName(tenant) {
+			c.error(w, r, services.NewErrInvalidField("tenant", "request.tenant_invalid", tenant), "")
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(shared.WithTenant(r.Context(), tenant)))
+	})
+}
+
+// MiddlewareRequestMeta - Присваивает запросу id (или берёт его из X-Request-ID) и определяет IP клиента.
+// X-Forwarded-For учитывается, только если сервис стоит за доверенным прокси.
+func (c *Controller) MiddlewareRequestMeta(trustForwardedFor bool, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		w.Header().Set(requestIDHeader, id)
+
+		ip, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			ip = r.RemoteAddr
+		}
+		if trustForwardedFor {
+			if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+				first, _, _ := strings.Cut(forwarded, ",")
+				if first = strings.TrimSpace(first); net.ParseIP(first) != nil {
+					ip = first
+				}
+			}
+		}
+
+		ctx := shared.WithRequestMeta(r.Context(), shared.RequestMeta{ID: id, ClientIP: ip})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for _, r := range id {
+		if r < 0x21 || r > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(b)
+}
+
+// statusRecorder - Запоминает статус ответа для лога доступа.
+type statusRecorder struct {
+	http.ResponseWriter
+	ctx    context.Context
+	status int
+}
+
+func (sr *statusRecorder) WriteHeader(status int) {
+	if sr.status == 0 {
+		sr.status = status
+		logging.SetStatus(sr.ctx, status)
+	}
+	sr.ResponseWriter.WriteHeader(status)
+}
+
+func (sr *statusRecorder) Write(b []byte) (int, error) {
+	if sr.status == 0 {
+		sr.WriteHeader(http.StatusOK)
+	}
+	return sr.ResponseWriter.Write(b)
+}
+
+func (sr *statusRecorder) Unwrap() http.ResponseWriter {
+	return sr.ResponseWriter
+}
+
+// MiddlewareBodyLimit - Ограничивает размер тела запроса. Чтение сверх лимита возвращает *http.MaxBytesError,
+// который c.error превращает в 413.
+func (c *Controller) MiddlewareBodyLimit(limit int64, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength > limit {
+			c.error(w, r, &http.MaxBytesError{Limit: limit}, dto.CodePayloadTooLarge)
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, limit)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// MiddlewareTracing - Открывает span запроса. Продолжает трассу из traceparent/tracestate, если они пришли,
+// и возвращает traceparent своего span в ответе.
+func (c *Controller) MiddlewareTracing(router *http.ServeMux, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, route := router.Handler(r)
+		if route == "" {
+			route = "unmatched"
+		}
+
+		var remote tracing.SpanContext
+		if traceparent := r.Header.Get(tracing.HeaderTraceparent); traceparent != "" {
+			var err error
+			remote, err = tracing.ParseTraceparent(traceparent, r.Header.Get(tracing.HeaderTracestate))
+			if err != nil {
+				// По спецификации некорректный traceparent игнорируется и начинается новая трасса.
+				slog.DebugContext(r.Context(), "заголовок traceparent проигнорирован", "traceparent", traceparent)
+			}
+		}
+
+		ctx, span := tracing.StartServer(r.Context(), route, remote)
+		defer span.End()
+
+		sc := span.Context()
+		if sc.IsValid() {
+			w.Header().Set(tracing.HeaderTraceparent, sc.Traceparent())
+			if sc.State != "" {
+				w.Header().Set(tracing.HeaderTracestate, sc.State)
+			}
+		}
+		meta := shared.RequestMetaFromContext(ctx)
+		span.SetAttr("http.request.method", r.Method)
+		span.SetAttr("http.route", route)
+		span.SetAttr("url.path", r.URL.Path)
+		span.SetAttr("client.address", meta.ClientIP)
+		span.SetAttr("request.id", meta.ID)
+		span.SetAttr("tenant", shared.TenantFromContext(ctx))
+
+		rec := &spanRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r.WithContext(ctx))
+
+		if rec.status == 0 {
+			rec.status = http.StatusOK
+		}
+		span.SetAttr("http.response.status_code", rec.status)
+		if rec.status >= 500 {
+			span.SetError(fmt.Errorf("статус ответа %d", rec.status))
+		}
+	})
+}
+
+// spanRecorder - Запоминает статус ответа для span запроса.
+type spanRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (sr *spanRecorder) WriteHeader(status int) {
+	if sr.status == 0 {
+		sr.status = status
+	}
+	sr.ResponseWriter.WriteHeader(status)
+}
+
+func (sr *spanRecorder) Write(b []byte) (int, error) {
+	if sr.status == 0 {
+		sr.status = http.StatusOK
+	}
+	return sr.ResponseWriter.Write(b)
+}
+
+func (sr *spanRecorder) Unwrap() http.ResponseWriter {
+	return sr.ResponseWriter
+}
+
+// MiddlewareLogging - Открывает область логирования запроса: id, метод, маршрут, тенант и IP
+// попадают во все строки лога, написанные с контекстом запроса. По завершении пишет строку лога доступа
+// и учитывает запрос в метриках.
+func (c *Controller) MiddlewareLogging(router *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, route := router.Handler(r)
+		if route == "" {
+			route = "unmatched"
+		}
+		meta := shared.RequestMetaFromContext(r.Context())
+		meta.Route = route
+
+		ctx := logging.WithRequest(shared.WithRequestMeta(r.Context(), meta),
+			slog.String("request_id", meta.ID),
+			slog.String("method", r.Method),
+			slog.String("route", route),
+			slog.String("tenant", shared.TenantFromContext(r.Context())),
+			slog.String("client_ip", meta.ClientIP),
+		)
+		if sc := tracing.SpanFromContext(r.Context()).Context(); sc.IsValid() {
+			logging.AddAttrs(ctx, slog.String("trace_id", sc.TraceID.String()), slog.String("span_id", sc.SpanID.String()))
+		}
+		rec := &statusRecorder{ResponseWriter: w, ctx: ctx}
+
+		router.ServeHTTP(rec, r.WithContext(ctx))
+
+		if rec.status == 0 {
+			rec.status = http.StatusOK
+			logging.SetStatus(ctx, rec.status)
+		}
+		c.metrics.observeRequest(r.Method, route, rec.status, logging.Elapsed(ctx))
+		slog.InfoContext(ctx, "запрос обработан", "path", r.URL.Path)
+	})
+}
@@ -0,0 +1,55 @@
+package transport
+
+import (
+	"go-offline-test/internal/shared/dto"
+	"net/http"
+)
+
+func (c *Controller) ModerationQueue() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		queue, err := c.moderation.ModerationQueue(r.Context())
+		if err != nil {
+			c.error(w, r, err, "")
+			return
+		}
+		c.respond(w, r, queue, http.StatusOK)
+	}
+}
+
+func (c *Controller) ApproveQuote() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := c.quoteID(w, r)
+		if !ok {
+			return
+		}
+
+		quote, err := c.moderation.ApproveQuote(r.Context(), id)
+		if err != nil {
+			c.error(w, r, err, "")
+			return
+		}
+		c.respond(w, r, quote, http.StatusOK)
+	}
+}
+
+func (c *Controller) RejectQuote() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := c.quoteID(w, r)
+		if !ok {
+			return
+		}
+
+		var req dto.RejectQuote
+		if code, err := c.decode(r, &req); err != nil {
+			c.error(w, r, err, code)
+			return
+		}
+
+		quote, err := c.moderation.RejectQuote(r.Context(), id, req.Reason)
+		if err != nil {
+			c.error(w, r, err, "")
+			return
+		}
+		c.respond(w, r, quote, http.StatusOK)
+	}
+}
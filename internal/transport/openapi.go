@@ -0,0 +1,39 @@
+package transport
+
+import (
+	_ "embed"
+	"log/slog"
+	"net/http"
+)
+
+var (
+	// openAPISpec - Описание API. При изменении маршрутов или DTO обновляется вместе с ними,
+	// расхождения ловят контрактные тесты в tests/.
+	//go:embed openapi.json
+	openAPISpec []byte
+
+	//go:embed docs.html
+	docsPage []byte
+)
+
+// OpenAPI - GET /openapi.json.
+func (c *Controller) OpenAPI() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(openAPISpec); err != nil {
+			slog.WarnContext(r.Context(), "не удалось отдать описание API", "error", err)
+		}
+	}
+}
+
+// Docs - GET /docs: страница, которая строит документацию по /openapi.json и позволяет отправлять запросы.
+// Всё нужное встроено в бинарник, внешние скрипты не загружаются.
+func (c *Controller) Docs() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Content-Security-Policy", "default-src 'self'; script-src 'unsafe-inline'; style-src 'unsafe-inline'")
+		if _, err := w.Write(docsPage); err != nil {
+			slog.WarnContext(r.Context(), "не удалось отдать страницу документации", "error", err)
+		}
+	}
+}
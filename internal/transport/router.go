@@ -1,23 +1,276 @@
 package transport
 
 import (
-	"go-offline-test/internal/shared"
-	"log"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"go-offline-test/internal/auth"
+	"go-offline-test/internal/codec"
+	"go-offline-test/internal/health"
+	"go-offline-test/internal/listen"
+	"go-offline-test/internal/metrics"
+	"go-offline-test/internal/shared/dto/config"
+	"log/slog"
+	"net"
 	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
-func RunRouter(c *Controller) {
+// route - Маршрут и группа слушателей, на которых он доступен.
+type route struct {
+	pattern string
+	group   listen.RouteSet
+	handler http.HandlerFunc
+}
+
+// routes - Все маршруты сервиса. Описание API в openapi.json должно совпадать с этим списком.
+func (c *Controller) routes() []route {
+	// api - Ответы в формате из Accept. /metrics, /openapi.json и /docs сами выбирают Content-Type.
+	api := c.MiddlewareAccept
+	routes := []route{
+		{"POST /quotes", listen.RoutesPublic, api(c.MiddlewareAuth(auth.ScopeQuotesWrite, c.MiddlewareValidate(c.AddQuote())))},
+		{"DELETE /quotes/{id}", listen.RoutesPublic, api(c.MiddlewareAuth(auth.ScopeQuotesWrite, c.MiddlewareValidate(c.DeleteQuote())))},
+		{"GET /quotes", listen.RoutesPublic, api(c.MiddlewareAuth(auth.ScopeQuotesRead, c.GetQuotesHandler()))},
+		{"GET /quotes/random", listen.RoutesPublic, api(c.MiddlewareAuth(auth.ScopeQuotesRead, c.RandomQuote()))},
+		{"GET /quotes/{id}/cite", listen.RoutesPublic, api(c.MiddlewareAuth(auth.ScopeQuotesRead, c.CiteQuote()))},
+		{"POST /authors", listen.RoutesPublic, api(c.MiddlewareAuth(auth.ScopeQuotesWrite, c.CreateAuthor()))},
+		{"GET /authors/{author}", listen.RoutesPublic, api(c.MiddlewareAuth(auth.ScopeQuotesRead, c.Author()))},
+		{"PUT /authors/{author}", listen.RoutesPublic, api(c.MiddlewareAuth(auth.ScopeQuotesWrite, c.UpdateAuthor()))},
+		{"POST /authors/{author}/merge", listen.RoutesPublic, api(c.MiddlewareAuth(auth.ScopeQuotesWrite, c.MergeAuthors()))},
+		{"GET /authors/{author}/citations", listen.RoutesPublic, api(c.MiddlewareAuth(auth.ScopeQuotesRead, c.AuthorCitations()))},
+
+		{"GET /moderation/queue", listen.RoutesPublic, api(c.MiddlewareAuth(auth.ScopeQuotesModerate, c.ModerationQueue()))},
+		{"POST /moderation/quotes/{id}/approve", listen.RoutesPublic, api(c.MiddlewareAuth(auth.ScopeQuotesModerate, c.ApproveQuote()))},
+		{"POST /moderation/quotes/{id}/reject", listen.RoutesPublic, api(c.MiddlewareAuth(auth.ScopeQuotesModerate, c.RejectQuote()))},
+
+		{"GET /metrics", listen.RoutesOps, c.Metrics()},
+		{"GET /healthz", listen.RoutesOps, api(c.Healthz())},
+		{"GET /readyz", listen.RoutesOps, api(c.Readyz())},
+
+		{"GET /audit", listen.RoutesAdmin, api(c.MiddlewareAuth(auth.ScopeAdmin, c.QueryAudit()))},
+
+		{"GET /admin/tenants", listen.RoutesAdmin, api(c.MiddlewareAuth(auth.ScopeAdmin, c.ListTenants()))},
+		{"POST /admin/tenants", listen.RoutesAdmin, api(c.MiddlewareAuth(auth.ScopeAdmin, c.CreateTenant()))},
+		{"PUT /admin/tenants/{name}/quota", listen.RoutesAdmin, api(c.MiddlewareAuth(auth.ScopeAdmin, c.UpdateTenantQuota()))},
+		{"DELETE /admin/tenants/{name}", listen.RoutesAdmin, api(c.MiddlewareAuth(auth.ScopeAdmin, c.DeleteTenant()))},
+
+		{"POST /admin/reload", listen.RoutesAdmin, api(c.MiddlewareAuth(auth.ScopeAdmin, c.ReloadConfig()))},
+
+		// Описание API отдаётся на каждом слушателе.
+		{"GET /openapi.json", listen.RoutesAll, c.OpenAPI()},
+		{"GET /docs", listen.RoutesAll, c.Docs()},
+	}
+
+	if c.keys != nil {
+		routes = append(routes,
+			route{"GET /admin/keys", listen.RoutesAdmin, api(c.MiddlewareAuth(auth.ScopeAdmin, c.ListKeys()))},
+			route{"POST /admin/keys", listen.RoutesAdmin, api(c.MiddlewareAuth(auth.ScopeAdmin, c.CreateKey()))},
+			route{"DELETE /admin/keys/{id}", listen.RoutesAdmin, api(c.MiddlewareAuth(auth.ScopeAdmin, c.RevokeKey()))},
+			route{"POST /admin/keys/{id}/rotate", listen.RoutesAdmin, api(c.MiddlewareAuth(auth.ScopeAdmin, c.RotateKey()))},
+		)
+	}
+	return routes
+}
+
+// Routes - Шаблоны маршрутов вида "GET /quotes", которые обслуживает контроллер.
+func (c *Controller) Routes() []string {
+	routes := c.routes()
+	patterns := make([]string, 0, len(routes))
+	for _, r := range routes {
+		patterns = append(patterns, r.pattern)
+	}
+	return patterns
+}
+
+// newRouter - Маршруты из указанных групп. Слушатель видит только их, остальные пути отвечают 404.
+func (c *Controller) newRouter(routes listen.RouteSet) *http.ServeMux {
+	router := http.NewServeMux()
+	for _, r := range c.routes() {
+		if routes.Has(r.group) {
+			router.HandleFunc(r.pattern, r.handler)
+		}
+	}
+	return router
+}
+
+// Handler - Маршруты из указанных групп вместе с общими middleware: лимит тела, id запроса, тенант,
+// трассировка и лог доступа.
+func (c *Controller) Handler(conf *config.Config, routes listen.RouteSet) http.Handler {
+	router := c.newRouter(routes)
+	return c.MiddlewareBodyLimit(conf.Server.MaxBodyBytes,
+		c.MiddlewareRequestMeta(conf.Request.TrustForwardedFor,
+			c.MiddlewareTenant(c.MiddlewareTracing(router, c.MiddlewareLogging(router)))))
+}
+
+// listenerSpecs - Слушатели из server.listeners, а без них - один TCP-слушатель на server.listen со всеми маршрутами.
+func listenerSpecs(conf *config.ServerConfig) ([]*listen.Spec, error) {
+	if len(conf.Listeners) == 0 {
+		return []*listen.Spec{{Network: listen.NetworkTCP, Address: conf.Listen, Routes: listen.RoutesAll, TLS: true}}, nil
+	}
+	specs, errs := listen.ParseAll(conf.Listeners)
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return specs, nil
+}
+
+// handlerSwitch - Обработчик слушателя, который подменяется на работающем сервере.
+type handlerSwitch struct {
+	current atomic.Pointer[http.Handler]
+}
+
+func (h *handlerSwitch) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	(*h.current.Load()).ServeHTTP(w, r)
+}
+
+func (h *handlerSwitch) set(handler http.Handler) {
+	h.current.Store(&handler)
+}
+
+// startupHandler - Обработчик на время сборки сервиса: /healthz и /readyz на слушателях с группой ops,
+// остальные пути отвечают 503.
+func startupHandler(healthState *health.Health, routes listen.RouteSet) http.Handler {
+	c := &Controller{health: healthState, codecs: codec.Default(), metrics: newHTTPMetrics(metrics.NewRegistry())}
 	router := http.NewServeMux()
+	if routes.Has(listen.RoutesOps) {
+		router.HandleFunc("GET /healthz", c.MiddlewareAccept(c.Healthz()))
+		router.HandleFunc("GET /readyz", c.MiddlewareAccept(c.Readyz()))
+	}
+	router.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "1")
+		http.Error(w, "сервис запускается", http.StatusServiceUnavailable)
+	})
+	return router
+}
+
+// Server - Слушатели сервиса. Открываются до восстановления данных, чтобы /readyz показывал, что оно идёт:
+// до Run на них отвечают только /healthz и /readyz.
+type Server struct {
+	conf     *config.Config
+	health   *health.Health
+	specs    []*listen.Spec
+	servers  []*http.Server
+	handlers []*handlerSwitch
+	errCh    chan error
+}
+
+// Listen - Открывает все слушатели и начинает обслуживать на них /healthz и /readyz.
+// tlsConfig не nil - слушатели с включённым TLS принимают только HTTPS.
+func Listen(conf *config.Config, tlsConfig *tls.Config, healthState *health.Health) (*Server, error) {
+	specs, err := listenerSpecs(&conf.Server)
+	if err != nil {
+		return nil, err
+	}
+
+	serverConf := conf.Server
+	s := &Server{conf: conf, health: healthState, specs: specs}
+	listeners := make([]net.Listener, 0, len(specs))
+	for _, spec := range specs {
+		ln, err := listen.Open(spec)
+		if err != nil {
+			for _, opened := range listeners {
+				opened.Close()
+			}
+			return nil, fmt.Errorf("не удалось открыть слушатель %s %s: %w", spec.Network, spec.Address, err)
+		}
+		listeners = append(listeners, ln)
+
+		handler := &handlerSwitch{}
+		handler.set(startupHandler(healthState, spec.Routes))
+		server := &http.Server{
+			Handler:           handler,
+			ReadTimeout:       serverConf.ReadTimeout,
+			ReadHeaderTimeout: serverConf.ReadHeaderTimeout,
+			WriteTimeout:      serverConf.WriteTimeout,
+			IdleTimeout:       serverConf.IdleTimeout,
+			MaxHeaderBytes:    serverConf.MaxHeaderBytes,
+			ErrorLog:          slog.NewLogLogger(slog.Default().Handler(), slog.LevelWarn),
+		}
+		if spec.TLS && tlsConfig != nil {
+			server.TLSConfig = tlsConfig
+		}
+		s.servers = append(s.servers, server)
+		s.handlers = append(s.handlers, handler)
+	}
+
+	s.errCh = make(chan error, len(s.servers))
+	for i, server := range s.servers {
+		spec, ln := specs[i], listeners[i]
+		go func() {
+			slog.Info("слушатель открыт", "network", spec.Network, "listen", ln.Addr().String(), "routes", spec.Routes.String(), "tls", server.TLSConfig != nil)
+			var err error
+			if server.TLSConfig != nil {
+				// Сертификат отдаёт tlsConfig.GetCertificate, поэтому файлы здесь не указываются.
+				err = server.ServeTLS(ln, "", "")
+			} else {
+				err = server.Serve(ln)
+			}
+			if !errors.Is(err, http.ErrServerClosed) {
+				s.errCh <- fmt.Errorf("слушатель %s %s: %w", spec.Network, spec.Address, err)
+			}
+		}()
+	}
+	return s, nil
+}
+
+// Close - Закрывает слушатели и обрывает соединения. Нужен, если сервис не собрался и Run не вызывался.
+func (s *Server) Close() {
+	for _, server := range s.servers {
+		server.Close()
+	}
+}
+
+// Run - Подключает маршруты контроллера ко всем слушателям и блокируется до отмены ctx. После отмены переводит
+// /readyz в not_ready, перестаёт принимать соединения и ждёт завершения активных запросов не дольше SHUTDOWN_TIMEOUT.
+func (s *Server) Run(ctx context.Context, c *Controller) error {
+	if c.auth == nil && c.certAuth == nil {
+		slog.Warn("авторизация отключена, эндпоинты доступны без ключа")
+	}
 
-	router.HandleFunc("POST /quotes", c.MiddlewareValidate(c.AddQuote()))
-	router.HandleFunc("DELETE /quotes/{id}", c.MiddlewareValidate(c.DeleteQuote()))
-	router.HandleFunc("GET /quotes", c.GetQuotesHandler())
-	router.HandleFunc("GET /quotes/random", c.RandomQuote())
+	serverConf := s.conf.Server
+	for i, spec := range s.specs {
+		s.handlers[i].set(c.Handler(s.conf, spec.Routes))
+	}
+	slog.Info("сервер запущен", "listeners", len(s.specs))
+
+	var serveErr error
+	select {
+	case serveErr = <-s.errCh:
+		slog.Error("слушатель остановился, сервер завершает работу", "error", serveErr)
+	case <-ctx.Done():
+		slog.Info("получен сигнал остановки, сервер перестаёт принимать запросы", "drain_delay", serverConf.DrainDelay, "timeout", serverConf.ShutdownTimeout)
+		s.health.SetDraining(true)
+		time.Sleep(serverConf.DrainDelay)
+	}
 
-	addrConf := shared.GetAddr()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), serverConf.ShutdownTimeout)
+	defer cancel()
 
-	log.Printf("INFO: сервер на порту%s запущен.", addrConf.Port)
-	if err := http.ListenAndServe(addrConf.Port, router); err != nil {
-		log.Fatal("не удалось запустить сервер,ошибка:", err)
+	var wg sync.WaitGroup
+	shutdownErrs := make([]error, len(s.servers))
+	for i, server := range s.servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := server.Shutdown(shutdownCtx); err != nil {
+				// Не уложились в срок - обрываем оставшиеся соединения.
+				server.Close()
+				shutdownErrs[i] = err
+			}
+		}()
 	}
+	wg.Wait()
+
+	if serveErr != nil {
+		return serveErr
+	}
+	if err := errors.Join(shutdownErrs...); err != nil {
+		return fmt.Errorf("не все запросы завершились за %s: %w", serverConf.ShutdownTimeout, err)
+	}
+	slog.Info("сервер остановлен")
+
+	return nil
 }
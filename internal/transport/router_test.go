@@ -0,0 +1,63 @@
+package transport_test
+
+import (
+	"context"
+	"encoding/json"
+	"go-offline-test/internal/health"
+	"go-offline-test/internal/shared"
+	"go-offline-test/internal/shared/dto"
+	"go-offline-test/internal/transport"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+)
+
+// TestListenDuringReplay - Слушатели открыты до восстановления данных: /readyz отвечает replaying,
+// остальные маршруты - 503.
+func TestListenDuringReplay(t *testing.T) {
+	sock := filepath.Join(t.TempDir(), "quotes.sock")
+	conf := shared.DefaultConfig()
+	conf.Server.Listeners = []string{"unix://" + sock}
+
+	healthState := health.New()
+	replayDone := healthState.BeginReplay("audit")
+	server, err := transport.Listen(conf, nil, healthState)
+	if err != nil {
+		t.Fatalf("Listen(): %v", err)
+	}
+	defer server.Close()
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", sock)
+		},
+	}}
+	get := func(path string) (*http.Response, *dto.Health) {
+		t.Helper()
+		resp, err := client.Get("http://quotes" + path)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		defer resp.Body.Close()
+		var body dto.Health
+		json.NewDecoder(resp.Body).Decode(&body)
+		return resp, &body
+	}
+
+	resp, body := get("/readyz")
+	if resp.StatusCode != http.StatusServiceUnavailable || body.Reason != "replaying" {
+		t.Errorf("/readyz во время восстановления: %d %+v, ожидалось 503 replaying", resp.StatusCode, body)
+	}
+	if resp, _ := get("/healthz"); resp.StatusCode != http.StatusOK {
+		t.Errorf("/healthz: %d, ожидалось 200", resp.StatusCode)
+	}
+	if resp, _ := get("/quotes"); resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("/quotes до запуска: %d, ожидалось 503", resp.StatusCode)
+	}
+
+	replayDone()
+	if resp, body := get("/readyz"); resp.StatusCode != http.StatusOK || body.Status != health.StatusReady {
+		t.Errorf("/readyz после восстановления: %d %+v, ожидалось 200", resp.StatusCode, body)
+	}
+}
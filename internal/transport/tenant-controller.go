@@ -0,0 +1,63 @@
+package transport
+
+import (
+	"encoding/json"
+	"go-offline-test/internal/i18n"
+	"go-offline-test/internal/shared/dto"
+	"net/http"
+)
+
+func (c *Controller) ListTenants() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tenants, err := c.tenants.ListTenants(r.Context())
+		if err != nil {
+			c.error(w, r, err, "")
+			return
+		}
+		c.respond(w, r, tenants, http.StatusOK)
+	}
+}
+
+func (c *Controller) CreateTenant() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req dto.CreateTenant
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			c.error(w, r, i18n.Errorf("request.body_malformed", err), dto.CodeMalformedBody)
+			return
+		}
+
+		tenant, err := c.tenants.CreateTenant(r.Context(), &req)
+		if err != nil {
+			c.error(w, r, err, "")
+			return
+		}
+		c.respond(w, r, tenant, http.StatusCreated)
+	}
+}
+
+func (c *Controller) UpdateTenantQuota() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var quota dto.Quota
+		if err := json.NewDecoder(r.Body).Decode(&quota); err != nil {
+			c.error(w, r, i18n.Errorf("request.body_malformed", err), dto.CodeMalformedBody)
+			return
+		}
+
+		tenant, err := c.tenants.UpdateQuota(r.Context(), r.PathValue("name"), &quota)
+		if err != nil {
+			c.error(w, r, err, "")
+			return
+		}
+		c.respond(w, r, tenant, http.StatusOK)
+	}
+}
+
+func (c *Controller) DeleteTenant() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := c.tenants.DeleteTenant(r.Context(), r.PathValue("name")); err != nil {
+			c.error(w, r, err, "")
+			return
+		}
+		c.respond(w, r, nil, http.StatusNoContent)
+	}
+}
@@ -0,0 +1,286 @@
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-offline-test/internal/shared/dto/config"
+	"go-offline-test/internal/shared/encoding/toml"
+	"go-offline-test/internal/shared/encoding/yaml"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DefaultSet - Имя набора из плоских ключей validation.*. Действует, когда для тенанта и маршрута
+// не выбран другой набор.
+const DefaultSet = "default"
+
+// Rules - Наборы правил и их привязка к тенантам и маршрутам.
+type Rules struct {
+	sets    map[string]*RuleSet
+	tenants map[string]string
+	routes  map[string]string
+}
+
+// Select - Набор для запроса. Привязка к маршруту важнее привязки к тенанту: маршрут описывает
+// сами данные, а тенант - только их владельца.
+func (r *Rules) Select(tenant, route string) *RuleSet {
+	if name, ok := r.routes[route]; ok {
+		return r.sets[name]
+	}
+	if name, ok := r.tenants[tenant]; ok {
+		return r.sets[name]
+	}
+	return r.sets[DefaultSet]
+}
+
+// Sets - Имена наборов по алфавиту.
+func (r *Rules) Sets() []string {
+	names := make([]string, 0, len(r.sets))
+	for name := range r.sets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Load - Собирает наборы правил из настроек и файла validation.rules_file. Возвращает все найденные
+// ошибки сразу, как и проверка остальных настроек. При ошибках Rules не возвращается.
+func Load(conf config.ValidationConfig) (*Rules, []error) {
+	var errs []error
+	fail := func(key, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("validation.%s: %s", key, fmt.Sprintf(format, args...)))
+	}
+
+	base := &RuleSet{
+		Name: DefaultSet,
+		Quote: FieldRules{
+			MinLength:      conf.QuoteMinLength,
+			MaxLength:      conf.QuoteMaxLength,
+			Chars:          conf.QuoteChars,
+			ForbiddenWords: conf.ForbiddenWords,
+		},
+		Author: FieldRules{
+			MinLength:      conf.AuthorMinLength,
+			MaxLength:      conf.AuthorMaxLength,
+			Chars:          conf.AuthorChars,
+			ForbiddenWords: conf.ForbiddenWords,
+			ForbiddenEdges: conf.AuthorForbiddenEdges,
+		},
+	}
+	if err := checkClasses(conf.QuoteChars); err != nil {
+		fail("quote_chars", "%v", err)
+	}
+	if err := checkClasses(conf.AuthorChars); err != nil {
+		fail("author_chars", "%v", err)
+	}
+	if err := checkClasses(conf.AuthorForbiddenEdges); err != nil {
+		fail("author_forbidden_edges", "%v", err)
+	}
+	var err error
+	if base.Quote.Pattern, err = compile(conf.QuotePattern); err != nil {
+		fail("quote_pattern", "%v", err)
+	}
+	if base.Author.Pattern, err = compile(conf.AuthorPattern); err != nil {
+		fail("author_pattern", "%v", err)
+	}
+
+	rules := &Rules{
+		sets:    map[string]*RuleSet{DefaultSet: base},
+		tenants: conf.TenantRules,
+		routes:  conf.RouteRules,
+	}
+	if conf.RulesFile != "" {
+		sets, fileErrs := readFile(conf.RulesFile, base)
+		errs = append(errs, fileErrs...)
+		for name, set := range sets {
+			rules.sets[name] = set
+		}
+	}
+
+	for _, name := range sortedKeys(conf.TenantRules) {
+		if set := conf.TenantRules[name]; rules.sets[set] == nil {
+			fail("tenant_rules", "тенант %q: неизвестный набор правил %q", name, set)
+		}
+	}
+	for _, route := range sortedKeys(conf.RouteRules) {
+		if set := conf.RouteRules[route]; rules.sets[set] == nil {
+			fail("route_rules", "маршрут %q: неизвестный набор правил %q", route, set)
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return rules, nil
+}
+
+// readFile - Наборы из файла правил. Каждый набор дополняет base: поля, не указанные в файле, берутся
+// из плоских ключей. Набор default в файле меняет сам base.
+func readFile(path string, base *RuleSet) (map[string]*RuleSet, []error) {
+	prefix := "validation.rules_file " + path
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, []error{fmt.Errorf("validation.rules_file: %w", err)}
+	}
+
+	var doc any
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		err = json.Unmarshal(data, &doc)
+	case ".toml":
+		doc, err = toml.Unmarshal(data)
+	case ".yaml", ".yml":
+		doc, err = yaml.Unmarshal(data)
+	default:
+		return nil, []error{fmt.Errorf("%s: неизвестный формат %q, ожидается .json, .toml, .yaml или .yml", prefix, ext)}
+	}
+	if err != nil {
+		return nil, []error{fmt.Errorf("%s: %w", prefix, err)}
+	}
+	root, ok := doc.(map[string]any)
+	if !ok {
+		return nil, []error{fmt.Errorf("%s: на верхнем уровне ожидается объект с наборами правил", prefix)}
+	}
+
+	var errs []error
+	sets := make(map[string]*RuleSet, len(root))
+	// default разбирается первым, остальные наборы наследуют уже изменённые правила.
+	names := sortedKeys(root)
+	sort.SliceStable(names, func(i, j int) bool { return names[i] == DefaultSet && names[j] != DefaultSet })
+	for _, name := range names {
+		fields, ok := root[name].(map[string]any)
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: набор %q: ожидается объект с полями quote и author", prefix, name))
+			continue
+		}
+		set := &RuleSet{Name: name, Quote: base.Quote, Author: base.Author}
+		for _, field := range sortedKeys(fields) {
+			var target *FieldRules
+			switch field {
+			case FieldQuote:
+				target = &set.Quote
+			case FieldAuthor:
+				target = &set.Author
+			default:
+				errs = append(errs, fmt.Errorf("%s: набор %q: неизвестное поле %q", prefix, name, field))
+				continue
+			}
+			for _, err := range parseFieldRules(fields[field], target) {
+				errs = append(errs, fmt.Errorf("%s: набор %q, поле %s: %w", prefix, name, field, err))
+			}
+		}
+		if name == DefaultSet {
+			*base = *set
+			continue
+		}
+		sets[name] = set
+	}
+	return sets, errs
+}
+
+// parseFieldRules - Переносит в fr правила поля из файла. Неизвестные ключи - ошибка, чтобы опечатка
+// не отключала правило молча.
+func parseFieldRules(v any, fr *FieldRules) []error {
+	values, ok := v.(map[string]any)
+	if !ok {
+		return []error{fmt.Errorf("ожидается объект с правилами")}
+	}
+
+	var errs []error
+	for _, key := range sortedKeys(values) {
+		value := values[key]
+		var err error
+		switch key {
+		case "min_length":
+			fr.MinLength, err = toInt(value)
+		case "max_length":
+			fr.MaxLength, err = toInt(value)
+		case "chars":
+			if fr.Chars, err = toStrings(value); err == nil {
+				err = checkClasses(fr.Chars)
+			}
+		case "forbidden_edges":
+			if fr.ForbiddenEdges, err = toStrings(value); err == nil {
+				err = checkClasses(fr.ForbiddenEdges)
+			}
+		case "forbidden_words":
+			fr.ForbiddenWords, err = toStrings(value)
+		case "pattern":
+			s, ok := value.(string)
+			if !ok {
+				err = fmt.Errorf("ожидается строка")
+				break
+			}
+			fr.Pattern, err = compile(s)
+		default:
+			err = fmt.Errorf("неизвестное правило")
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", key, err))
+		}
+	}
+
+	if fr.MinLength < 1 {
+		errs = append(errs, fmt.Errorf("min_length: должна быть не меньше 1"))
+	}
+	if fr.MaxLength != 0 && fr.MaxLength < fr.MinLength {
+		errs = append(errs, fmt.Errorf("max_length: должна быть не меньше min_length (%d)", fr.MinLength))
+	}
+	return errs
+}
+
+func checkClasses(classes []string) error {
+	for _, name := range classes {
+		if _, ok := charClasses[name]; !ok {
+			return fmt.Errorf("неизвестный класс символов %q, допустимы: %s", name, strings.Join(CharClasses(), ", "))
+		}
+	}
+	return nil
+}
+
+func compile(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	return regexp.Compile(pattern)
+}
+
+func toInt(v any) (int, error) {
+	switch n := v.(type) {
+	case int64:
+		return int(n), nil
+	case float64:
+		if n == float64(int(n)) {
+			return int(n), nil
+		}
+	}
+	return 0, fmt.Errorf("ожидается целое число, получено %v", v)
+}
+
+func toStrings(v any) ([]string, error) {
+	items, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf("ожидается список строк")
+	}
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		s, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("ожидается список строк, получено %v", item)
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
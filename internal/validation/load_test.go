@@ -0,0 +1,185 @@
+package validation_test
+
+import (
+	"go-offline-test/internal/shared/dto/config"
+	"go-offline-test/internal/validation"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// baseConfig - Плоские правила, от которых наследуют наборы из файла.
+func baseConfig() config.ValidationConfig {
+	return config.ValidationConfig{
+		QuoteMinLength:       1,
+		QuoteMaxLength:       500,
+		AuthorMinLength:      2,
+		AuthorMaxLength:      100,
+		AuthorChars:          []string{"letter", "space", "hyphen"},
+		AuthorForbiddenEdges: []string{"hyphen"},
+	}
+}
+
+// writeRules - Файл правил во временном каталоге теста.
+func writeRules(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadSelect(t *testing.T) {
+	conf := baseConfig()
+	conf.RulesFile = writeRules(t, "rules.yaml", `default:
+  quote:
+    max_length: 300
+strict:
+  quote:
+    min_length: 10
+short:
+  author:
+    max_length: 20
+`)
+	conf.TenantRules = map[string]string{"acme": "strict"}
+	conf.RouteRules = map[string]string{"POST /quotes": "short"}
+
+	rules, errs := validation.Load(conf)
+	if len(errs) > 0 {
+		t.Fatalf("Load(): %v", errs)
+	}
+	if got := strings.Join(rules.Sets(), ","); got != "default,short,strict" {
+		t.Errorf("Sets() = %s, ожидалось default,short,strict", got)
+	}
+
+	tests := []struct {
+		name, tenant, route, want string
+	}{
+		{"без привязки", "other", "GET /quotes", validation.DefaultSet},
+		{"по тенанту", "acme", "GET /quotes", "strict"},
+		{"маршрут важнее тенанта", "acme", "POST /quotes", "short"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rules.Select(tt.tenant, tt.route).Name; got != tt.want {
+				t.Errorf("Select(%q, %q) = %s, ожидалось %s", tt.tenant, tt.route, got, tt.want)
+			}
+		})
+	}
+
+	// Наборы наследуют плоские ключи и изменения набора default из файла.
+	strict := rules.Select("acme", "")
+	if strict.Quote.MinLength != 10 || strict.Quote.MaxLength != 300 || strict.Author.MaxLength != 100 {
+		t.Errorf("strict: quote %d..%d, author до %d, ожидалось 10..300 и до 100",
+			strict.Quote.MinLength, strict.Quote.MaxLength, strict.Author.MaxLength)
+	}
+	if def := rules.Select("", ""); def.Quote.MaxLength != 300 {
+		t.Errorf("default: quote до %d, ожидалось 300", def.Quote.MaxLength)
+	}
+}
+
+func TestLoadFormats(t *testing.T) {
+	files := map[string]string{
+		"rules.json": `{"strict": {"author": {"min_length": 5, "chars": ["letter"]}}}`,
+		"rules.toml": "[strict.author]\nmin_length = 5\nchars = [\"letter\"]\n",
+		"rules.yml":  "strict:\n  author:\n    min_length: 5\n    chars: [letter]\n",
+	}
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			conf := baseConfig()
+			conf.RulesFile = writeRules(t, name, content)
+			conf.TenantRules = map[string]string{"acme": "strict"}
+			rules, errs := validation.Load(conf)
+			if len(errs) > 0 {
+				t.Fatalf("Load(): %v", errs)
+			}
+			if author := rules.Select("acme", "").Author; author.MinLength != 5 || len(author.Chars) != 1 {
+				t.Errorf("author: min_length %d, chars %v, ожидалось 5 и [letter]", author.MinLength, author.Chars)
+			}
+		})
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		file  string
+		edit  func(*config.ValidationConfig)
+		wants []string
+	}{
+		{
+			name: "неизвестные наборы тенанта и маршрута",
+			edit: func(c *config.ValidationConfig) {
+				c.TenantRules = map[string]string{"acme": "strict"}
+				c.RouteRules = map[string]string{"POST /quotes": "short"}
+			},
+			wants: []string{
+				`validation.tenant_rules: тенант "acme": неизвестный набор правил "strict"`,
+				`validation.route_rules: маршрут "POST /quotes": неизвестный набор правил "short"`,
+			},
+		},
+		{
+			name: "ошибки плоских ключей собираются вместе",
+			edit: func(c *config.ValidationConfig) {
+				c.AuthorChars = []string{"letters"}
+				c.QuotePattern = "("
+			},
+			wants: []string{
+				`validation.author_chars: неизвестный класс символов "letters"`,
+				"validation.quote_pattern: ",
+			},
+		},
+		{
+			name: "ошибки в файле правил",
+			file: "strict:\n  quote:\n    min_lenght: 5\n    max_length: 0.5\n  author:\n    min_length: 10\n    max_length: 5\n  tags: {}\n",
+			wants: []string{
+				`набор "strict", поле quote: max_length: ожидается целое число`,
+				`набор "strict", поле quote: min_lenght: неизвестное правило`,
+				`набор "strict", поле author: max_length: должна быть не меньше min_length (10)`,
+				`набор "strict": неизвестное поле "tags"`,
+			},
+		},
+		{
+			name:  "набор не объект",
+			file:  "strict: 1\n",
+			wants: []string{`набор "strict": ожидается объект с полями quote и author`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := baseConfig()
+			if tt.file != "" {
+				conf.RulesFile = writeRules(t, "rules.yaml", tt.file)
+			}
+			if tt.edit != nil {
+				tt.edit(&conf)
+			}
+			rules, errs := validation.Load(conf)
+			if rules != nil {
+				t.Error("Load() с ошибками вернул правила")
+			}
+			if len(errs) != len(tt.wants) {
+				t.Errorf("Load() вернул %d ошибок, ожидалось %d: %v", len(errs), len(tt.wants), errs)
+			}
+			for _, want := range tt.wants {
+				found := false
+				for _, err := range errs {
+					found = found || strings.Contains(err.Error(), want)
+				}
+				if !found {
+					t.Errorf("Load(): нет ошибки %q среди %v", want, errs)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadUnknownFormat(t *testing.T) {
+	conf := baseConfig()
+	conf.RulesFile = writeRules(t, "rules.ini", "[strict]\n")
+	if _, errs := validation.Load(conf); len(errs) != 1 || !strings.Contains(errs[0].Error(), "неизвестный формат") {
+		t.Errorf("Load() = %v, ожидалась ошибка о неизвестном формате", errs)
+	}
+}
@@ -0,0 +1,174 @@
+package validation
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Поля цитаты, для которых задаются правила.
+const (
+	FieldQuote  = "quote"
+	FieldAuthor = "author"
+)
+
+// charClasses - Классы символов, из которых собираются допустимые наборы.
+var charClasses = map[string]func(r rune) bool{
+	"letter": func(r rune) bool { return unicode.IsLetter(r) || unicode.IsMark(r) },
+	"digit":  unicode.IsDigit,
+	"space":  unicode.IsSpace,
+	"hyphen": func(r rune) bool { return r == '-' || unicode.Is(unicode.Hyphen, r) },
+	"apostrophe": func(r rune) bool {
+		return r == '\'' || r == '’' || r == 'ʼ'
+	},
+	"punct":  unicode.IsPunct,
+	"symbol": unicode.IsSymbol,
+}
+
+// CharClasses - Имена известных классов символов по алфавиту.
+func CharClasses() []string {
+	names := make([]string, 0, len(charClasses))
+	for name := range charClasses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FieldRules - Правила одного поля. Длина считается в символах, а не в байтах.
+type FieldRules struct {
+	MinLength int
+	// MaxLength - 0 - без ограничения.
+	MaxLength int
+	// Chars - Допустимые классы символов. Пусто - любые.
+	Chars []string
+	// Pattern - Регулярное выражение, которое должно найтись в значении. Чтобы проверить значение
+	// целиком, шаблон записывается с ^ и $. nil - не проверяется.
+	Pattern *regexp.Regexp
+	// ForbiddenWords - Слова и фразы, которых не должно быть в значении. Регистр не учитывается.
+	ForbiddenWords []string
+	// ForbiddenEdges - Классы символов, с которых значение не может начинаться и которыми не может заканчиваться.
+	ForbiddenEdges []string
+}
+
+// Violation - Нарушенное правило: поле, ключ сообщения в каталоге и параметры сообщения.
+type Violation struct {
+	Field string
+	Key   string
+	Args  []any
+}
+
+// Check - Все нарушения правил для значения поля. Пустое значение даёт одно нарушение: остальные
+// проверки для него бессмысленны.
+func (fr *FieldRules) Check(field, value string) []Violation {
+	value = strings.TrimSpace(value)
+	violation := func(rule string, args ...any) Violation {
+		return Violation{Field: field, Key: "validation." + field + "_" + rule, Args: args}
+	}
+	if value == "" {
+		return []Violation{violation("empty")}
+	}
+
+	var out []Violation
+	length := utf8.RuneCountInString(value)
+	if length < fr.MinLength {
+		out = append(out, violation("too_short", fr.MinLength))
+	}
+	if fr.MaxLength > 0 && length > fr.MaxLength {
+		out = append(out, violation("too_long", fr.MaxLength))
+	}
+
+	if len(fr.Chars) > 0 {
+		for _, r := range value {
+			if !inClasses(r, fr.Chars) {
+				out = append(out, violation("invalid_char", r))
+				break
+			}
+		}
+	}
+
+	if len(fr.ForbiddenEdges) > 0 {
+		first, _ := utf8.DecodeRuneInString(value)
+		last, _ := utf8.DecodeLastRuneInString(value)
+		if inClasses(first, fr.ForbiddenEdges) {
+			out = append(out, violation("edge", first))
+		} else if inClasses(last, fr.ForbiddenEdges) {
+			out = append(out, violation("edge", last))
+		}
+	}
+
+	if fr.Pattern != nil && !fr.Pattern.MatchString(value) {
+		out = append(out, violation("pattern", fr.Pattern.String()))
+	}
+
+	if found := forbiddenWords(value, fr.ForbiddenWords); len(found) > 0 {
+		out = append(out, violation("forbidden_words", strings.Join(found, ", ")))
+	}
+	return out
+}
+
+func inClasses(r rune, classes []string) bool {
+	for _, name := range classes {
+		if is, ok := charClasses[name]; ok && is(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// forbiddenWords - Запрещённые слова, которые встречаются в тексте. Слово совпадает только целиком
+// ("кот" не находится в "который"), фраза из нескольких слов ищется как подстрока.
+func forbiddenWords(text string, forbidden []string) []string {
+	if len(forbidden) == 0 {
+		return nil
+	}
+	lower := strings.ToLower(text)
+	words := make(map[string]bool)
+	for _, w := range strings.FieldsFunc(lower, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r)
+	}) {
+		words[w] = true
+	}
+
+	var found []string
+	for _, f := range forbidden {
+		f = strings.ToLower(strings.TrimSpace(f))
+		if f == "" {
+			continue
+		}
+		if strings.ContainsFunc(f, unicode.IsSpace) {
+			if strings.Contains(lower, f) {
+				found = append(found, f)
+			}
+		} else if words[f] {
+			found = append(found, f)
+		}
+	}
+	return found
+}
+
+// RuleSet - Именованный набор правил для полей цитаты.
+type RuleSet struct {
+	Name   string
+	Quote  FieldRules
+	Author FieldRules
+}
+
+// Check - Нарушения для полей цитаты. Без fields проверяются все поля.
+func (rs *RuleSet) Check(text, author string, fields ...string) []Violation {
+	if len(fields) == 0 {
+		fields = []string{FieldQuote, FieldAuthor}
+	}
+	var out []Violation
+	for _, field := range fields {
+		switch field {
+		case FieldQuote:
+			out = append(out, rs.Quote.Check(FieldQuote, text)...)
+		case FieldAuthor:
+			out = append(out, rs.Author.Check(FieldAuthor, author)...)
+		}
+	}
+	return out
+}
@@ -0,0 +1,130 @@
+package validation_test
+
+import (
+	"go-offline-test/internal/validation"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestFieldRulesLength(t *testing.T) {
+	rules := validation.FieldRules{MinLength: 3, MaxLength: 5}
+	tests := []struct {
+		name  string
+		value string
+		want  []validation.Violation
+	}{
+		{"кириллица на нижней границе", "абв", nil},
+		{"кириллица на верхней границе", "абвгд", nil},
+		{"кириллица на символ длиннее", "абвгде", []validation.Violation{
+			{Field: "quote", Key: "validation.quote_too_long", Args: []any{5}},
+		}},
+		{"кириллица на символ короче", "аб", []validation.Violation{
+			{Field: "quote", Key: "validation.quote_too_short", Args: []any{3}},
+		}},
+		// 5 символов по 4 байта: 20 байт, но длина в пределах.
+		{"эмодзи на верхней границе", "😀😀😀😀😀", nil},
+		{"пробелы по краям не считаются", "  абв  ", nil},
+		{"пустое значение - одно нарушение", "   ", []validation.Violation{
+			{Field: "quote", Key: "validation.quote_empty"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rules.Check("quote", tt.value); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Check(%q) = %#v, ожидалось %#v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldRulesViolations(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules validation.FieldRules
+		value string
+		want  []validation.Violation
+	}{
+		{
+			name: "все нарушения сразу",
+			rules: validation.FieldRules{
+				MinLength:      1,
+				MaxLength:      10,
+				Chars:          []string{"letter", "space", "hyphen"},
+				ForbiddenEdges: []string{"hyphen"},
+				Pattern:        regexp.MustCompile(`^[А-ЯЁ]`),
+				ForbiddenWords: []string{"спам", "плохое слово"},
+			},
+			value: "-спам 1 плохое слово",
+			want: []validation.Violation{
+				{Field: "author", Key: "validation.author_too_long", Args: []any{10}},
+				{Field: "author", Key: "validation.author_invalid_char", Args: []any{'1'}},
+				{Field: "author", Key: "validation.author_edge", Args: []any{'-'}},
+				{Field: "author", Key: "validation.author_pattern", Args: []any{`^[А-ЯЁ]`}},
+				{Field: "author", Key: "validation.author_forbidden_words", Args: []any{"спам, плохое слово"}},
+			},
+		},
+		{
+			name:  "запрещённый символ в конце",
+			rules: validation.FieldRules{MinLength: 1, ForbiddenEdges: []string{"hyphen"}},
+			value: "Толстой-",
+			want:  []validation.Violation{{Field: "author", Key: "validation.author_edge", Args: []any{'-'}}},
+		},
+		{
+			name:  "буква с диакритикой из двух символов",
+			rules: validation.FieldRules{MinLength: 1, Chars: []string{"letter"}},
+			value: "Зола́",
+		},
+		{
+			name:  "запрещённое слово только целиком и без учёта регистра",
+			rules: validation.FieldRules{MinLength: 1, ForbiddenWords: []string{"Кот"}},
+			value: "Который КОТ",
+			want: []validation.Violation{
+				{Field: "author", Key: "validation.author_forbidden_words", Args: []any{"кот"}},
+			},
+		},
+		{
+			name:  "часть слова не запрещена",
+			rules: validation.FieldRules{MinLength: 1, ForbiddenWords: []string{"кот"}},
+			value: "который",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rules.Check("author", tt.value); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Check(%q) = %#v, ожидалось %#v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRuleSetCheck(t *testing.T) {
+	set := validation.RuleSet{
+		Quote:  validation.FieldRules{MinLength: 5},
+		Author: validation.FieldRules{MinLength: 2},
+	}
+	keys := func(vs []validation.Violation) []string {
+		var out []string
+		for _, v := range vs {
+			out = append(out, v.Key)
+		}
+		return out
+	}
+
+	all := keys(set.Check("abc", "x"))
+	if want := []string{"validation.quote_too_short", "validation.author_too_short"}; !reflect.DeepEqual(all, want) {
+		t.Errorf("Check() = %v, ожидалось %v", all, want)
+	}
+	only := keys(set.Check("abc", "x", validation.FieldAuthor))
+	if want := []string{"validation.author_too_short"}; !reflect.DeepEqual(only, want) {
+		t.Errorf("Check(author) = %v, ожидалось %v", only, want)
+	}
+}
+
+func TestCharClasses(t *testing.T) {
+	got := strings.Join(validation.CharClasses(), ",")
+	if want := "apostrophe,digit,hyphen,letter,punct,space,symbol"; got != want {
+		t.Errorf("CharClasses() = %s, ожидалось %s", got, want)
+	}
+}
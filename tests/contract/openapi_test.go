@@ -0,0 +1,682 @@
+package contract_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-offline-test/internal/audit"
+	"go-offline-test/internal/auth"
+	"go-offline-test/internal/filter"
+	"go-offline-test/internal/health"
+	"go-offline-test/internal/listen"
+	"go-offline-test/internal/metrics"
+	"go-offline-test/internal/repository"
+	"go-offline-test/internal/services"
+	"go-offline-test/internal/shared"
+	"go-offline-test/internal/shared/dto"
+	"go-offline-test/internal/shared/dto/config"
+	"go-offline-test/internal/transport"
+	"go-offline-test/internal/validation"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// contractServer - Контроллер со всеми слоями в памяти и API-ключами, чтобы были доступны все маршруты.
+type contractServer struct {
+	controller *transport.Controller
+	handler    http.Handler
+	keys       *auth.KeyStore
+	admin      string
+}
+
+func newContractServer(t *testing.T) *contractServer {
+	t.Helper()
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	conf := shared.DefaultConfig()
+	conf.Server.MaxBodyBytes = 1 << 10
+	// Модерация включена только для тенанта review, чтобы остальной сценарий видел цитаты сразу.
+	conf.Moderation.Tenants = []string{"review"}
+	conf.Filter.Chain = []string{"profanity", "spam", "repeats"}
+
+	keys, err := auth.NewKeyStore(filepath.Join(t.TempDir(), "keys.json"))
+	if err != nil {
+		t.Fatalf("NewKeyStore() error = %v", err)
+	}
+	admin, _, err := keys.Create(context.Background(), "contract", []auth.Scope{auth.ScopeAdmin, auth.ScopeQuotesRead, auth.ScopeQuotesWrite}, nil)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	auditLog, err := audit.NewLog("", 100)
+	if err != nil {
+		t.Fatalf("NewLog() error = %v", err)
+	}
+	tenants := repository.NewTenantRegistry(repository.Quota{})
+	rules, errs := validation.Load(conf.Validation)
+	if len(errs) > 0 {
+		t.Fatalf("validation.Load() errors = %v", errs)
+	}
+	configService := services.NewConfigService(conf, func() (*config.Config, error) {
+		return shared.DefaultConfig(), nil
+	})
+
+	filters, errs := filter.Load(conf.Filter)
+	if len(errs) > 0 {
+		t.Fatalf("filter.Load() errors = %v", errs)
+	}
+
+	quotes := services.NewQuoteService(tenants, auditLog, rules, filters, conf.Moderation)
+	controller := transport.NewController(
+		quotes,
+		quotes,
+		quotes,
+		quotes,
+		services.NewTenantService(tenants),
+		services.NewAuditService(auditLog),
+		configService,
+		keys, nil, keys,
+		metrics.NewRegistry(),
+		health.New(),
+	)
+	return &contractServer{
+		controller: controller,
+		handler:    controller.Handler(conf, listen.RoutesAll),
+		keys:       keys,
+		admin:      admin,
+	}
+}
+
+// contractCall - Запрос сценария. Токен по умолчанию - админский, noAuth отправляет запрос без него.
+type contractCall struct {
+	method string
+	path   string
+	header map[string]string
+	body   string
+	token  string
+	noAuth bool
+	status int
+	// code - Ожидаемый код ошибки в теле ответа. Пусто - не проверяется.
+	code string
+	// save - Запомнить значение поля ответа для следующих запросов: {id} в path и body подставляется из vars.
+	save map[string]string
+}
+
+func (s *contractServer) do(t *testing.T, call *contractCall, vars map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	path, payload := call.path, call.body
+	for name, value := range vars {
+		path = strings.ReplaceAll(path, "{"+name+"}", value)
+		payload = strings.ReplaceAll(payload, "{"+name+"}", value)
+	}
+	var body io.Reader
+	if payload != "" {
+		body = strings.NewReader(payload)
+	}
+	req := httptest.NewRequest(call.method, path, body)
+	if call.body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for key, value := range call.header {
+		req.Header.Set(key, value)
+	}
+	switch {
+	case call.noAuth:
+	case call.token != "":
+		req.Header.Set("Authorization", "Bearer "+vars[call.token])
+	default:
+		req.Header.Set("Authorization", "Bearer "+s.admin)
+	}
+
+	rec := httptest.NewRecorder()
+	s.handler.ServeHTTP(rec, req)
+	return rec
+}
+
+// contractScenario - Запросы по всем операциям описания, включая ответы с ошибками.
+func contractScenario() []*contractCall {
+	longQuote := `{"quote":"` + strings.Repeat("a", 2000) + `","author":"Автор"}`
+	// Тенант review на модерации, см. newContractServer.
+	review := map[string]string{"X-Tenant": "review"}
+	return []*contractCall{
+		{method: "GET", path: "/openapi.json", noAuth: true, status: 200},
+		{method: "GET", path: "/docs", noAuth: true, status: 200},
+		{method: "GET", path: "/healthz", noAuth: true, status: 200},
+		{method: "GET", path: "/readyz", noAuth: true, status: 200},
+		{method: "GET", path: "/metrics", noAuth: true, status: 200},
+
+		{method: "GET", path: "/quotes", noAuth: true, status: 401, code: "UNAUTHORIZED"},
+		{method: "GET", path: "/quotes", status: 404, code: "NO_QUOTES"},
+		{method: "GET", path: "/quotes/random", status: 404, code: "NO_QUOTES"},
+		{method: "POST", path: "/quotes", body: `{"quote":"Stay hungry, stay foolish","author":"Steve Jobs"}`, status: 201, save: map[string]string{"quote_id": "id", "jobs_id": "author_id"}},
+		{method: "POST", path: "/quotes", body: `{"quote":"Stay hungry, stay foolish","author":"Steve Jobs"}`, status: 409, code: "QUOTE_ALREADY_EXISTS"},
+		{method: "POST", path: "/quotes", body: `{"quote":"","author":"Steve Jobs"}`, status: 400, code: "VALIDATION_FAILED"},
+		{method: "POST", path: "/quotes", body: `{"quote":"","author":"-Steve Jobs1"}`, status: 400, code: "VALIDATION_FAILED"},
+		{method: "POST", path: "/quotes", body: `{"quote":`, status: 400, code: "MALFORMED_BODY"},
+		{method: "POST", path: "/quotes", body: `{"quote":"Текст","author":"R2D2"}`, status: 400},
+		{method: "POST", path: "/quotes", body: longQuote, status: 413, code: "PAYLOAD_TOO_LARGE"},
+		{method: "GET", path: "/quotes", status: 200},
+		{method: "GET", path: "/quotes", header: map[string]string{"author": "Steve Jobs"}, status: 200},
+		{method: "GET", path: "/quotes/random", status: 200},
+		{method: "GET", path: "/quotes", header: map[string]string{"Accept": "text/csv"}, status: 200},
+		{method: "GET", path: "/quotes/random", header: map[string]string{"Accept": "application/xml"}, status: 200},
+		{method: "GET", path: "/quotes", header: map[string]string{"Accept": "application/pdf"}, status: 406, code: "NOT_ACCEPTABLE"},
+		{method: "POST", path: "/quotes", header: map[string]string{"Content-Type": "text/plain"}, body: "Простота - залог надёжности — Автор", status: 201},
+		{method: "POST", path: "/quotes", header: map[string]string{"Content-Type": "application/msword"}, body: "...", status: 415, code: "UNSUPPORTED_MEDIA_TYPE"},
+		{method: "POST", path: "/quotes", body: `{"quote":"Ну и fuuuck","author":"Автор"}`, status: 422, code: "CONTENT_REJECTED"},
+		{method: "POST", path: "/quotes", body: `{"quote":"Подробности на example.com","author":"Автор"}`, status: 201},
+		{method: "POST", path: "/quotes", body: `{"quote":"Нееееет!!!!!","author":"Автор"}`, status: 201},
+		{method: "PUT", path: "/authors/Steve%20Jobs", body: `{"full_name":"Steven Paul Jobs","birth_year":1955,"death_year":2011,"links":[{"title":"Wikipedia","url":"https://en.wikipedia.org/wiki/Steve_Jobs"}]}`, status: 200},
+		{method: "PUT", path: "/authors/Steve%20Jobs", body: `{"birth_year":1955,"death_year":1900,"links":[{"url":"ftp://example"}]}`, status: 400, code: "VALIDATION_FAILED"},
+		{method: "PUT", path: "/authors/Nobody", body: `{}`, status: 404, code: "AUTHOR_NOT_FOUND"},
+		{method: "GET", path: "/authors/Steve%20Jobs", status: 200},
+		{method: "GET", path: "/authors/Nobody", status: 404, code: "AUTHOR_NOT_FOUND"},
+		{method: "POST", path: "/authors", body: `{"name":"Steve Jobs","full_name":"Steve Jobs (тёзка)"}`, status: 201, save: map[string]string{"twin_id": "id"}},
+		{method: "POST", path: "/authors", body: `{"name":""}`, status: 400, code: "VALIDATION_FAILED"},
+		{method: "GET", path: "/authors/Steve%20Jobs", status: 409, code: "AUTHOR_AMBIGUOUS"},
+		{method: "GET", path: "/quotes", header: map[string]string{"author": "Steve Jobs"}, status: 409, code: "AUTHOR_AMBIGUOUS"},
+		{method: "POST", path: "/quotes", body: `{"quote":"Think different","author":"Steve Jobs"}`, status: 409, code: "AUTHOR_AMBIGUOUS"},
+		{method: "POST", path: "/quotes", body: `{"quote":"Think different","author_id":{twin_id}}`, status: 201},
+		{method: "POST", path: "/quotes", body: `{"quote":"Think different","author_id":999}`, status: 404, code: "AUTHOR_NOT_FOUND"},
+		{method: "PUT", path: "/authors/{twin_id}", body: `{"aliases":["Стив Джобс"]}`, status: 200},
+		{method: "PUT", path: "/authors/Автор", body: `{"aliases":["стив  джобс"]}`, status: 409, code: "AUTHOR_ALIAS_TAKEN"},
+		{method: "POST", path: "/authors/{twin_id}/merge", body: `{"into":{twin_id}}`, status: 400, code: "VALIDATION_FAILED"},
+		{method: "POST", path: "/authors/{twin_id}/merge", body: `{"into":999}`, status: 404, code: "AUTHOR_NOT_FOUND"},
+		{method: "POST", path: "/authors/{twin_id}/merge", body: `{"into":{jobs_id}}`, status: 200},
+		{method: "GET", path: "/authors/Стив%20Джобс", status: 200},
+		{method: "POST", path: "/quotes", body: `{"quote":"Your time is limited","author":"Steve Jobs","source":{"type":"speech","title":"Commencement address","publisher":"Stanford University","year":2005,"url":"https://news.stanford.edu/"}}`, status: 201, save: map[string]string{"cited_id": "id"}},
+		{method: "POST", path: "/quotes", body: `{"quote":"Без источника никуда","author":"Автор","source":{"type":"poem","title":"","year":5000,"pages":"9-3","url":"ftp://example"}}`, status: 400, code: "VALIDATION_FAILED"},
+		{method: "GET", path: "/quotes/{cited_id}/cite", status: 200},
+		{method: "GET", path: "/quotes/{cited_id}/cite?style=bibtex", header: map[string]string{"Accept": "text/plain"}, status: 200},
+		{method: "GET", path: "/quotes/{cited_id}/cite?style=harvard", status: 400, code: "VALIDATION_FAILED"},
+		{method: "GET", path: "/quotes/abc/cite", status: 400},
+		{method: "GET", path: "/quotes/999/cite", status: 404, code: "QUOTE_NOT_FOUND"},
+		{method: "GET", path: "/authors/Steve%20Jobs/citations?style=bibtex", status: 200},
+		{method: "GET", path: "/authors/Nobody/citations", status: 404, code: "AUTHOR_NOT_FOUND"},
+		{method: "GET", path: "/quotes?expand=author", status: 200},
+		{method: "GET", path: "/quotes/random?expand=author", header: map[string]string{"Accept": "application/xml"}, status: 200},
+		{method: "GET", path: "/quotes?expand=bogus", status: 400, code: "VALIDATION_FAILED"},
+		{method: "DELETE", path: "/quotes/abc", status: 400},
+		{method: "DELETE", path: "/quotes/{quote_id}", status: 204},
+		{method: "DELETE", path: "/quotes/{quote_id}", status: 404, code: "QUOTE_NOT_FOUND"},
+
+		{method: "POST", path: "/admin/keys", body: `{"name":"reader","scopes":["quotes:read"]}`, status: 201, save: map[string]string{"key_id": "id", "reader": "token"}},
+		{method: "POST", path: "/admin/keys", body: `{"name":"","scopes":["quotes:read"]}`, status: 400},
+		{method: "GET", path: "/admin/keys", status: 200},
+		{method: "GET", path: "/admin/keys", token: "reader", status: 403},
+		{method: "POST", path: "/quotes", token: "reader", body: `{"quote":"Текст","author":"Автор"}`, status: 403},
+		{method: "POST", path: "/admin/keys/{key_id}/rotate", status: 200},
+		{method: "POST", path: "/admin/keys/missing/rotate", status: 404},
+		{method: "DELETE", path: "/admin/keys/{key_id}", status: 204},
+		{method: "DELETE", path: "/admin/keys/{key_id}", status: 409, code: "KEY_REVOKED"},
+
+		{method: "POST", path: "/admin/tenants", body: `{"name":"acme","quota":{"max_quotes":10,"max_authors":5}}`, status: 201},
+		{method: "POST", path: "/admin/tenants", body: `{"name":"acme"}`, status: 409},
+		{method: "POST", path: "/admin/tenants", body: `{"name":"bad name!"}`, status: 400},
+		{method: "GET", path: "/admin/tenants", status: 200},
+		{method: "PUT", path: "/admin/tenants/acme/quota", body: `{"max_quotes":20,"max_authors":5}`, status: 200},
+		{method: "PUT", path: "/admin/tenants/acme/quota", body: `{"max_quotes":-1,"max_authors":5}`, status: 400},
+		{method: "PUT", path: "/admin/tenants/missing/quota", body: `{"max_quotes":1,"max_authors":1}`, status: 404},
+		{method: "DELETE", path: "/admin/tenants/default", status: 409, code: "DEFAULT_TENANT_PROTECTED"},
+		{method: "DELETE", path: "/admin/tenants/acme", status: 204},
+		{method: "DELETE", path: "/admin/tenants/acme", status: 404},
+
+		{method: "POST", path: "/admin/tenants", body: `{"name":"review"}`, status: 201},
+		{method: "POST", path: "/quotes", header: review, body: `{"quote":"Ждущий да дождётся","author":"Народ"}`, status: 201, save: map[string]string{"review_id": "id"}},
+		{method: "GET", path: "/quotes/random", header: review, status: 404, code: "NO_QUOTES"},
+		{method: "GET", path: "/moderation/queue", header: review, status: 200},
+		{method: "POST", path: "/moderation/quotes/{review_id}/reject", header: review, body: `{}`, status: 400, code: "VALIDATION_FAILED"},
+		{method: "POST", path: "/moderation/quotes/{review_id}/approve", header: review, status: 200},
+		{method: "POST", path: "/moderation/quotes/{review_id}/approve", header: review, status: 409, code: "QUOTE_NOT_PENDING"},
+		{method: "POST", path: "/moderation/quotes/abc/approve", header: review, status: 400},
+		{method: "POST", path: "/moderation/quotes/999/reject", header: review, body: `{"reason":"дубль"}`, status: 404, code: "QUOTE_NOT_FOUND"},
+		{method: "POST", path: "/quotes", header: review, body: `{"quote":"Тише едешь - дальше будешь","author":"Народ"}`, status: 201, save: map[string]string{"review_id": "id"}},
+		{method: "POST", path: "/moderation/quotes/{review_id}/reject", header: review, body: `{"reason":"уже есть в коллекции"}`, status: 200},
+
+		{method: "GET", path: "/audit?limit=10", status: 200},
+		{method: "GET", path: "/audit?author_id={jobs_id}", status: 200},
+		{method: "GET", path: "/audit?since=yesterday", status: 400},
+		{method: "POST", path: "/admin/reload", status: 200},
+	}
+}
+
+// TestOpenAPIRoutes - Каждый маршрут контроллера описан, и в описании нет маршрутов, которых нет в сервисе.
+func TestOpenAPIRoutes(t *testing.T) {
+	s := newContractServer(t)
+	spec := loadSpec(t, s)
+
+	var described []string
+	for path, item := range spec.Paths {
+		for method := range item {
+			described = append(described, strings.ToUpper(method)+" "+path)
+		}
+	}
+	sort.Strings(described)
+
+	routes := s.controller.Routes()
+	sort.Strings(routes)
+
+	if strings.Join(described, "\n") != strings.Join(routes, "\n") {
+		t.Errorf("маршруты расходятся с openapi.json\nв описании:\n%s\n\nв сервисе:\n%s", strings.Join(described, "\n"), strings.Join(routes, "\n"))
+	}
+}
+
+// TestOpenAPIContract - Статусы, типы содержимого и тела ответов совпадают с описанием.
+func TestOpenAPIContract(t *testing.T) {
+	s := newContractServer(t)
+	spec := loadSpec(t, s)
+
+	covered := make(map[string]bool)
+	vars := make(map[string]string)
+	for _, call := range contractScenario() {
+		name := call.method + " " + call.path
+		rec := s.do(t, call, vars)
+		if rec.Code != call.status {
+			t.Errorf("%s: статус %d, ожидался %d; тело: %s", name, rec.Code, call.status, rec.Body.String())
+			continue
+		}
+
+		path, op := spec.find(call.method, strings.SplitN(call.path, "?", 2)[0])
+		if op == nil {
+			t.Errorf("%s: операция не описана в openapi.json", name)
+			continue
+		}
+		covered[call.method+" "+path] = true
+
+		if err := spec.checkResponse(op, rec); err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if call.code != "" {
+			var problem struct {
+				Code string `json:"code"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil || problem.Code != call.code {
+				t.Errorf("%s: код ошибки %q, ожидался %q; тело: %s", name, problem.Code, call.code, rec.Body.String())
+			}
+		}
+
+		if len(call.save) > 0 {
+			var body map[string]any
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			for key, field := range call.save {
+				vars[key] = fmt.Sprint(body[field])
+			}
+		}
+	}
+
+	for path, item := range spec.Paths {
+		for method := range item {
+			if key := strings.ToUpper(method) + " " + path; !covered[key] {
+				t.Errorf("операция %s не проверяется контрактным тестом", key)
+			}
+		}
+	}
+}
+
+// TestAuthorAudit - Создание, изменение и объединение авторов попадают в журнал аудита с состоянием до и после.
+func TestAuthorAudit(t *testing.T) {
+	s := newContractServer(t)
+	vars := make(map[string]string)
+	for _, call := range []*contractCall{
+		{method: "POST", path: "/authors", body: `{"name":"Марк Твен","birth_year":1835}`, status: 201, save: map[string]string{"author_id": "id"}},
+		{method: "PUT", path: "/authors/{author_id}", body: `{"full_name":"Сэмюэл Клеменс","birth_year":1835,"death_year":1910}`, status: 200},
+		{method: "PUT", path: "/authors/{author_id}", body: `{"death_year":1800,"birth_year":1835}`, status: 400},
+	} {
+		rec := s.do(t, call, vars)
+		if rec.Code != call.status {
+			t.Fatalf("%s %s: статус %d, ожидался %d; тело: %s", call.method, call.path, rec.Code, call.status, rec.Body.String())
+		}
+		if len(call.save) > 0 {
+			var body map[string]any
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			for key, field := range call.save {
+				vars[key] = fmt.Sprint(body[field])
+			}
+		}
+	}
+
+	rec := s.do(t, &contractCall{method: "GET", path: "/audit?author_id={author_id}"}, vars)
+	var entries []*dto.AuditEntry
+	if err := json.Unmarshal(rec.Body.Bytes(), &entries); err != nil {
+		t.Fatalf("GET /audit: %v; тело: %s", err, rec.Body.String())
+	}
+	// Записи от новых к старым; отклонённое изменение в журнал не попадает.
+	if len(entries) != 2 {
+		t.Fatalf("записей об авторе %d, ожидалось 2: %s", len(entries), rec.Body.String())
+	}
+	update, create := entries[0], entries[1]
+	if create.Operation != audit.OpAuthorCreate || create.AuthorBefore != nil || create.AuthorAfter == nil || create.AuthorAfter.Name != "Марк Твен" {
+		t.Errorf("запись о создании: %+v", create)
+	}
+	if update.Operation != audit.OpAuthorUpdate || update.AuthorBefore == nil || update.AuthorAfter == nil ||
+		update.AuthorBefore.FullName != "" || update.AuthorAfter.FullName != "Сэмюэл Клеменс" {
+		t.Errorf("запись об изменении: %+v", update)
+	}
+	if update.Actor == "" || strconv.Itoa(update.AuthorID) != vars["author_id"] {
+		t.Errorf("исполнитель %q и автор %d, ожидались ключ и автор %s", update.Actor, update.AuthorID, vars["author_id"])
+	}
+
+	for _, call := range []*contractCall{
+		{method: "POST", path: "/authors", body: `{"name":"Твен","aliases":["Mark Twain"]}`, status: 201, save: map[string]string{"twin_id": "id"}},
+		{method: "POST", path: "/quotes", body: `{"quote":"Слухи о моей смерти сильно преувеличены","author_id":{twin_id}}`, status: 201},
+		{method: "POST", path: "/authors/{twin_id}/merge", body: `{"into":{author_id}}`, status: 200},
+	} {
+		rec := s.do(t, call, vars)
+		if rec.Code != call.status {
+			t.Fatalf("%s %s: статус %d, ожидался %d; тело: %s", call.method, call.path, rec.Code, call.status, rec.Body.String())
+		}
+		if len(call.save) > 0 {
+			var body map[string]any
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			for key, field := range call.save {
+				vars[key] = fmt.Sprint(body[field])
+			}
+		}
+	}
+
+	// Удалённого автора запись об объединении находит по merged_from.
+	rec = s.do(t, &contractCall{method: "GET", path: "/audit?operation=author.merge&author_id={twin_id}"}, vars)
+	entries = nil
+	if err := json.Unmarshal(rec.Body.Bytes(), &entries); err != nil || len(entries) != 1 {
+		t.Fatalf("GET /audit: записей об объединении %d, ошибка %v; тело: %s", len(entries), err, rec.Body.String())
+	}
+	merge := entries[0]
+	if strconv.Itoa(merge.AuthorID) != vars["author_id"] || merge.MergedFrom == nil || merge.MergedFrom.Name != "Твен" ||
+		merge.AuthorBefore == nil || len(merge.AuthorBefore.Aliases) != 0 || merge.AuthorAfter == nil {
+		t.Errorf("запись об объединении: %+v", merge)
+	}
+	if want := []string{"Твен", "Mark Twain"}; !slices.Equal(merge.MovedAliases, want) {
+		t.Errorf("moved_aliases = %v, ожидалось %v", merge.MovedAliases, want)
+	}
+}
+
+// TestMalformedYAMLBody - Некорректный YAML в теле быстро отклоняется ответом 400 в формате RFC 7807,
+// а не зацикливает разбор и не расходует память без предела.
+func TestMalformedYAMLBody(t *testing.T) {
+	s := newContractServer(t)
+	spec := loadSpec(t, s)
+	_, op := spec.find("POST", "/quotes")
+
+	bodies := []string{
+		"[}",
+		"quote: [}",
+		"quote: {b: [}",
+		"quote: {a: [b, {c: ]}",
+		"quote: [[1] [2]]",
+		strings.Repeat("[", 500),
+		strings.Repeat("- ", 500) + "x",
+	}
+	for _, body := range bodies {
+		call := &contractCall{method: "POST", path: "/quotes", header: map[string]string{"Content-Type": "application/yaml"}, body: body}
+		done := make(chan *httptest.ResponseRecorder, 1)
+		go func() { done <- s.do(t, call, nil) }()
+
+		var rec *httptest.ResponseRecorder
+		select {
+		case rec = <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("тело %.40q: нет ответа за 5 секунд", body)
+		}
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("тело %.40q: статус %d, ожидался 400; тело: %s", body, rec.Code, rec.Body.String())
+			continue
+		}
+		if err := spec.checkResponse(op, rec); err != nil {
+			t.Errorf("тело %.40q: %v", body, err)
+		}
+		var problem struct {
+			Code string `json:"code"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil || problem.Code != "MALFORMED_BODY" {
+			t.Errorf("тело %.40q: код ошибки %q, ожидался MALFORMED_BODY", body, problem.Code)
+		}
+	}
+}
+
+// openAPI - Часть описания, которая нужна для проверки ответов.
+type openAPI struct {
+	Paths      map[string]map[string]*openAPIOperation `json:"paths"`
+	Components struct {
+		Schemas   map[string]json.RawMessage  `json:"schemas"`
+		Responses map[string]*openAPIResponse `json:"responses"`
+	} `json:"components"`
+}
+
+type openAPIOperation struct {
+	Responses map[string]*openAPIResponse `json:"responses"`
+}
+
+type openAPIResponse struct {
+	Ref     string `json:"$ref"`
+	Content map[string]struct {
+		Schema json.RawMessage `json:"schema"`
+	} `json:"content"`
+}
+
+// schema - Подмножество JSON Schema, которое используется в openapi.json.
+type schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 json.RawMessage    `json:"type"`
+	Format               string             `json:"format"`
+	Enum                 []any              `json:"enum"`
+	Required             []string           `json:"required"`
+	Properties           map[string]*schema `json:"properties"`
+	AdditionalProperties *bool              `json:"additionalProperties"`
+	Items                *schema            `json:"items"`
+	Minimum              *float64           `json:"minimum"`
+}
+
+func loadSpec(t *testing.T, s *contractServer) *openAPI {
+	t.Helper()
+
+	rec := s.do(t, &contractCall{method: "GET", path: "/openapi.json", noAuth: true}, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /openapi.json: статус %d", rec.Code)
+	}
+	var spec openAPI
+	if err := json.Unmarshal(rec.Body.Bytes(), &spec); err != nil {
+		t.Fatalf("openapi.json: %v", err)
+	}
+	return &spec
+}
+
+// find - Операция для пути запроса: /quotes/5 соответствует /quotes/{id}. Точное совпадение важнее шаблона.
+func (spec *openAPI) find(method, path string) (string, *openAPIOperation) {
+	method = strings.ToLower(method)
+	if op := spec.Paths[path][method]; op != nil {
+		return path, op
+	}
+	for template, item := range spec.Paths {
+		pattern := "^" + regexp.MustCompile(`\\\{[^/]+\\\}`).ReplaceAllString(regexp.QuoteMeta(template), `[^/]+`) + "$"
+		if op := item[method]; op != nil && regexp.MustCompile(pattern).MatchString(path) {
+			return template, op
+		}
+	}
+	return "", nil
+}
+
+func (spec *openAPI) checkResponse(op *openAPIOperation, rec *httptest.ResponseRecorder) error {
+	resp := op.Responses[strconv.Itoa(rec.Code)]
+	if resp == nil {
+		return fmt.Errorf("статус %d не описан", rec.Code)
+	}
+	if resp.Ref != "" {
+		resp = spec.Components.Responses[strings.TrimPrefix(resp.Ref, "#/components/responses/")]
+	}
+
+	if len(resp.Content) == 0 {
+		if rec.Body.Len() > 0 {
+			return fmt.Errorf("статус %d описан без тела, получено: %s", rec.Code, rec.Body.String())
+		}
+		return nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(rec.Header().Get("Content-Type"))
+	if err != nil {
+		return fmt.Errorf("Content-Type %q: %w", rec.Header().Get("Content-Type"), err)
+	}
+	content, ok := resp.Content[mediaType]
+	if !ok {
+		return fmt.Errorf("Content-Type %s не описан для статуса %d", mediaType, rec.Code)
+	}
+	if mediaType != "application/json" && mediaType != "application/problem+json" {
+		return nil
+	}
+
+	var s schema
+	if err := json.Unmarshal(content.Schema, &s); err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(rec.Body.Bytes()))
+	dec.UseNumber()
+	var body any
+	if err := dec.Decode(&body); err != nil {
+		return fmt.Errorf("тело не JSON: %w", err)
+	}
+	return spec.validate(&s, body, "$")
+}
+
+func (spec *openAPI) resolve(s *schema) (*schema, error) {
+	for s.Ref != "" {
+		raw, ok := spec.Components.Schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]
+		if !ok {
+			return nil, fmt.Errorf("схема %s не найдена", s.Ref)
+		}
+		s = new(schema)
+		if err := json.Unmarshal(raw, s); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// validate - Проверяет значение по схеме. Лишние поля при additionalProperties: false - тоже расхождение.
+func (spec *openAPI) validate(s *schema, value any, at string) error {
+	s, err := spec.resolve(s)
+	if err != nil {
+		return err
+	}
+
+	if len(s.Type) > 0 {
+		var types []string
+		if err := json.Unmarshal(s.Type, &types); err != nil {
+			var single string
+			if err := json.Unmarshal(s.Type, &single); err != nil {
+				return fmt.Errorf("%s: некорректный type в схеме", at)
+			}
+			types = []string{single}
+		}
+		if !matchesType(types, value) {
+			return fmt.Errorf("%s: ожидается %s, получено %T (%v)", at, strings.Join(types, " или "), value, value)
+		}
+	}
+
+	if len(s.Enum) > 0 {
+		found := false
+		for _, item := range s.Enum {
+			if fmt.Sprint(item) == fmt.Sprint(value) {
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s: значение %v не входит в %v", at, value, s.Enum)
+		}
+	}
+
+	switch v := value.(type) {
+	case string:
+		if s.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339Nano, v); err != nil {
+				return fmt.Errorf("%s: ожидается date-time, получено %q", at, v)
+			}
+		}
+	case json.Number:
+		if s.Minimum != nil {
+			if n, _ := v.Float64(); n < *s.Minimum {
+				return fmt.Errorf("%s: %v меньше минимума %v", at, v, *s.Minimum)
+			}
+		}
+	case map[string]any:
+		for _, key := range s.Required {
+			if _, ok := v[key]; !ok {
+				return fmt.Errorf("%s: нет обязательного поля %q", at, key)
+			}
+		}
+		for key, item := range v {
+			prop, ok := s.Properties[key]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					return fmt.Errorf("%s: поле %q не описано в схеме", at, key)
+				}
+				continue
+			}
+			if err := spec.validate(prop, item, at+"."+key); err != nil {
+				return err
+			}
+		}
+	case []any:
+		if s.Items != nil {
+			for i, item := range v {
+				if err := spec.validate(s.Items, item, fmt.Sprintf("%s[%d]", at, i)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func matchesType(types []string, value any) bool {
+	for _, t := range types {
+		switch v := value.(type) {
+		case nil:
+			if t == "null" {
+				return true
+			}
+		case bool:
+			if t == "boolean" {
+				return true
+			}
+		case string:
+			if t == "string" {
+				return true
+			}
+		case json.Number:
+			if t == "number" {
+				return true
+			}
+			if _, err := v.Int64(); t == "integer" && err == nil {
+				return true
+			}
+		case map[string]any:
+			if t == "object" {
+				return true
+			}
+		case []any:
+			if t == "array" {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package i18n_test
+
+import (
+	"errors"
+	"go-offline-test/internal/auth"
+	"go-offline-test/internal/i18n"
+	"go-offline-test/internal/services"
+	"go-offline-test/internal/shared/dto"
+	"reflect"
+	"regexp"
+	"slices"
+	"testing"
+)
+
+// TestErrorCodesTranslated - У каждого кода ошибки есть заголовок на каждом языке.
+func TestErrorCodesTranslated(t *testing.T) {
+	for _, lang := range i18n.Languages() {
+		for _, code := range dto.ErrorCodes() {
+			if !i18n.Has(lang, "code."+string(code)) {
+				t.Errorf("%s: нет перевода для кода %s", lang, code)
+			}
+		}
+	}
+}
+
+// TestSentinelErrorsTranslated - Сообщения всех ошибок-образцов есть на каждом языке. Образцы
+// берутся из реестра i18n, поэтому новая ошибка в services или auth проверяется без правки теста.
+func TestSentinelErrorsTranslated(t *testing.T) {
+	sentinels := i18n.Sentinels()
+	// Пакеты с образцами импортированы тестом, значит их образцы уже в реестре.
+	for _, want := range []*i18n.Error{services.ErrQuoteNotPending, services.ErrMergeTargetNotFound, auth.ErrInvalidAudience} {
+		if !slices.Contains(sentinels, want) {
+			t.Fatalf("%s: образца нет в i18n.Sentinels()", want.Key)
+		}
+	}
+	for _, msg := range sentinels {
+		for _, lang := range i18n.Languages() {
+			if !i18n.Has(lang, msg.Key) {
+				t.Errorf("%s: нет перевода для %s", lang, msg.Key)
+			}
+		}
+	}
+}
+
+var verbs = regexp.MustCompile(`%[-+# 0]*[0-9]*(?:\.[0-9]+)?[a-zA-Z%]`)
+
+// TestCatalogsMatch - Во всех каталогах одинаковые ключи и одинаковые параметры в сообщениях,
+// иначе перевод с подстановкой напечатает %!(EXTRA ...) или %!d(MISSING).
+func TestCatalogsMatch(t *testing.T) {
+	want := i18n.Keys(i18n.Default)
+	for _, lang := range i18n.Languages()[1:] {
+		if got := i18n.Keys(lang); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: ключи каталога расходятся с %s:\n%v\n%v", lang, i18n.Default, got, want)
+		}
+		for _, key := range want {
+			base := verbs.FindAllString(i18n.T([]i18n.Lang{i18n.Default}, key), -1)
+			translated := verbs.FindAllString(i18n.T([]i18n.Lang{lang}, key), -1)
+			if !reflect.DeepEqual(base, translated) {
+				t.Errorf("%s: %s: параметры %v, в %s - %v", lang, key, translated, i18n.Default, base)
+			}
+		}
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		header string
+		want   []i18n.Lang
+	}{
+		{"", []i18n.Lang{i18n.RU}},
+		{"en", []i18n.Lang{i18n.EN, i18n.RU}},
+		{"en-US,en;q=0.9", []i18n.Lang{i18n.EN, i18n.RU}},
+		{"de-DE, en;q=0.5, ru;q=0.8", []i18n.Lang{i18n.RU, i18n.EN}},
+		{"fr, *;q=0.1", []i18n.Lang{i18n.RU}},
+		{"en;q=0, ru", []i18n.Lang{i18n.RU}},
+	}
+	for _, tt := range tests {
+		if got := i18n.Negotiate(tt.header); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Negotiate(%q) = %v, ожидалось %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestLocalize(t *testing.T) {
+	en := i18n.Negotiate("en")
+
+	if got := i18n.Localize(services.NewErrInvalidField("author", "validation.author_too_short", 2), en); got != "author name is too short (minimum 2 characters)" {
+		t.Errorf("параметры: %q", got)
+	}
+	if got := i18n.Localize(i18n.Errorf("auth.scope_required", auth.ErrForbidden, "admin"), en); got != "insufficient permissions: admin is required" {
+		t.Errorf("вложенная ошибка: %q", got)
+	}
+	wrapped := errors.Join(services.ErrQuotaExceeded)
+	if got := i18n.Localize(wrapped, en); got != "tenant quota exceeded" {
+		t.Errorf("обёртка: %q", got)
+	}
+	if !errors.Is(services.ErrQuotaExceeded.With("acme"), services.ErrQuotaExceeded) {
+		t.Error("With: errors.Is с образцом должен быть истинным")
+	}
+}
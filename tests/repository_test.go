@@ -3,8 +3,9 @@ package repository_test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"go-offline-test/internal/models"
 	"go-offline-test/internal/repository"
-	"go-offline-test/internal/shared/dto"
 	"sync"
 	"testing"
 )
@@ -14,7 +15,7 @@ func TestAddQuote(t *testing.T) {
 	ctx := context.Background()
 
 	t.Run("Успешное добавление цитаты", func(t *testing.T) {
-		quote := &dto.Quote{AuthorName: "Test Author", Text: "Test Quote"}
+		quote := &models.Quote{AuthorName: "Test Author", Text: "Test Quote"}
 		err := qr.AddQuote(ctx, quote)
 		if err != nil {
 			t.Fatalf("AddQuote() error = %v, want nil", err)
@@ -27,7 +28,7 @@ func TestAddQuote(t *testing.T) {
 	t.Run("Добавление с отменённым контекстом", func(t *testing.T) {
 		canceledCtx, cancel := context.WithCancel(ctx)
 		cancel()
-		err := qr.AddQuote(canceledCtx, &dto.Quote{})
+		err := qr.AddQuote(canceledCtx, &models.Quote{})
 		if !errors.Is(err, context.Canceled) {
 			t.Errorf("AddQuote() error = %v, want %v", err, context.Canceled)
 		}
@@ -46,7 +47,7 @@ func TestQuotes(t *testing.T) {
 	})
 
 	t.Run("Успешное получение", func(t *testing.T) {
-		qr.AddQuote(ctx, &dto.Quote{AuthorName: "Author", Text: "Quote"})
+		qr.AddQuote(ctx, &models.Quote{AuthorName: "Author", Text: "Quote"})
 		quotes, err := qr.Quotes(ctx)
 		if err != nil {
 			t.Fatalf("Quotes() error = %v, want nil", err)
@@ -69,8 +70,8 @@ func TestRandomQuote(t *testing.T) {
 	})
 
 	t.Run("Успешное получение", func(t *testing.T) {
-		qr.AddQuote(ctx, &dto.Quote{AuthorName: "Author", Text: "Quote 1"})
-		qr.AddQuote(ctx, &dto.Quote{AuthorName: "Author", Text: "Quote 2"})
+		qr.AddQuote(ctx, &models.Quote{AuthorName: "Author", Text: "Quote 1"})
+		qr.AddQuote(ctx, &models.Quote{AuthorName: "Author", Text: "Quote 2"})
 		quote, err := qr.RandomQuote(ctx)
 		if err != nil {
 			t.Fatalf("RandomQuote() error = %v, want nil", err)
@@ -93,7 +94,7 @@ func TestQuotesByAuthor(t *testing.T) {
 	})
 
 	t.Run("Успешное получение", func(t *testing.T) {
-		qr.AddQuote(ctx, &dto.Quote{AuthorName: "Author", Text: "Quote"})
+		qr.AddQuote(ctx, &models.Quote{AuthorName: "Author", Text: "Quote"})
 		quotes, err := qr.QuotesByAuthor(ctx, "Author")
 		if err != nil {
 			t.Fatalf("QuotesByAuthor() error = %v, want nil", err)
@@ -109,18 +110,21 @@ func TestDeleteQuote(t *testing.T) {
 	ctx := context.Background()
 
 	t.Run("Несуществующая цитата", func(t *testing.T) {
-		err := qr.DeleteQuote(ctx, 999)
+		_, err := qr.DeleteQuote(ctx, 999)
 		if !errors.Is(err, repository.ErrQuoteNotFound) {
 			t.Errorf("DeleteQuote() error = %v, want %v", err, repository.ErrQuoteNotFound)
 		}
 	})
 
 	t.Run("Успешное удаление", func(t *testing.T) {
-		qr.AddQuote(ctx, &dto.Quote{AuthorName: "Author", Text: "Quote"})
-		err := qr.DeleteQuote(ctx, 1)
+		qr.AddQuote(ctx, &models.Quote{AuthorName: "Author", Text: "Quote"})
+		deleted, err := qr.DeleteQuote(ctx, 1)
 		if err != nil {
 			t.Fatalf("DeleteQuote() error = %v, want nil", err)
 		}
+		if deleted.ID != 1 || deleted.Text != "Quote" {
+			t.Errorf("DeleteQuote() = %+v, want deleted quote 1", deleted)
+		}
 	})
 }
 
@@ -133,10 +137,10 @@ func TestConcurrentAccess(t *testing.T) {
 	wg.Add(goroutines)
 
 	for i := 0; i < goroutines; i++ {
-		go func() {
+		go func(i int) {
 			defer wg.Done()
-			_ = qr.AddQuote(ctx, &dto.Quote{AuthorName: "Concurrent", Text: "Quote"})
-		}()
+			_ = qr.AddQuote(ctx, &models.Quote{AuthorName: "Concurrent", Text: fmt.Sprintf("Quote %d", i)})
+		}(i)
 	}
 
 	wg.Wait()
@@ -149,3 +153,36 @@ func TestConcurrentAccess(t *testing.T) {
 		t.Errorf("len(quotes) = %d, want %d", len(quotes), goroutines)
 	}
 }
+
+func TestConcurrentDuplicate(t *testing.T) {
+	qr := repository.NewQuoteRepository()
+	ctx := context.Background()
+	const goroutines = 100
+
+	var wg sync.WaitGroup
+	errs := make(chan error, goroutines)
+	wg.Add(goroutines)
+
+	for i := 0; i < goroutines; i++ {
+		go func() {
+			defer wg.Done()
+			errs <- qr.AddQuote(ctx, &models.Quote{AuthorName: "Concurrent", Text: "Quote"})
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	added := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			added++
+		case !errors.Is(err, repository.ErrQuoteAlreadyExist):
+			t.Errorf("AddQuote() error = %v, want %v", err, repository.ErrQuoteAlreadyExist)
+		}
+	}
+	if added != 1 {
+		t.Errorf("успешных добавлений %d, want 1", added)
+	}
+}